@@ -0,0 +1,386 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"sort"
+	"strings"
+
+	"github.com/ellescotz/graph_backend/pkg/core"
+)
+
+// analysis runs an analysis on a graph, stopping its searches with ctx.
+// If the searches are truncated, it returns the partial result with a *core.TruncatedError.
+type analysis func(ctx context.Context, g *core.Graph) (result, error)
+
+// command represents a command of graphctl.
+type command struct {
+	summary string
+	setUp   func(flags *flag.FlagSet) analysis // defines the flags of the command and returns its analysis
+}
+
+// commands are the commands of graphctl by name, named after the corresponding endpoints of the server.
+var commands = map[string]command{
+	"maxSteps": {
+		summary: "paths with at most or exactly a number of edges",
+		setUp:   setUpMaxSteps,
+	},
+	"maxWeight": {
+		summary: "paths with at most or exactly a sum weight",
+		setUp:   setUpMaxWeight,
+	},
+	"highLowWeight": {
+		summary: "lowest or highest weighted paths",
+		setUp:   setUpHighLowWeight,
+	},
+	"shortLong": {
+		summary: "shortest or longest paths by number of edges",
+		setUp:   setUpShortLong,
+	},
+	"bellmanFord": {
+		summary: "lowest weighted paths, negative weights allowed",
+		setUp:   setUpBellmanFord,
+	},
+	"kShortest": {
+		summary: "the K lowest weighted paths in order",
+		setUp:   setUpKShortest,
+	},
+	"aStar": {
+		summary: "lowest weighted path guided by node positions",
+		setUp:   setUpAStar,
+	},
+	"maxFlow": {
+		summary: "maximum flow and minimum cut, edge weights being capacities",
+		setUp:   setUpMaxFlow,
+	},
+	"allPairs": {
+		summary: "lowest weights between every pair of nodes",
+		setUp:   setUpAllPairs,
+	},
+	"spanningTree": {
+		summary: "minimum spanning forest or arborescence",
+		setUp:   setUpSpanningTree,
+	},
+	"components": {
+		summary: "strongly and weakly connected components",
+		setUp:   setUpComponents,
+	},
+	"cycles": {
+		summary: "every elementary cycle",
+		setUp:   setUpCycles,
+	},
+	"findCycle": {
+		summary: "whether the graph has a cycle, with one cycle if there is any",
+		setUp:   setUpFindCycle,
+	},
+}
+
+// endNodeFlags defines the -from and -to flags on flags.
+// It returns a function looking up the nodes in a graph.
+func endNodeFlags(flags *flag.FlagSet) func(g *core.Graph) (initialNode, endNode core.Node, err error) {
+	var (
+		from = flags.String("from", "", "initial node (required)")
+		to   = flags.String("to", "", "end node (required)")
+	)
+
+	return func(g *core.Graph) (initialNode, endNode core.Node, err error) {
+		initialNode.Name, endNode.Name = *from, *to
+
+		if len(initialNode.Name) == 0 || len(endNode.Name) == 0 {
+			return initialNode, endNode, usageErrorf("-from and -to are required")
+		}
+
+		for i, n := range []core.Node{initialNode, endNode} {
+			if !g.HasNode(n) {
+				return initialNode, endNode, &core.UnknownNodeError{Node: n, Field: []string{"from", "to"}[i]}
+			}
+		}
+
+		return initialNode, endNode, nil
+	}
+}
+
+// requireFlags returns a usageError if any of the flags named names is not set.
+func requireFlags(flags *flag.FlagSet, names ...string) error {
+	set := make(map[string]bool)
+
+	flags.Visit(func(f *flag.Flag) {
+		set[f.Name] = true
+	})
+
+	for _, name := range names {
+		if !set[name] {
+			return usageErrorf("-%v is required", name)
+		}
+	}
+
+	return nil
+}
+
+// setUpMaxSteps sets up the maxSteps command, calling GeneratePathsWithMaxSteps.
+func setUpMaxSteps(flags *flag.FlagSet) analysis {
+	var (
+		endNodes = endNodeFlags(flags)
+		maxEdges = flags.Int("max-edges", 0, "(maximum) number of edges of the paths (required)")
+		exact    = flags.Bool("exact", false, "exactly -max-edges edges instead of at most")
+	)
+
+	return func(ctx context.Context, g *core.Graph) (result, error) {
+		initialNode, endNode, err := endNodes(g)
+		if err != nil {
+			return nil, err
+		}
+
+		if err = requireFlags(flags, "max-edges"); err != nil {
+			return nil, err
+		}
+
+		paths, err := g.GeneratePathsWithMaxSteps(ctx, initialNode, endNode, *maxEdges, *exact)
+
+		return &pathsResult{graph: g, paths: paths}, err
+	}
+}
+
+// setUpMaxWeight sets up the maxWeight command, calling GeneratePathsWithMaxWeight.
+func setUpMaxWeight(flags *flag.FlagSet) analysis {
+	var (
+		endNodes  = endNodeFlags(flags)
+		maxWeight = flags.Float64("max-weight", 0, "(maximum) sum weight of the paths (required)")
+		exact     = flags.Bool("exact", false, "exactly -max-weight sum weight instead of at most")
+	)
+
+	return func(ctx context.Context, g *core.Graph) (result, error) {
+		initialNode, endNode, err := endNodes(g)
+		if err != nil {
+			return nil, err
+		}
+
+		if err = requireFlags(flags, "max-weight"); err != nil {
+			return nil, err
+		}
+
+		paths, err := g.GeneratePathsWithMaxWeight(ctx, initialNode, endNode, *maxWeight, *exact)
+
+		return &pathsResult{graph: g, paths: paths}, err
+	}
+}
+
+// setUpHighLowWeight sets up the highLowWeight command, calling GenerateLowestHighestWeightPathWithStrategy.
+func setUpHighLowWeight(flags *flag.FlagSet) analysis {
+	var (
+		endNodes = endNodeFlags(flags)
+		lowest   = flags.Bool("lowest", true, "lowest weighted paths, highest weighted ones if false")
+	)
+
+	return func(ctx context.Context, g *core.Graph) (result, error) {
+		initialNode, endNode, err := endNodes(g)
+		if err != nil {
+			return nil, err
+		}
+
+		paths, strategy, err := g.GenerateLowestHighestWeightPathWithStrategy(ctx, initialNode, endNode, *lowest)
+
+		return &pathsResult{graph: g, paths: paths, strategy: strategy}, err
+	}
+}
+
+// setUpShortLong sets up the shortLong command, calling GenerateShortestLongestPathWithStrategy.
+func setUpShortLong(flags *flag.FlagSet) analysis {
+	var (
+		endNodes = endNodeFlags(flags)
+		shortest = flags.Bool("shortest", true, "shortest paths, longest ones if false")
+	)
+
+	return func(ctx context.Context, g *core.Graph) (result, error) {
+		initialNode, endNode, err := endNodes(g)
+		if err != nil {
+			return nil, err
+		}
+
+		paths, strategy, err := g.GenerateShortestLongestPathWithStrategy(ctx, initialNode, endNode, *shortest)
+
+		return &pathsResult{graph: g, paths: paths, strategy: strategy}, err
+	}
+}
+
+// setUpBellmanFord sets up the bellmanFord command, calling GenerateLowestWeightPathsBellmanFord.
+func setUpBellmanFord(flags *flag.FlagSet) analysis {
+	endNodes := endNodeFlags(flags)
+
+	return func(ctx context.Context, g *core.Graph) (result, error) {
+		initialNode, endNode, err := endNodes(g)
+		if err != nil {
+			return nil, err
+		}
+
+		paths, err := g.GenerateLowestWeightPathsBellmanFord(ctx, initialNode, endNode)
+		if err != nil && !truncated(err) {
+			return nil, err
+		}
+
+		return &pathsResult{graph: g, paths: paths}, err
+	}
+}
+
+// setUpKShortest sets up the kShortest command, calling GenerateKShortestPaths.
+func setUpKShortest(flags *flag.FlagSet) analysis {
+	var (
+		endNodes = endNodeFlags(flags)
+		k        = flags.Int("k", 1, "number of paths")
+	)
+
+	return func(ctx context.Context, g *core.Graph) (result, error) {
+		initialNode, endNode, err := endNodes(g)
+		if err != nil {
+			return nil, err
+		}
+
+		paths, err := g.GenerateKShortestPaths(ctx, initialNode, endNode, *k)
+		if err != nil && !truncated(err) {
+			return nil, err
+		}
+
+		return &pathsResult{graph: g, paths: paths}, err
+	}
+}
+
+// setUpAStar sets up the aStar command, calling GenerateAStarPath.
+func setUpAStar(flags *flag.FlagSet) analysis {
+	var (
+		endNodes = endNodeFlags(flags)
+		name     = flags.String("heuristic", "euclidean", "heuristic using the node positions: "+heuristicNames())
+	)
+
+	return func(ctx context.Context, g *core.Graph) (result, error) {
+		initialNode, endNode, err := endNodes(g)
+		if err != nil {
+			return nil, err
+		}
+
+		heuristic, ok := core.Heuristics[*name]
+		if !ok {
+			return nil, usageErrorf("unknown heuristic %q", *name)
+		}
+
+		paths, err := g.GenerateAStarPath(ctx, initialNode, endNode, heuristic)
+		if err != nil && !truncated(err) {
+			return nil, err
+		}
+
+		return &pathsResult{graph: g, paths: paths}, err
+	}
+}
+
+// heuristicNames returns the sorted names of the built-in heuristics separated by commas.
+func heuristicNames() string {
+	names := make([]string, 0, len(core.Heuristics))
+
+	for name := range core.Heuristics {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return strings.Join(names, ", ")
+}
+
+// setUpMaxFlow sets up the maxFlow command, calling MaxFlow.
+func setUpMaxFlow(flags *flag.FlagSet) analysis {
+	endNodes := endNodeFlags(flags)
+
+	return func(ctx context.Context, g *core.Graph) (result, error) {
+		initialNode, endNode, err := endNodes(g)
+		if err != nil {
+			return nil, err
+		}
+
+		flow, err := g.MaxFlow(initialNode, endNode)
+		if err != nil {
+			return nil, err
+		}
+
+		return &flowResult{graph: g, flow: flow}, nil
+	}
+}
+
+// setUpAllPairs sets up the allPairs command, calling GenerateAllPairsPaths.
+func setUpAllPairs(flags *flag.FlagSet) analysis {
+	return func(ctx context.Context, g *core.Graph) (result, error) {
+		allPairs, err := g.GenerateAllPairsPaths(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		return &allPairsResult{allPairs: allPairs}, nil
+	}
+}
+
+// setUpSpanningTree sets up the spanningTree command,
+// calling MinimumSpanningForestKruskal, MinimumSpanningForestPrim or MinimumArborescence.
+func setUpSpanningTree(flags *flag.FlagSet) analysis {
+	var (
+		algorithm = flags.String("algorithm", "kruskal", "kruskal or prim for undirected forests, edmonds for a directed arborescence")
+		root      = flags.String("root", "", "root node of the arborescence, for edmonds only")
+	)
+
+	return func(ctx context.Context, g *core.Graph) (result, error) {
+		var tree core.SpanningTree
+
+		switch *algorithm {
+		case "kruskal":
+			tree = g.MinimumSpanningForestKruskal()
+		case "prim":
+			tree = g.MinimumSpanningForestPrim()
+		case "edmonds":
+			rootNode := core.Node{Name: *root}
+
+			if len(rootNode.Name) == 0 {
+				return nil, usageErrorf("-root is required for edmonds")
+			}
+
+			if !g.HasNode(rootNode) {
+				return nil, &core.UnknownNodeError{Node: rootNode, Field: "root"}
+			}
+
+			tree = g.MinimumArborescence(rootNode)
+		default:
+			return nil, usageErrorf("unknown algorithm %q", *algorithm)
+		}
+
+		return &treeResult{graph: g, tree: tree}, nil
+	}
+}
+
+// setUpComponents sets up the components command, calling Components.
+func setUpComponents(flags *flag.FlagSet) analysis {
+	return func(ctx context.Context, g *core.Graph) (result, error) {
+		return &componentsResult{graph: g, components: g.Components()}, nil
+	}
+}
+
+// setUpCycles sets up the cycles command, calling GenerateCycles.
+func setUpCycles(flags *flag.FlagSet) analysis {
+	var (
+		maxEdges  = flags.Int("max-edges", -1, "maximum number of edges of the cycles, -1 if unlimited")
+		maxWeight = flags.Float64("max-weight", -1, "maximum sum weight of the cycles, -1 if unlimited")
+	)
+
+	return func(ctx context.Context, g *core.Graph) (result, error) {
+		if *maxEdges == 0 || *maxEdges < -1 {
+			return nil, usageErrorf("-max-edges has to be positive or -1")
+		}
+
+		cycles, err := g.GenerateCycles(ctx, *maxEdges, *maxWeight)
+
+		return &pathsResult{graph: g, paths: cycles}, err
+	}
+}
+
+// setUpFindCycle sets up the findCycle command, calling FindCycle.
+func setUpFindCycle(flags *flag.FlagSet) analysis {
+	return func(ctx context.Context, g *core.Graph) (result, error) {
+		cycle, ok := g.FindCycle()
+
+		return &cycleResult{graph: g, cycle: cycle, cyclic: ok}, nil
+	}
+}
@@ -0,0 +1,300 @@
+// Command graphctl runs the analyses of the graph backend on a graph read from a file or the standard input,
+// without the HTTP server.
+//
+// Usage:
+//
+//	graphctl <command> [flags] [file]
+//
+// The graph is read from file, or from the standard input if file is missing or "-".
+// Its format is given by the -in flag, or by the extension of file, JSON by default.
+// Results are written to the standard output as a table, JSON or DOT, as given by the -out flag.
+// Searches can be limited by the -timeout, -max-states and -max-paths flags,
+// the partial results found until then are written with exit code 3.
+// Path enumerations are split among the goroutines given by the -workers flag.
+// Run "graphctl help" for the list of commands, and "graphctl <command> -h" for their flags.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ellescotz/graph_backend/pkg/core"
+	"github.com/ellescotz/graph_backend/pkg/dot"
+	"github.com/ellescotz/graph_backend/pkg/interchange"
+)
+
+// Exit codes.
+const (
+	exitOK        int = 0
+	exitError     int = 1 // failing analysis or unreadable graph
+	exitUsage     int = 2 // wrong command or flags
+	exitTruncated int = 3 // partial result, the analysis was stopped by its limits
+)
+
+// Output formats.
+const (
+	outputTable string = "table"
+	outputJSON  string = "json"
+	outputDOT   string = "dot"
+)
+
+// graphReaders read graphs by input format.
+var graphReaders = map[string]func(r io.Reader) (core.Graph, error){
+	"json": func(r io.Reader) (core.Graph, error) {
+		var graph core.Graph
+
+		err := json.NewDecoder(r).Decode(&graph)
+
+		return graph, err
+	},
+	"dot":     dot.Parse,
+	"graphml": interchange.ReadGraphML,
+	"gexf":    interchange.ReadGEXF,
+}
+
+// csvSeparators are the separators of delimiter-separated input formats.
+var csvSeparators = map[string]rune{
+	"csv": interchange.CSVSeparator,
+	"tsv": interchange.TSVSeparator,
+}
+
+// extensionFormats are the input formats by file extension.
+var extensionFormats = map[string]string{
+	".json":    "json",
+	".dot":     "dot",
+	".gv":      "dot",
+	".graphml": "graphml",
+	".gexf":    "gexf",
+	".csv":     "csv",
+	".tsv":     "tsv",
+}
+
+// usageError represents wrong command line arguments.
+type usageError struct {
+	message string
+}
+
+// Error returns the message of the error.
+func (e *usageError) Error() string {
+	return e.message
+}
+
+// usageErrorf returns a usageError with the formatted message.
+func usageErrorf(format string, args ...interface{}) error {
+	return &usageError{message: fmt.Sprintf(format, args...)}
+}
+
+// readGraph reads the graph of path, or of stdin if path is empty or "-", in format.
+// If format is empty, it is taken from the extension of path, JSON by default.
+// Delimiter-separated graphs hold an edge list or an adjacency matrix as given by csvLayout.
+func readGraph(path, format, csvLayout string, stdin io.Reader) (core.Graph, error) {
+	if len(format) == 0 {
+		format = "json"
+
+		if extensionFormat, ok := extensionFormats[strings.ToLower(filepath.Ext(path))]; ok {
+			format = extensionFormat
+		}
+	}
+
+	r := stdin
+
+	if len(path) != 0 && path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return core.Graph{}, err
+		}
+		defer file.Close()
+
+		r = file
+	}
+
+	if separator, ok := csvSeparators[format]; ok {
+		switch csvLayout {
+		case "edges":
+			return interchange.ReadEdgeList(r, separator)
+		case "matrix":
+			return interchange.ReadAdjacencyMatrix(r, separator)
+		default:
+			return core.Graph{}, usageErrorf("unknown CSV layout %q", csvLayout)
+		}
+	}
+
+	read, ok := graphReaders[format]
+	if !ok {
+		return core.Graph{}, usageErrorf("unknown input format %q", format)
+	}
+
+	return read(r)
+}
+
+// writeResult writes result to w in the output format.
+func writeResult(w io.Writer, result result, format string) error {
+	switch format {
+	case outputTable:
+		return result.writeTable(w)
+	case outputJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(result.value())
+	case outputDOT:
+		return result.writeDOT(w)
+	default:
+		return usageErrorf("unknown output format %q", format)
+	}
+}
+
+// usage writes the usage of graphctl with the list of commands to w.
+func usage(w io.Writer) {
+	names := make([]string, 0, len(commands))
+
+	for name := range commands {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	fmt.Fprintln(w, "Usage: graphctl <command> [flags] [file]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-14s %s\n", name, commands[name].summary)
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, `Run "graphctl <command> -h" for the flags of a command.`)
+}
+
+// run runs graphctl with the command line arguments args, without the program name.
+// It returns the exit code.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		usage(stderr)
+
+		if len(args) == 0 {
+			return exitUsage
+		}
+
+		return exitOK
+	}
+
+	name := args[0]
+
+	command, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(stderr, "graphctl: unknown command %q\n", name)
+		usage(stderr)
+		return exitUsage
+	}
+
+	// Defining the common flags, then the flags of the command
+	var (
+		flags      = flag.NewFlagSet("graphctl "+name, flag.ContinueOnError)
+		input      = flags.String("in", "", "input format: json, dot, graphml, gexf, csv or tsv (default from the file extension, json otherwise)")
+		csvLayout  = flags.String("csv-layout", "edges", "layout of csv and tsv input: edges or matrix")
+		output     = flags.String("out", outputTable, "output format: table, json or dot")
+		validation = flags.String("validation", string(core.ValidationLenient), "validation of the graph: strict or lenient")
+		timeout    = flags.Duration("timeout", 0, "maximum duration of the analysis, 0 if unlimited")
+		maxStates  = flags.Int("max-states", 0, "maximum number of states explored by path enumerations, 0 if unlimited")
+		maxPaths   = flags.Int("max-paths", 0, "maximum number of paths found by path enumerations, 0 if unlimited")
+		workers    = flags.Int("workers", 1, "number of goroutines of path enumerations")
+		analyze    = command.setUp(flags)
+	)
+
+	flags.SetOutput(stderr)
+
+	if err := flags.Parse(args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+
+		return exitUsage
+	}
+
+	if flags.NArg() > 1 {
+		fmt.Fprintf(stderr, "graphctl: too many arguments: %v\n", strings.Join(flags.Args(), " "))
+		return exitUsage
+	}
+
+	mode, err := core.ParseValidationMode(*validation)
+	if err != nil {
+		fmt.Fprintf(stderr, "graphctl: -validation has to be strict or lenient\n")
+		return exitUsage
+	}
+
+	if *workers < 1 {
+		fmt.Fprintf(stderr, "graphctl: -workers has to be a positive integer\n")
+		return exitUsage
+	}
+
+	graph, err := readGraph(flags.Arg(0), *input, *csvLayout, stdin)
+	if err == nil {
+		err = graph.Validate(mode)
+	}
+
+	if err == nil {
+		var (
+			ctx    = core.WithBudget(context.Background(), core.Budget{MaxStates: *maxStates, MaxPaths: *maxPaths})
+			result result
+		)
+
+		// Splitting path enumerations among the workers
+		ctx = core.WithWorkers(ctx, *workers)
+
+		if *timeout > 0 {
+			var cancel context.CancelFunc
+
+			ctx, cancel = context.WithTimeout(ctx, *timeout)
+			defer cancel()
+		}
+
+		// Writing partial results as well
+		if result, err = analyze(ctx, &graph); result != nil && (err == nil || truncated(err)) {
+			if writeErr := writeResult(stdout, result, *output); writeErr != nil {
+				err = writeErr
+			}
+		}
+	}
+
+	if truncated(err) {
+		fmt.Fprintf(stderr, "graphctl %v: partial result: %v\n", name, err)
+		return exitTruncated
+	}
+
+	if err != nil {
+		fmt.Fprintf(stderr, "graphctl %v: %v\n", name, err)
+
+		var (
+			usageErr     *usageError
+			parameterErr *core.InvalidParameterError
+		)
+
+		if errors.As(err, &usageErr) || errors.As(err, &parameterErr) {
+			return exitUsage
+		}
+
+		return exitError
+	}
+
+	return exitOK
+}
+
+// truncated reports whether err is a *core.TruncatedError.
+func truncated(err error) bool {
+	var truncatedErr *core.TruncatedError
+
+	return errors.As(err, &truncatedErr)
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ellescotz/graph_backend/pkg/core"
+)
+
+const testGraph string = `{
+	"nodes": [{"name": "A"}, {"name": "B"}, {"name": "C"}],
+	"edges": [
+		{"nodes": [{"name": "A"}, {"name": "B"}], "weight": 1},
+		{"nodes": [{"name": "B"}, {"name": "C"}], "weight": 2},
+		{"nodes": [{"name": "A"}, {"name": "C"}], "weight": 4}
+	]
+}`
+
+func TestRunTable(t *testing.T) {
+	t.Parallel()
+
+	var stdout, stderr bytes.Buffer
+
+	code := run([]string{"highLowWeight", "-from", "A", "-to", "C"}, strings.NewReader(testGraph), &stdout, &stderr)
+	if code != exitOK {
+		t.Fatalf("run did not work. Got exit code %v with %v", code, stderr.String())
+	}
+
+	expected := `#  WEIGHT  PATH
+1  3       A -> B -> C
+
+Strategy: dijkstra
+`
+
+	if stdout.String() != expected {
+		t.Errorf("run did not work. Got %v instead of %v", stdout.String(), expected)
+	}
+}
+
+func TestRunJSON(t *testing.T) {
+	t.Parallel()
+
+	var (
+		stdout, stderr bytes.Buffer
+		paths          []core.Path
+	)
+
+	code := run([]string{"kShortest", "-from", "A", "-to", "C", "-k", "2", "-out", "json"}, strings.NewReader(testGraph), &stdout, &stderr)
+	if code != exitOK {
+		t.Fatalf("run did not work. Got exit code %v with %v", code, stderr.String())
+	}
+
+	if err := json.Unmarshal(stdout.Bytes(), &paths); err != nil {
+		t.Fatalf("run did not work. Got malformed JSON: %v", err)
+	}
+
+	if len(paths) != 2 || paths[0].Weight != 3 || paths[1].Weight != 4 {
+		t.Errorf("run did not work. Got %v instead of the paths weighing 3 and 4", paths)
+	}
+}
+
+func TestRunFile(t *testing.T) {
+	t.Parallel()
+
+	var stdout, stderr bytes.Buffer
+
+	// The format of the file is taken from its extension
+	path := filepath.Join(t.TempDir(), "graph.csv")
+
+	if err := os.WriteFile(path, []byte("source,target,weight\nA,B,1\nB,C,2\nC,A,3\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	code := run([]string{"findCycle", "-out", "dot", path}, nil, &stdout, &stderr)
+	if code != exitOK {
+		t.Fatalf("run did not work. Got exit code %v with %v", code, stderr.String())
+	}
+
+	if !strings.HasPrefix(stdout.String(), `digraph "path1" {`) || !strings.Contains(stdout.String(), `"color"="red"`) {
+		t.Errorf("run did not work. Got %v instead of the highlighted cycle", stdout.String())
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		args []string
+		code int
+	}{
+		{[]string{}, exitUsage},
+		{[]string{"unknown"}, exitUsage},
+		{[]string{"maxSteps", "-from", "A", "-to", "C"}, exitUsage},
+		{[]string{"shortLong", "-from", "A"}, exitUsage},
+		{[]string{"shortLong", "-from", "A", "-to", "Z"}, exitError},
+		{[]string{"components", "-out", "xml"}, exitUsage},
+		{[]string{"components", "-in", "yaml"}, exitUsage},
+		{[]string{"components", "-validation", "loose"}, exitUsage},
+		{[]string{"components", "-workers", "0"}, exitUsage},
+		{[]string{"kShortest", "-from", "A", "-to", "C", "-k", "2", "-max-paths", "1"}, exitTruncated},
+		{[]string{"allPairs", "-timeout", "1ns"}, exitTruncated},
+	}
+
+	for _, test := range tests {
+		var stdout, stderr bytes.Buffer
+
+		if code := run(test.args, strings.NewReader(testGraph), &stdout, &stderr); code != test.code {
+			t.Errorf("run did not work for %v. Got exit code %v instead of %v", test.args, code, test.code)
+		}
+	}
+}
@@ -0,0 +1,255 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ellescotz/graph_backend/pkg/core"
+	"github.com/ellescotz/graph_backend/pkg/dot"
+	"github.com/ellescotz/graph_backend/pkg/interchange"
+)
+
+// result represents the result of an analysis.
+type result interface {
+	value() interface{}           // written as JSON, the same as the response of the server
+	writeTable(w io.Writer) error // writes the result as a human-readable table
+	writeDOT(w io.Writer) error   // writes the result in DOT, as the server does
+}
+
+// newTable returns a writer aligning the tab-separated columns of its lines, flushed to w.
+func newTable(w io.Writer) *tabwriter.Writer {
+	return tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+}
+
+// formatFloat returns the shortest representation of f.
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// nodeNames returns the names of nodes joined by separator.
+func nodeNames(nodes []core.Node, separator string) string {
+	names := make([]string, 0, len(nodes))
+
+	for _, n := range nodes {
+		names = append(names, n.Name)
+	}
+
+	return strings.Join(names, separator)
+}
+
+// pathsResult represents paths found in a graph.
+type pathsResult struct {
+	graph    *core.Graph
+	paths    []core.Path
+	strategy core.Strategy // how the paths were found, if reported
+}
+
+func (r *pathsResult) value() interface{} {
+	return r.paths
+}
+
+func (r *pathsResult) writeTable(w io.Writer) error {
+	table := newTable(w)
+
+	fmt.Fprintln(table, "#\tWEIGHT\tPATH")
+
+	for i, path := range r.paths {
+		fmt.Fprintf(table, "%v\t%v\t%v\n", i+1, formatFloat(path.Weight), nodeNames(path.Subgraph.Nodes, " -> "))
+	}
+
+	if err := table.Flush(); err != nil {
+		return err
+	}
+
+	if len(r.strategy) != 0 {
+		_, err := fmt.Fprintf(w, "\nStrategy: %v\n", r.strategy)
+		return err
+	}
+
+	return nil
+}
+
+func (r *pathsResult) writeDOT(w io.Writer) error {
+	return dot.WritePaths(w, *r.graph, r.paths)
+}
+
+// cycleResult represents whether a graph has a cycle, with one cycle if there is any.
+type cycleResult struct {
+	graph  *core.Graph
+	cycle  core.Path
+	cyclic bool
+}
+
+func (r *cycleResult) value() interface{} {
+	return map[string]interface{}{
+		"cyclic": r.cyclic,
+		"cycle":  r.cycle,
+	}
+}
+
+func (r *cycleResult) writeTable(w io.Writer) error {
+	if !r.cyclic {
+		_, err := fmt.Fprintln(w, "Cyclic: false")
+		return err
+	}
+
+	_, err := fmt.Fprintf(w, "Cyclic: true\nCycle: %v\nWeight: %v\n", nodeNames(r.cycle.Subgraph.Nodes, " -> "), formatFloat(r.cycle.Weight))
+
+	return err
+}
+
+func (r *cycleResult) writeDOT(w io.Writer) error {
+	var cycles []core.Path
+
+	if r.cyclic {
+		cycles = append(cycles, r.cycle)
+	}
+
+	return dot.WritePaths(w, *r.graph, cycles)
+}
+
+// treeResult represents a spanning tree, forest or arborescence of a graph.
+type treeResult struct {
+	graph *core.Graph
+	tree  core.SpanningTree
+}
+
+func (r *treeResult) value() interface{} {
+	return r.tree
+}
+
+func (r *treeResult) writeTable(w io.Writer) error {
+	table := newTable(w)
+
+	fmt.Fprintln(table, "FROM\tTO\tWEIGHT")
+
+	for _, e := range r.tree.Subgraph.Edges {
+		fmt.Fprintf(table, "%v\t%v\t%v\n", e.Nodes[0].Name, e.Nodes[1].Name, formatFloat(e.Weight))
+	}
+
+	if err := table.Flush(); err != nil {
+		return err
+	}
+
+	_, err := fmt.Fprintf(w, "\nWeight: %v\n", formatFloat(r.tree.Weight))
+
+	return err
+}
+
+func (r *treeResult) writeDOT(w io.Writer) error {
+	return dot.WritePaths(w, *r.graph, []core.Path{{Subgraph: r.tree.Subgraph, Weight: r.tree.Weight}})
+}
+
+// componentsResult represents the connected components of a graph.
+type componentsResult struct {
+	graph      *core.Graph
+	components core.Components
+}
+
+func (r *componentsResult) value() interface{} {
+	return r.components
+}
+
+func (r *componentsResult) writeTable(w io.Writer) error {
+	table := newTable(w)
+
+	fmt.Fprintln(table, "#\tSTRONG COMPONENT")
+
+	for i, component := range r.components.Strong {
+		fmt.Fprintf(table, "%v\t%v\n", i, nodeNames(component, " "))
+	}
+
+	fmt.Fprintln(table)
+	fmt.Fprintln(table, "#\tWEAK COMPONENT")
+
+	for i, component := range r.components.Weak {
+		fmt.Fprintf(table, "%v\t%v\n", i, nodeNames(component, " "))
+	}
+
+	return table.Flush()
+}
+
+func (r *componentsResult) writeDOT(w io.Writer) error {
+	return dot.Write(w, interchange.AnnotateComponents(*r.graph, r.components))
+}
+
+// flowResult represents a maximum flow in a graph.
+type flowResult struct {
+	graph *core.Graph
+	flow  core.Flow
+}
+
+func (r *flowResult) value() interface{} {
+	return r.flow
+}
+
+func (r *flowResult) writeTable(w io.Writer) error {
+	table := newTable(w)
+
+	fmt.Fprintln(table, "FROM\tTO\tCAPACITY\tFLOW\tMIN CUT")
+
+	for _, edgeFlow := range r.flow.Edges {
+		var (
+			e     = edgeFlow.Edge
+			inCut bool
+		)
+
+		for i := range r.flow.MinCut.Edges {
+			inCut = inCut || r.flow.MinCut.Edges[i].Equals(&e)
+		}
+
+		fmt.Fprintf(table, "%v\t%v\t%v\t%v\t%v\n", e.Nodes[0].Name, e.Nodes[1].Name, formatFloat(e.Weight), formatFloat(edgeFlow.Flow), inCut)
+	}
+
+	if err := table.Flush(); err != nil {
+		return err
+	}
+
+	_, err := fmt.Fprintf(w, "\nMaximum flow: %v\n", formatFloat(r.flow.Value))
+
+	return err
+}
+
+func (r *flowResult) writeDOT(w io.Writer) error {
+	return dot.Write(w, interchange.AnnotateFlow(*r.graph, r.flow))
+}
+
+// allPairsResult represents the lowest weights between every pair of nodes of a graph.
+type allPairsResult struct {
+	allPairs core.AllPairsPaths
+}
+
+func (r *allPairsResult) value() interface{} {
+	return r.allPairs
+}
+
+// writeTable writes the matrix of the lowest weights, "-" standing for unreachable nodes.
+func (r *allPairsResult) writeTable(w io.Writer) error {
+	table := newTable(w)
+
+	fmt.Fprintf(table, "FROM \\ TO\t%v\n", nodeNames(r.allPairs.Nodes, "\t"))
+
+	for i, n := range r.allPairs.Nodes {
+		cells := make([]string, 0, len(r.allPairs.Nodes))
+
+		for _, distance := range r.allPairs.Distances[i] {
+			if math.IsInf(distance, 1) {
+				cells = append(cells, "-")
+			} else {
+				cells = append(cells, formatFloat(distance))
+			}
+		}
+
+		fmt.Fprintf(table, "%v\t%v\n", n.Name, strings.Join(cells, "\t"))
+	}
+
+	return table.Flush()
+}
+
+func (r *allPairsResult) writeDOT(w io.Writer) error {
+	return dot.Write(w, interchange.AllPairsGraph(r.allPairs))
+}
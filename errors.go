@@ -0,0 +1,117 @@
+package main
+
+import (
+	"errors"
+
+	"github.com/ellescotz/graph_backend/pkg/core"
+	"github.com/ellescotz/graph_backend/pkg/storage"
+	"github.com/gin-gonic/gin"
+)
+
+// Codes of error responses.
+// Client mistakes are answered with 4xx statuses, server failures with 500 and codeInternal.
+const (
+	codeInvalidParameter string = "invalid_parameter" // 400
+	codeMalformedGraph   string = "malformed_graph"   // 400
+	codeUnknownNode      string = "unknown_node"      // 404
+	codeNotFound         string = "not_found"         // 404, unknown graph, revision or edge
+	codeConflict         string = "conflict"          // 409, existing node or edge
+	codeGraphTooLarge    string = "graph_too_large"   // 413, also for too large request bodies
+	codeInvalidWeight    string = "invalid_weight"    // 422
+	codeInvalidEdge      string = "invalid_edge"      // 422, self-loop
+	codeInvalidGraph     string = "invalid_graph"     // 422, violated invariants
+	codeNegativeCycle    string = "negative_cycle"    // 422
+	codeTruncated        string = "truncated"         // 503, search stopped without results
+	codeInternal         string = "internal"          // 500
+)
+
+// errorResponse is the body of every error response.
+type errorResponse struct {
+	Code    string     `json:"code"`
+	Message string     `json:"message"`
+	Field   string     `json:"field,omitempty"` // request parameter, or "body", causing the error
+	Cycle   *core.Path `json:"cycle,omitempty"` // negative weighted cycle, for codeNegativeCycle only
+
+	Violations []core.Violation `json:"violations,omitempty"` // every violated invariant, for codeInvalidGraph only
+}
+
+// malformedGraphError is returned when the request body does not hold a graph.
+type malformedGraphError struct {
+	err error
+}
+
+// Error returns the message of malformed graphs with the details of the error.
+func (e *malformedGraphError) Error() string {
+	return malformedGraphErrorMessage + ": " + e.err.Error()
+}
+
+// Unwrap returns the error of reading the graph.
+func (e *malformedGraphError) Unwrap() error {
+	return e.err
+}
+
+// invalidParameter returns a *core.InvalidParameterError of the request parameter field.
+func invalidParameter(field, message string) error {
+	return &core.InvalidParameterError{Field: field, Message: message}
+}
+
+// errorStatus returns the HTTP status and the body of the error response of err.
+func errorStatus(err error) (int, errorResponse) {
+	var (
+		response      = errorResponse{Message: err.Error()}
+		parameterErr  *core.InvalidParameterError
+		nodeErr       *core.UnknownNodeError
+		weightErr     *core.InvalidWeightError
+		tooLargeErr   *core.GraphTooLargeError
+		cycleErr      *core.NegativeCycleError
+		validationErr *core.ValidationError
+		truncatedErr  *core.TruncatedError
+		graphErr      *malformedGraphError
+	)
+
+	switch {
+	case errors.As(err, &parameterErr):
+		response.Code, response.Field, response.Message = codeInvalidParameter, parameterErr.Field, parameterErr.Message
+		return 400, response
+	case errors.As(err, &graphErr):
+		response.Code, response.Field = codeMalformedGraph, "body"
+		return 400, response
+	case errors.As(err, &nodeErr):
+		response.Code, response.Field = codeUnknownNode, nodeErr.Field
+		return 404, response
+	case errors.Is(err, storage.ErrNotFound), errors.Is(err, storage.ErrRevisionNotFound), errors.Is(err, core.ErrEdgeMissing):
+		response.Code = codeNotFound
+		return 404, response
+	case errors.Is(err, core.ErrNodeExists), errors.Is(err, core.ErrEdgeExists):
+		response.Code = codeConflict
+		return 409, response
+	case errors.As(err, &tooLargeErr), errors.Is(err, errBodyTooLarge):
+		response.Code = codeGraphTooLarge
+		return 413, response
+	case errors.As(err, &weightErr):
+		response.Code = codeInvalidWeight
+		return 422, response
+	case errors.Is(err, core.ErrSelfLoop):
+		response.Code = codeInvalidEdge
+		return 422, response
+	case errors.As(err, &validationErr):
+		response.Code, response.Violations = codeInvalidGraph, validationErr.Violations
+		return 422, response
+	case errors.As(err, &cycleErr):
+		response.Code, response.Cycle = codeNegativeCycle, &cycleErr.Cycle
+		return 422, response
+	case errors.As(err, &truncatedErr):
+		response.Code = codeTruncated
+		return 503, response
+	default:
+		response.Code = codeInternal
+		return 500, response
+	}
+}
+
+// respondError gives the error response of err.
+func respondError(c *gin.Context, err error) {
+	status, response := errorStatus(err)
+
+	c.JSON(status, response)
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ellescotz/graph_backend/pkg/core"
+	"github.com/ellescotz/graph_backend/pkg/storage"
+	"github.com/gin-gonic/gin"
+)
+
+func TestRespondError(t *testing.T) {
+	t.Parallel()
+
+	nodeA := core.Node{Name: "A"}
+	cycle := core.Path{Subgraph: core.Graph{Nodes: []core.Node{nodeA}}}
+	violations := []core.Violation{{Rule: core.RuleSelfLoop, Message: "self-loop", Edges: []int{0}}}
+
+	tests := []struct {
+		err    error
+		status int
+		code   string
+		field  string
+	}{
+		{invalidParameter("MaxEdges", "has to be a positive integer"), 400, codeInvalidParameter, "MaxEdges"},
+		{&malformedGraphError{err: errors.New("unexpected EOF")}, 400, codeMalformedGraph, "body"},
+		{&core.UnknownNodeError{Node: nodeA, Field: "From"}, 404, codeUnknownNode, "From"},
+		{storage.ErrNotFound, 404, codeNotFound, ""},
+		{storage.ErrRevisionNotFound, 404, codeNotFound, ""},
+		{core.ErrEdgeMissing, 404, codeNotFound, ""},
+		{core.ErrNodeExists, 409, codeConflict, ""},
+		{core.ErrEdgeExists, 409, codeConflict, ""},
+		{&core.GraphTooLargeError{Nodes: 2, MaxNodes: 1}, 413, codeGraphTooLarge, ""},
+		{errBodyTooLarge, 413, codeGraphTooLarge, ""},
+		{&core.InvalidWeightError{Err: core.ErrNegativeWeight}, 422, codeInvalidWeight, ""},
+		{core.ErrSelfLoop, 422, codeInvalidEdge, ""},
+		{&core.ValidationError{Violations: violations}, 422, codeInvalidGraph, ""},
+		{&core.NegativeCycleError{Cycle: cycle}, 422, codeNegativeCycle, ""},
+		{&core.TruncatedError{Err: context.DeadlineExceeded}, 503, codeTruncated, ""},
+		{errors.New("disk failure"), 500, codeInternal, ""},
+		// Wrapped errors are answered as the errors they wrap
+		{fmt.Errorf("loading graph: %w", storage.ErrNotFound), 404, codeNotFound, ""},
+		{fmt.Errorf("adding edge: %w", &core.UnknownNodeError{Node: nodeA, Field: "Edge"}), 404, codeUnknownNode, "Edge"},
+	}
+
+	for _, test := range tests {
+		recorder := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(recorder)
+
+		respondError(c, test.err)
+
+		var response errorResponse
+
+		if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+			t.Fatalf("respondError(%v) did not work. Got body %q and error %v", test.err, recorder.Body, err)
+		}
+
+		if recorder.Code != test.status {
+			t.Errorf("respondError(%v) did not work. Got status %v instead of %v", test.err, recorder.Code, test.status)
+		}
+
+		if response.Code != test.code || response.Field != test.field {
+			t.Errorf("respondError(%v) did not work. Got code %q and field %q instead of %q and %q", test.err, response.Code, response.Field, test.code, test.field)
+		}
+
+		if len(response.Message) == 0 {
+			t.Errorf("respondError(%v) did not work. Got an empty message", test.err)
+		}
+
+		if test.code == codeInvalidGraph && len(response.Violations) != len(violations) {
+			t.Errorf("respondError(%v) did not work. Got violations %v instead of %v", test.err, response.Violations, violations)
+		}
+
+		if (test.code == codeNegativeCycle) != (response.Cycle != nil) {
+			t.Errorf("respondError(%v) did not work. Got cycle %v", test.err, response.Cycle)
+		}
+	}
+}
+
+func TestErrorResponses(t *testing.T) {
+	t.Parallel()
+
+	router := newRouter()
+
+	graph := `{"Nodes": [{"Name": "A"}, {"Name": "B"}], "Edges": [{"Nodes": [{"Name": "A"}, {"Name": "B"}], "Weight": 1}]}`
+
+	tests := []struct {
+		method, target, body string
+		status               int
+		code                 string
+		field                string
+	}{
+		// Case 1: missing and malformed parameters
+		{"POST", "/maxSteps?To=B&MaxEdges=2%3B&Exact=true", graph, 400, codeInvalidParameter, "From"},
+		{"POST", "/maxSteps?From=A&To=B&Exact=true", graph, 400, codeInvalidParameter, "MaxEdges"},
+		// Case 2: bodies without graphs
+		{"POST", "/highLowWeight?From=A&To=B", `{"Nodes": [`, 400, codeMalformedGraph, "body"},
+		// Case 3: nodes missing from the graph
+		{"POST", "/highLowWeight?From=A&To=C", graph, 404, codeUnknownNode, "To"},
+		// Case 4: unknown stored graphs
+		{"GET", "/graphs/unknown", "", 404, codeNotFound, ""},
+		// Case 5: graphs violating invariants
+		{"POST", "/kShortest?From=A&To=B&K=2", strings.Replace(graph, `"Weight": 1`, `"Weight": -1`, 1), 422, codeInvalidWeight, ""},
+	}
+
+	for i, test := range tests {
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(test.method, test.target, strings.NewReader(test.body))
+		request.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(recorder, request)
+
+		var response errorResponse
+
+		if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Request %v did not work. Got body %q and error %v", i+1, recorder.Body, err)
+		}
+
+		if recorder.Code != test.status || response.Code != test.code || response.Field != test.field {
+			t.Errorf("Request %v did not work. Got %v %q %q instead of %v %q %q", i+1, recorder.Code, response.Code, response.Field, test.status, test.code, test.field)
+		}
+	}
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/ellescotz/graph_backend/pkg/core"
+	"github.com/ellescotz/graph_backend/pkg/dot"
+	"github.com/ellescotz/graph_backend/pkg/interchange"
+	"github.com/ellescotz/graph_backend/pkg/render"
+	"github.com/gin-gonic/gin"
+)
+
+// graphReaders read request bodies by their Content-Type. Other bodies are read as JSON.
+var graphReaders = map[string]func(r io.Reader) (core.Graph, error){
+	dot.MediaType:                dot.Parse,
+	interchange.GraphMLMediaType: interchange.ReadGraphML,
+	interchange.GEXFMediaType:    interchange.ReadGEXF,
+}
+
+// csvSeparators are the separators of delimiter-separated request bodies by their Content-Type.
+var csvSeparators = map[string]rune{
+	interchange.CSVMediaType: interchange.CSVSeparator,
+	interchange.TSVMediaType: interchange.TSVSeparator,
+}
+
+// graphWriters write graphs in the media types of responses other than JSON.
+var graphWriters = map[string]func(w io.Writer, g core.Graph) error{
+	dot.MediaType:                dot.Write,
+	interchange.GraphMLMediaType: interchange.WriteGraphML,
+	interchange.GEXFMediaType:    interchange.WriteGEXF,
+}
+
+// responseMediaTypes are the media types of responses in order of preference.
+var responseMediaTypes = []string{
+	gin.MIMEJSON,
+	dot.MediaType,
+	interchange.GraphMLMediaType,
+	interchange.GEXFMediaType,
+	render.SVGMediaType,
+	render.PNGMediaType,
+}
+
+// Limits on the size of graphs sent in requests.
+const (
+	maxGraphNodes int = 10000
+	maxGraphEdges int = 100000
+)
+
+// decodeGraph decodes the graph of the request body into graph.
+// The format of the body is given by the Content-Type request header:
+// DOT for "text/vnd.graphviz", GraphML for "application/graphml+xml",
+// GEXF for "application/gexf+xml", CSV for "text/csv", TSV for "text/tab-separated-values" and JSON otherwise.
+// CSV and TSV bodies hold an edge list or an adjacency matrix
+// as given by the "CSVLayout": "<edges/matrix>" request parameter, edge list by default.
+// It returns a *malformedGraphError if the body does not hold a graph,
+// and a *core.GraphTooLargeError if the graph has more than maxGraphNodes nodes or maxGraphEdges edges.
+// Bodies larger than maxBodyBytes are not read further, errBodyTooLarge is returned instead (see limitBodies).
+func decodeGraph(c *gin.Context, graph *core.Graph) error {
+	var err error
+
+	contentType := c.ContentType()
+
+	if separator, ok := csvSeparators[contentType]; ok {
+		switch c.DefaultQuery("CSVLayout", "edges") {
+		case "edges":
+			*graph, err = interchange.ReadEdgeList(c.Request.Body, separator)
+		case "matrix":
+			*graph, err = interchange.ReadAdjacencyMatrix(c.Request.Body, separator)
+		default:
+			return invalidParameter("CSVLayout", "has to be edges or matrix")
+		}
+	} else if read, ok := graphReaders[contentType]; ok {
+		*graph, err = read(c.Request.Body)
+	} else {
+		err = json.NewDecoder(c.Request.Body).Decode(graph)
+	}
+
+	if errors.Is(err, errBodyTooLarge) {
+		return err
+	} else if err != nil {
+		return &malformedGraphError{err: err}
+	}
+
+	return graph.CheckSize(maxGraphNodes, maxGraphEdges)
+}
+
+// respondFormat returns the media type of the response preferred by the Accept request header.
+// JSON is preferred if the header is missing or accepts anything.
+func respondFormat(c *gin.Context) string {
+	return c.NegotiateFormat(responseMediaTypes...)
+}
+
+// respondPaths responds with paths found in graph in the format preferred by the client.
+// In DOT each path is the whole graph with the path highlighted, up to dot.PathLimit paths,
+// in GraphML and GEXF the graph is annotated with the paths containing its nodes and edges,
+// in SVG and PNG the graph is drawn with the paths highlighted in different colors.
+func respondPaths(c *gin.Context, graph *core.Graph, paths []core.Path) {
+	switch format := respondFormat(c); format {
+	case gin.MIMEJSON:
+		c.JSON(200, paths)
+	case dot.MediaType:
+		if limit := dot.PathLimit(*graph); len(paths) > limit {
+			c.Header(truncatedHeader, fmt.Sprintf("only the first %v paths are written in DOT", limit))
+		}
+
+		respondWith(c, format, func(w io.Writer) error {
+			return dot.WritePaths(w, *graph, paths)
+		})
+	case render.SVGMediaType, render.PNGMediaType:
+		respondPicture(c, format, graph, paths)
+	default:
+		annotated := interchange.AnnotatePaths(*graph, paths)
+
+		respondGraph(c, &annotated)
+	}
+}
+
+// respondGraph responds with graph in the format preferred by the client.
+func respondGraph(c *gin.Context, graph *core.Graph) {
+	format := respondFormat(c)
+
+	if format == render.SVGMediaType || format == render.PNGMediaType {
+		respondPicture(c, format, graph, nil)
+		return
+	}
+
+	write, ok := graphWriters[format]
+	if !ok {
+		c.JSON(200, graph)
+		return
+	}
+
+	respondWith(c, format, func(w io.Writer) error {
+		return write(w, *graph)
+	})
+}
+
+// respondPicture responds with a picture of graph with paths highlighted, as mediaType: SVG or PNG.
+// The picture is drawn according to the request parameters (see renderOptions).
+// In case of malformed parameters it gives an error response.
+func respondPicture(c *gin.Context, mediaType string, graph *core.Graph, paths []core.Path) {
+	options, err := renderOptions(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	drawing, err := render.NewDrawing(c.Request.Context(), *graph, paths, options)
+	if errors.Is(err, render.ErrUnknownLayout) {
+		respondError(c, invalidParameter("Layout", "has to be positions, force, circular or layered"))
+		return
+	} else if ctxErr := c.Request.Context().Err(); ctxErr != nil && errors.Is(err, ctxErr) {
+		// Laying out stopped with the searches of the request
+		respondError(c, &core.TruncatedError{Err: ctxErr})
+		return
+	} else if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondWith(c, mediaType, func(w io.Writer) error {
+		if mediaType == render.PNGMediaType {
+			return drawing.WritePNG(w)
+		}
+
+		return drawing.WriteSVG(w)
+	})
+}
+
+// renderOptions returns the options of pictures given by the request parameters:
+// Layout of the nodes: "Layout": "<positions/force/circular/layered>",
+// node positions if every node has one and force-directed otherwise if missing
+// Size of the picture in pixels: "Width": "<a positive integer>", "Height": "<a positive integer>", 800x600 if missing
+func renderOptions(c *gin.Context) (render.Options, error) {
+	var (
+		options = render.Options{Layout: render.Layout(c.Query("Layout"))}
+		err     error
+	)
+
+	sizeMessage := fmt.Sprintf("has to be an integer between 1 and %v", render.MaxSize)
+
+	if width := c.Query("Width"); len(width) != 0 {
+		if options.Width, err = strconv.Atoi(width); err != nil || options.Width < 1 || options.Width > render.MaxSize {
+			return options, invalidParameter("Width", sizeMessage)
+		}
+	}
+
+	if height := c.Query("Height"); len(height) != 0 {
+		if options.Height, err = strconv.Atoi(height); err != nil || options.Height < 1 || options.Height > render.MaxSize {
+			return options, invalidParameter("Height", sizeMessage)
+		}
+	}
+
+	return options, nil
+}
+
+// respondWith responds with the output of write as mediaType.
+// In case of failing write it gives an error response.
+func respondWith(c *gin.Context, mediaType string, write func(w io.Writer) error) {
+	var buffer bytes.Buffer
+
+	if err := write(&buffer); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Data(200, mediaType, buffer.Bytes())
+}
@@ -0,0 +1,339 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"strconv"
+
+	"github.com/ellescotz/graph_backend/pkg/core"
+	"github.com/ellescotz/graph_backend/pkg/storage"
+	"github.com/gin-gonic/gin"
+)
+
+// storageDirEnv is the environment variable holding the directory of stored graphs.
+// Graphs are kept in memory if it is not set.
+const storageDirEnv string = "GRAPH_STORAGE_DIR"
+
+// validationEnv is the environment variable holding the default validation mode of graphs: strict or lenient.
+// Graphs are validated leniently if it is not set.
+const validationEnv string = "GRAPH_VALIDATION"
+
+// repository stores the graphs managed through the /graphs endpoints.
+var repository storage.Repository = storage.NewMemoryRepository()
+
+// defaultValidation is the validation mode of graphs if the "Validation" request parameter is missing.
+var defaultValidation = core.ValidationLenient
+
+// newRepository returns a file based repository if storageDirEnv is set;
+// otherwise an in-memory one.
+func newRepository() (storage.Repository, error) {
+	if dir := os.Getenv(storageDirEnv); len(dir) != 0 {
+		return storage.NewFileRepository(dir)
+	}
+
+	return storage.NewMemoryRepository(), nil
+}
+
+// newDefaultValidation returns the validation mode given by validationEnv,
+// or lenient validation if it is not set.
+func newDefaultValidation() (core.ValidationMode, error) {
+	if mode := os.Getenv(validationEnv); len(mode) != 0 {
+		return core.ParseValidationMode(mode)
+	}
+
+	return core.ValidationLenient, nil
+}
+
+// validateGraph checks the invariants of graph in the validation mode given by
+// the "Validation": "<strict/lenient>" request parameter, or defaultValidation if it is missing.
+// It returns a *core.ValidationError with every violation (see core.Graph.Validate).
+func validateGraph(c *gin.Context, graph *core.Graph) error {
+	mode := defaultValidation
+
+	if value, ok := c.GetQuery("Validation"); ok {
+		var err error
+
+		if mode, err = core.ParseValidationMode(value); err != nil {
+			return invalidParameter("Validation", "has to be strict or lenient")
+		}
+	}
+
+	return graph.Validate(mode)
+}
+
+// storedGraph returns the graph stored with id at the revision given by the request parameter named key,
+// or the latest revision if the parameter is missing.
+func storedGraph(c *gin.Context, id, key string) (core.Graph, error) {
+	value, ok := c.GetQuery(key)
+	if !ok {
+		return repository.Get(id)
+	}
+
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		return core.Graph{}, invalidParameter(key, "has to be an integer")
+	}
+
+	return repository.GetRevision(id, number)
+}
+
+// bindGraph loads the graph of the request into graph.
+// If the "GraphID": "<id>" request parameter is given, the graph is loaded from the repository,
+// optionally as it was at the "Revision": "<a positive integer>" request parameter;
+// otherwise the request body has to contain the graph in a format given by the Content-Type (see decodeGraph).
+// The graph is validated before any analysis (see validateGraph).
+// In case of malformed, unknown or invalid graph it gives an error response, and it returns false.
+func bindGraph(c *gin.Context, graph *core.Graph) bool {
+	var err error
+
+	if id := c.Query("GraphID"); len(id) != 0 {
+		*graph, err = storedGraph(c, id, "Revision")
+	} else {
+		// Decoding request body that contains the graph
+		err = decodeGraph(c, graph)
+	}
+
+	if err == nil {
+		err = validateGraph(c, graph)
+	}
+
+	if err != nil {
+		respondError(c, err)
+		return false
+	}
+
+	return true
+}
+
+// modifyGraph applies modify to the graph stored with the "id" path parameter,
+// and responds with the modified graph.
+// In case of unknown graph or failing modification it gives an error response.
+func modifyGraph(c *gin.Context, modify func(graph *core.Graph) error) {
+	var modified core.Graph
+
+	err := repository.Modify(c.Param("id"), func(graph *core.Graph) error {
+		if err := modify(graph); err != nil {
+			return err
+		}
+
+		modified = *graph
+
+		return nil
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondGraph(c, &modified)
+}
+
+// createGraph stores the graph of the request body, once validated (see validateGraph).
+// It responds with the ID of the stored graph.
+func createGraph(c *gin.Context) {
+	var graph core.Graph
+
+	// Decoding request body that contains the graph
+	err := decodeGraph(c, &graph)
+	if err == nil {
+		err = validateGraph(c, &graph)
+	}
+
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	id, err := repository.Create(graph)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(201, gin.H{
+		"id": id,
+	})
+}
+
+// listGraphs responds with the IDs of every stored graph.
+func listGraphs(c *gin.Context) {
+	ids, err := repository.List()
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(200, ids)
+}
+
+// getGraph responds with the graph stored with the "id" path parameter.
+func getGraph(c *gin.Context) {
+	graph, err := repository.Get(c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondGraph(c, &graph)
+}
+
+// listRevisions responds with every revision of the graph stored with the "id" path parameter.
+func listRevisions(c *gin.Context) {
+	revisions, err := repository.Revisions(c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(200, revisions)
+}
+
+// getRevision responds with the graph stored with the "id" path parameter
+// as it was at the "revision" path parameter.
+func getRevision(c *gin.Context) {
+	number, err := strconv.Atoi(c.Param("revision"))
+	if err != nil {
+		respondError(c, invalidParameter("revision", "has to be an integer"))
+		return
+	}
+
+	graph, err := repository.GetRevision(c.Param("id"), number)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondGraph(c, &graph)
+}
+
+// diffGraph responds with the differences between two revisions of the graph stored with the "id" path parameter.
+// Request parameters can specify the revisions and the comparison in the following way:
+// "FromRevision": "<a positive integer>", "ToRevision": "<a positive integer>", "IgnoreWeight": "<true/false>"
+// The revisions default to the latest one, and weight changes are reported unless IgnoreWeight is true.
+func diffGraph(c *gin.Context) {
+	var (
+		oldGraph, newGraph core.Graph
+		ignoreWeight       bool
+		err                error
+	)
+
+	id := c.Param("id")
+
+	if oldGraph, err = storedGraph(c, id, "FromRevision"); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	if newGraph, err = storedGraph(c, id, "ToRevision"); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	if value, ok := c.GetQuery("IgnoreWeight"); ok {
+		if ignoreWeight, err = strconv.ParseBool(value); err != nil {
+			respondError(c, invalidParameter("IgnoreWeight", "has to be true or false"))
+			return
+		}
+	}
+
+	c.JSON(200, core.Diff(oldGraph, newGraph, !ignoreWeight))
+}
+
+// updateGraph replaces the graph stored with the "id" path parameter
+// by the graph of the request body, once validated (see validateGraph).
+func updateGraph(c *gin.Context) {
+	var graph core.Graph
+
+	// Decoding request body that contains the graph
+	err := decodeGraph(c, &graph)
+	if err == nil {
+		err = validateGraph(c, &graph)
+	}
+
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	modifyGraph(c, func(stored *core.Graph) error {
+		*stored = graph
+
+		return nil
+	})
+}
+
+// deleteGraph removes the graph stored with the "id" path parameter.
+func deleteGraph(c *gin.Context) {
+	if err := repository.Delete(c.Param("id")); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Status(204)
+}
+
+// addNode adds the node of the request body to the graph stored with the "id" path parameter.
+// It responds with the modified graph.
+func addNode(c *gin.Context) {
+	var node core.Node
+
+	// Decoding request body that contains the node
+	err := json.NewDecoder(c.Request.Body).Decode(&node)
+	if err != nil || len(node.Name) == 0 {
+		respondError(c, invalidParameter("body", "malformed node"))
+		return
+	}
+
+	modifyGraph(c, func(graph *core.Graph) error {
+		return graph.AddNode(node)
+	})
+}
+
+// removeNode removes the node named by the "name" path parameter and its edges
+// from the graph stored with the "id" path parameter.
+// It responds with the modified graph.
+func removeNode(c *gin.Context) {
+	modifyGraph(c, func(graph *core.Graph) error {
+		return graph.RemoveNode(core.Node{Name: c.Param("name")})
+	})
+}
+
+// addEdge adds the edge of the request body to the graph stored with the "id" path parameter.
+// It responds with the modified graph.
+func addEdge(c *gin.Context) {
+	var edge core.Edge
+
+	// Decoding request body that contains the edge
+	err := json.NewDecoder(c.Request.Body).Decode(&edge)
+	if err != nil {
+		respondError(c, invalidParameter("body", "malformed edge"))
+		return
+	}
+
+	modifyGraph(c, func(graph *core.Graph) error {
+		return graph.AddEdge(edge)
+	})
+}
+
+// removeEdge removes an edge from the graph stored with the "id" path parameter.
+// Request parameters have to identify the edge in the following way:
+// "From": "<node1>", "To": "<node2>", "Weight": "<a floating point number>"
+// It responds with the modified graph.
+func removeEdge(c *gin.Context) {
+	var edge core.Edge
+
+	edge.Nodes[0].Name = c.Query("From")
+	edge.Nodes[1].Name = c.Query("To")
+
+	weight, err := strconv.ParseFloat(c.Query("Weight"), 64)
+	if err != nil {
+		respondError(c, invalidParameter("Weight", "has to be a floating point number"))
+		return
+	}
+
+	edge.Weight = weight
+
+	modifyGraph(c, func(graph *core.Graph) error {
+		return graph.RemoveEdge(edge)
+	})
+}
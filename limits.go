@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/ellescotz/graph_backend/pkg/core"
+	"github.com/gin-gonic/gin"
+)
+
+// Environment variables holding the limits of the searches of every request (see limitSearches).
+// A zero limit disables it, defaults are used if they are not set.
+const (
+	searchTimeoutEnv string = "GRAPH_SEARCH_TIMEOUT" // wall time, such as "30s"
+	maxStatesEnv     string = "GRAPH_MAX_STATES"     // explored states of path enumerations
+	maxPathsEnv      string = "GRAPH_MAX_PATHS"      // paths found by path enumerations
+)
+
+// searchWorkersEnv is the environment variable holding the number of goroutines of each path enumeration,
+// 1 if it is not set (see core.WithWorkers).
+const searchWorkersEnv string = "GRAPH_SEARCH_WORKERS"
+
+// maxBodyBytes is the max. size of request bodies, larger bodies are not read past it.
+// It leaves room for graphs of maxGraphNodes nodes and maxGraphEdges edges in verbose formats.
+const maxBodyBytes int64 = 64 << 20
+
+// errBodyTooLarge is returned when reading a request body larger than maxBodyBytes.
+var errBodyTooLarge = fmt.Errorf("request body too large: at most %v bytes are allowed", maxBodyBytes)
+
+// truncatedHeader is the response header telling why the results are partial, if they are.
+const truncatedHeader string = "Truncated"
+
+// searchLimits represents the limits of the searches of a request.
+type searchLimits struct {
+	timeout time.Duration // 0 if unlimited
+	budget  core.Budget
+	workers int
+}
+
+// limits are the limits of the searches of every request.
+var limits = searchLimits{
+	timeout: 30 * time.Second,
+	budget: core.Budget{
+		MaxStates: 10000000,
+		MaxPaths:  10000,
+	},
+	workers: 1,
+}
+
+// newSearchLimits returns limits with the limits given by the environment variables.
+func newSearchLimits() (searchLimits, error) {
+	newLimits := limits
+
+	if value := os.Getenv(searchTimeoutEnv); len(value) != 0 {
+		timeout, err := time.ParseDuration(value)
+		if err != nil || timeout < 0 {
+			return newLimits, fmt.Errorf("%v has to be a non-negative duration", searchTimeoutEnv)
+		}
+
+		newLimits.timeout = timeout
+	}
+
+	for env, limit := range map[string]*int{
+		maxStatesEnv: &newLimits.budget.MaxStates,
+		maxPathsEnv:  &newLimits.budget.MaxPaths,
+	} {
+		if value := os.Getenv(env); len(value) != 0 {
+			number, err := strconv.Atoi(value)
+			if err != nil || number < 0 {
+				return newLimits, fmt.Errorf("%v has to be a non-negative integer", env)
+			}
+
+			*limit = number
+		}
+	}
+
+	if value := os.Getenv(searchWorkersEnv); len(value) != 0 {
+		workers, err := strconv.Atoi(value)
+		if err != nil || workers < 1 {
+			return newLimits, fmt.Errorf("%v has to be a positive integer", searchWorkersEnv)
+		}
+
+		newLimits.workers = workers
+	}
+
+	return newLimits, nil
+}
+
+// limitSearches ties the searches of requests to limits through the context of the request:
+// searches stop when the client goes away, after limits.timeout, or once limits.budget is exhausted.
+// Path enumerations are split among limits.workers goroutines.
+func limitSearches() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := core.WithWorkers(core.WithBudget(c.Request.Context(), limits.budget), limits.workers)
+
+		if limits.timeout > 0 {
+			var cancel context.CancelFunc
+
+			ctx, cancel = context.WithTimeout(ctx, limits.timeout)
+			defer cancel()
+		}
+
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+	}
+}
+
+// limitedBody is a request body limited by http.MaxBytesReader,
+// reading past maxBodyBytes fails with errBodyTooLarge.
+type limitedBody struct {
+	io.ReadCloser
+	read int64 // bytes read until now
+}
+
+// Read reads from the limited body, see io.Reader.
+func (b *limitedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+
+	if b.read += int64(n); err != nil && err != io.EOF && b.read >= maxBodyBytes {
+		err = errBodyTooLarge
+	}
+
+	return n, err
+}
+
+// limitBodies stops reading request bodies past maxBodyBytes, before they are decoded.
+func limitBodies() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Request.Body = &limitedBody{ReadCloser: http.MaxBytesReader(c.Writer, c.Request.Body, maxBodyBytes)}
+
+		c.Next()
+	}
+}
+
+// partialResults sets truncatedHeader to the reason of truncation if err is a *core.TruncatedError,
+// so that the partial results found until then are given; it returns nil in that case.
+// Otherwise it returns err.
+func partialResults(c *gin.Context, err error) error {
+	var truncatedErr *core.TruncatedError
+
+	if errors.As(err, &truncatedErr) {
+		c.Header(truncatedHeader, truncatedErr.Err.Error())
+		return nil
+	}
+
+	return err
+}
@@ -1,77 +1,109 @@
 package main
 
 import (
-	"encoding/json"
+	"log"
 	"strconv"
 
 	"github.com/ellescotz/graph_backend/pkg/core"
+	"github.com/ellescotz/graph_backend/pkg/interchange"
+	"github.com/ellescotz/graph_backend/pkg/render"
 	"github.com/gin-gonic/gin"
 )
 
 const (
-	malformedNodesErrorMessage string = "Nodes key in request header is malformed"
+	malformedNodesErrorMessage string = "From and To keys in request are malformed"
 	malformedGraphErrorMessage string = "malformed graph"
+
+	// strategyHeader is the response header telling how the paths were found.
+	strategyHeader string = "Strategy"
 )
 
+// parseEndNodes identifies the initial and end nodes from the request parameters,
+// and checks that they are nodes of graph.
+// Request parameters have to contain information in the following way:
+// "From": "<node1>", "To": "<node2>" / for example: "From": "A", "To": "Zürich"
+// The legacy "Nodes": "<node1><node2>" parameter is accepted as well
+// for single-character node names.
+// In case of malformed or unknown nodes it gives an error response, and ok is false.
+func parseEndNodes(c *gin.Context, graph *core.Graph) (initialNode, endNode core.Node, ok bool) {
+	initialNode.Name = c.Query("From")
+	endNode.Name = c.Query("To")
+
+	// Falling back to the legacy parameter
+	if len(initialNode.Name) == 0 && len(endNode.Name) == 0 {
+		endNodes := []rune(c.Query("Nodes"))
+
+		if len(endNodes) == 2 {
+			initialNode.Name = string(endNodes[:1])
+			endNode.Name = string(endNodes[1:])
+		}
+	}
+
+	if len(initialNode.Name) == 0 {
+		respondError(c, invalidParameter("From", malformedNodesErrorMessage))
+		return initialNode, endNode, false
+	}
+
+	if len(endNode.Name) == 0 {
+		respondError(c, invalidParameter("To", malformedNodesErrorMessage))
+		return initialNode, endNode, false
+	}
+
+	for i, n := range []core.Node{initialNode, endNode} {
+		if !graph.HasNode(n) {
+			respondError(c, &core.UnknownNodeError{Node: n, Field: []string{"From", "To"}[i]})
+			return initialNode, endNode, false
+		}
+	}
+
+	return initialNode, endNode, true
+}
+
 // getPathsWithMaxSteps calls GeneratePathsWithMaxSteps.
 // Header requirements:
-// Initial and end nodes: "Nodes": "<node1><node2>" / for example: "Nodes": "AB"
+// Initial and end nodes: "From": "<node1>", "To": "<node2>" / for example: "From": "A", "To": "Zürich"
 // (Maximum) number of steps: "MaxEdges": "<a positive integer>"
 // Exact or up to certain number of edges: "Exact": "<true/false>"
+// Paths are streamed as they are found if the Accept request header asks for
+// newline-delimited JSON ("application/x-ndjson") or Server-Sent Events ("text/event-stream").
 // In case of malformed graph or header file the function exits,
 // and it gives an error response.
 func getPathsWithMaxSteps(c *gin.Context) {
 	var (
 		graph                core.Graph
-		endNodesString       string
 		initialNode, endNode core.Node
 		maxEdgesString       string
 		maxEdges             int
 		exactNumberString    string
 		exactNumber          bool
 		relevantPaths        []core.Path
+		ok                   bool
+		err                  error
 	)
 
-	// Decoding request body that contains the graph
-	err := json.NewDecoder(c.Request.Body).Decode(&graph)
-	if err != nil {
-		c.JSON(500, gin.H{
-			"error": malformedGraphErrorMessage,
-		})
+	// Loading the graph from the request body or from the repository
+	if !bindGraph(c, &graph) {
 		return
 	}
 
-	// Identifying initial and end node from request header
-	// Request header has to contain information about the initial and end nodes in the following way:
-	// "Nodes": "AB"
-	endNodesString = c.Query("Nodes")
-
-	if len(endNodesString) != 2 {
-		c.JSON(500, gin.H{
-			"error": malformedNodesErrorMessage,
-		})
+	// Identifying initial and end node from request parameters
+	initialNode, endNode, ok = parseEndNodes(c, &graph)
+	if !ok {
 		return
 	}
 
-	initialNode.Name = endNodesString[:1]
-	endNode.Name = endNodesString[1:]
-
 	// Identifying maximum number of steps (edges) from request header
 	// Request header has to contain information in the following way:
 	// "MaxEdges": "<a positive integer>"
 	maxEdgesString = c.Query("MaxEdges")
 	if len(maxEdgesString) == 0 {
-		c.JSON(500, gin.H{
-			"error": "wrong MaxEdges",
-		})
+		respondError(c, invalidParameter("MaxEdges", "has to be a positive integer"))
 		return
 	}
 
 	maxEdges, err = strconv.Atoi(maxEdgesString[:len(maxEdgesString)-1])
 	if err != nil {
-		c.JSON(500, gin.H{
-			"error": "wrong MaxEdges",
-		})
+		respondError(c, invalidParameter("MaxEdges", "has to be a positive integer"))
 		return
 	}
 
@@ -82,78 +114,75 @@ func getPathsWithMaxSteps(c *gin.Context) {
 
 	exactNumber, err = strconv.ParseBool(exactNumberString)
 	if err != nil {
-		c.JSON(500, gin.H{
-			"error": "wrong Exact",
+		respondError(c, invalidParameter("Exact", "has to be true or false"))
+		return
+	}
+
+	// Streaming relevant paths as they are found if the client asks for it
+	if format, streaming := streamFormat(c); streaming {
+		streamPaths(c, format, func(emit core.PathFunc) error {
+			return graph.StreamPathsWithMaxSteps(c.Request.Context(), initialNode, endNode, maxEdges, exactNumber, emit)
 		})
+
 		return
 	}
 
 	// Calculating relevant paths
-	relevantPaths = graph.GeneratePathsWithMaxSteps(initialNode, endNode, maxEdges, exactNumber)
+	relevantPaths, err = graph.GeneratePathsWithMaxSteps(c.Request.Context(), initialNode, endNode, maxEdges, exactNumber)
+	if err = partialResults(c, err); err != nil {
+		respondError(c, err)
+		return
+	}
 
 	// Binding relevantPaths with request
-	c.JSON(200, relevantPaths)
+	respondPaths(c, &graph, relevantPaths)
 }
 
 // getPathsWithMaxWeight calls GeneratePathsWithMaxWeight.
 // Header requirements:
-// Initial and end nodes: "Nodes": "<node1><node2>" / for example: "Nodes": "AB"
+// Initial and end nodes: "From": "<node1>", "To": "<node2>" / for example: "From": "A", "To": "Zürich"
 // (Maximum) sum weight of a path: "MaxWeight": "<a positive floating point number>"
 // Exact or up to certain sum weight: "Exact": "<true/false>"
+// Paths are streamed as they are found if the Accept request header asks for
+// newline-delimited JSON ("application/x-ndjson") or Server-Sent Events ("text/event-stream").
 // In case of malformed graph or header file the function exits,
 // and it gives an error response.
 func getPathsWithMaxWeight(c *gin.Context) {
 	var (
 		graph                core.Graph
-		endNodesString       string
 		initialNode, endNode core.Node
 		maxWeightString      string
 		maxWeight            float64
 		exactWeightString    string
 		exactWeight          bool
 		relevantPaths        []core.Path
+		ok                   bool
+		err                  error
 	)
 
-	// Decoding request body that contains the graph
-	err := json.NewDecoder(c.Request.Body).Decode(&graph)
-	if err != nil {
-		c.JSON(500, gin.H{
-			"error": malformedGraphErrorMessage,
-		})
+	// Loading the graph from the request body or from the repository
+	if !bindGraph(c, &graph) {
 		return
 	}
 
-	// Identifying initial and end node from request header
-	// Request header has to contain information about the initial and end nodes in the following way:
-	// "Nodes": "AB"
-	endNodesString = c.Query("Nodes")
-
-	if len(endNodesString) != 2 {
-		c.JSON(500, gin.H{
-			"error": malformedNodesErrorMessage,
-		})
+	// Identifying initial and end node from request parameters
+	initialNode, endNode, ok = parseEndNodes(c, &graph)
+	if !ok {
 		return
 	}
 
-	initialNode.Name = endNodesString[:1]
-	endNode.Name = endNodesString[1:]
-
 	// Identifying maximum sum weight of a path from request header
 	// Request header has to contain information in the following way:
 	// "MaxWeight": "<a positive floating point number>T"
 	maxWeightString = c.Query("MaxWeight")
 	if len(maxWeightString) == 0 {
-		c.JSON(500, gin.H{
-			"error": "wrong MaxWeight length",
-		})
+		respondError(c, invalidParameter("MaxWeight", "has to be a floating point number"))
 		return
 	}
 
 	maxWeight, err = strconv.ParseFloat(maxWeightString[:len(maxWeightString)-1], 64)
 	if err != nil {
-		c.JSON(500, gin.H{
-			"error": "wrong MaxWeight float",
-		})
+		respondError(c, invalidParameter("MaxWeight", "has to be a floating point number"))
 		return
 	}
 
@@ -164,59 +193,60 @@ func getPathsWithMaxWeight(c *gin.Context) {
 
 	exactWeight, err = strconv.ParseBool(exactWeightString)
 	if err != nil {
-		c.JSON(500, gin.H{
-			"error": "wrong Exact",
+		respondError(c, invalidParameter("Exact", "has to be true or false"))
+		return
+	}
+
+	// Streaming relevant paths as they are found if the client asks for it
+	if format, streaming := streamFormat(c); streaming {
+		streamPaths(c, format, func(emit core.PathFunc) error {
+			return graph.StreamPathsWithMaxWeight(c.Request.Context(), initialNode, endNode, maxWeight, exactWeight, emit)
 		})
+
 		return
 	}
 
 	// Calculating relevant paths
-	relevantPaths = graph.GeneratePathsWithMaxWeight(initialNode, endNode, maxWeight, exactWeight)
+	relevantPaths, err = graph.GeneratePathsWithMaxWeight(c.Request.Context(), initialNode, endNode, maxWeight, exactWeight)
+	if err = partialResults(c, err); err != nil {
+		respondError(c, err)
+		return
+	}
 
 	// Binding relevantPaths with request
-	c.JSON(200, relevantPaths)
+	respondPaths(c, &graph, relevantPaths)
 }
 
 // getLowestHighestWeightPath calls GenerateLowestHighestWeightPath.
 // Header requirements:
-// Initial and end nodes: "Nodes": "<node1><node2>" / for example: "Nodes": "AB"
+// Initial and end nodes: "From": "<node1>", "To": "<node2>" / for example: "From": "A", "To": "Zürich"
 // Lowest or highest weighted path: "Lowest": "<true/false>"
+// The "Strategy" response header tells how the paths were found.
 // In case of malformed graph or header file the function exits,
 // and it gives an error response.
 func getLowestHighestWeightPath(c *gin.Context) {
 	var (
 		graph                core.Graph
-		endNodesString       string
 		initialNode, endNode core.Node
 		lowestString         string
 		lowest               bool
 		relevantPaths        []core.Path
+		strategy             core.Strategy
+		ok                   bool
+		err                  error
 	)
 
-	// Decoding request body that contains the graph
-	err := json.NewDecoder(c.Request.Body).Decode(&graph)
-	if err != nil {
-		c.JSON(500, gin.H{
-			"error": malformedGraphErrorMessage,
-		})
+	// Loading the graph from the request body or from the repository
+	if !bindGraph(c, &graph) {
 		return
 	}
 
-	// Identifying initial and end node from request header
-	// Request header has to contain information about the initial and end nodes in the following way:
-	// "Nodes": "AB"
-	endNodesString = c.Query("Nodes")
-
-	if len(endNodesString) != 2 {
-		c.JSON(500, gin.H{
-			"error": malformedNodesErrorMessage,
-		})
+	// Identifying initial and end node from request parameters
+	initialNode, endNode, ok = parseEndNodes(c, &graph)
+	if !ok {
 		return
 	}
 
-	initialNode.Name = endNodesString[:1]
-	endNode.Name = endNodesString[1:]
-
 	// Identifying maximum or minimum sum weight of a path from request header
 	// Request header has to contain information in the following way:
 	// "Lowest": "true"
@@ -224,67 +254,445 @@ func getLowestHighestWeightPath(c *gin.Context) {
 	// false if calculation is for highest weighted path.
 	lowestString = c.Query("Lowest")
 	if len(lowestString) == 0 {
-		c.JSON(500, gin.H{
-			"error": "wrong Lowest",
-		})
+		respondError(c, invalidParameter("Lowest", "has to be true or false"))
 		return
 	}
 
 	lowest, err = strconv.ParseBool(lowestString)
 	if err != nil {
-		c.JSON(500, gin.H{
-			"error": "wrong Lowest",
-		})
+		respondError(c, invalidParameter("Lowest", "has to be true or false"))
+		return
+	}
+
+	// Calculating relevant paths
+	relevantPaths, strategy, err = graph.GenerateLowestHighestWeightPathWithStrategy(c.Request.Context(), initialNode, endNode, lowest)
+	if err = partialResults(c, err); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	// Reporting how the paths were found
+	c.Header(strategyHeader, string(strategy))
+
+	// Binding relevantPaths with request
+	respondPaths(c, &graph, relevantPaths)
+}
+
+// getBellmanFordPath calls GenerateLowestWeightPathsBellmanFord.
+// Edge weights can be negative.
+// Header requirements:
+// Initial and end nodes: "From": "<node1>", "To": "<node2>" / for example: "From": "A", "To": "Zürich"
+// In case of malformed graph or header file the function exits,
+// and it gives an error response.
+// In case of a negative weighted cycle reachable from the initial node,
+// the error response contains the cycle.
+func getBellmanFordPath(c *gin.Context) {
+	var (
+		graph                core.Graph
+		initialNode, endNode core.Node
+		relevantPaths        []core.Path
+		ok                   bool
+		err                  error
+	)
+
+	// Loading the graph from the request body or from the repository
+	if !bindGraph(c, &graph) {
+		return
+	}
+
+	// Identifying initial and end node from request parameters
+	initialNode, endNode, ok = parseEndNodes(c, &graph)
+	if !ok {
 		return
 	}
 
 	// Calculating relevant paths
-	relevantPaths = graph.GenerateLowestHighestWeightPath(initialNode, endNode, lowest)
+	relevantPaths, err = graph.GenerateLowestWeightPathsBellmanFord(c.Request.Context(), initialNode, endNode)
+	if err = partialResults(c, err); err != nil {
+		respondError(c, err)
+		return
+	}
 
 	// Binding relevantPaths with request
-	c.JSON(200, relevantPaths)
+	respondPaths(c, &graph, relevantPaths)
+}
+
+// getAllPairsPaths calls GenerateAllPairsPaths.
+// It responds with the lowest distances between every pair of nodes,
+// and with the next-hop table rebuilding the corresponding paths.
+// In case of malformed graph the function exits, and it gives an error response.
+// In case of a negative weighted cycle, the error response contains the cycle.
+func getAllPairsPaths(c *gin.Context) {
+	var (
+		graph    core.Graph
+		allPairs core.AllPairsPaths
+		err      error
+	)
+
+	// Loading the graph from the request body or from the repository
+	if !bindGraph(c, &graph) {
+		return
+	}
+
+	// Calculating lowest distances
+	allPairs, err = graph.GenerateAllPairsPaths(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	// Binding allPairs with request, as the graph of the lowest weights in graph formats
+	if respondFormat(c) != gin.MIMEJSON {
+		distances := interchange.AllPairsGraph(allPairs)
+
+		respondGraph(c, &distances)
+		return
+	}
+
+	c.JSON(200, allPairs)
+}
+
+// getKShortestPaths calls GenerateKShortestPaths.
+// Header requirements:
+// Initial and end nodes: "From": "<node1>", "To": "<node2>" / for example: "From": "A", "To": "Zürich"
+// Number of paths: "K": "<a positive integer>"
+// In case of malformed graph or header file the function exits,
+// and it gives an error response.
+func getKShortestPaths(c *gin.Context) {
+	var (
+		graph                core.Graph
+		initialNode, endNode core.Node
+		kString              string
+		k                    int
+		relevantPaths        []core.Path
+		ok                   bool
+		err                  error
+	)
+
+	// Loading the graph from the request body or from the repository
+	if !bindGraph(c, &graph) {
+		return
+	}
+
+	// Identifying initial and end node from request parameters
+	initialNode, endNode, ok = parseEndNodes(c, &graph)
+	if !ok {
+		return
+	}
+
+	// Identifying number of paths from request header
+	// Request header has to contain information in the following way:
+	// "K": "<a positive integer>"
+	kString = c.Query("K")
+
+	k, err = strconv.Atoi(kString)
+	if err != nil || k <= 0 {
+		respondError(c, invalidParameter("K", "has to be a positive integer"))
+		return
+	}
+
+	// Calculating relevant paths
+	relevantPaths, err = graph.GenerateKShortestPaths(c.Request.Context(), initialNode, endNode, k)
+	if err = partialResults(c, err); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	// Binding relevantPaths with request
+	respondPaths(c, &graph, relevantPaths)
+}
+
+// getAStarPath calls GenerateAStarPath.
+// Header requirements:
+// Initial and end nodes: "From": "<node1>", "To": "<node2>" / for example: "From": "A", "To": "Zürich"
+// Heuristic guiding the search: "Heuristic": "<euclidean/manhattan/haversine/none>"
+// Heuristics use the positions of the nodes of the graph.
+// In case of malformed graph or header file the function exits,
+// and it gives an error response.
+func getAStarPath(c *gin.Context) {
+	var (
+		graph                core.Graph
+		initialNode, endNode core.Node
+		heuristic            core.Heuristic
+		relevantPaths        []core.Path
+		ok                   bool
+		err                  error
+	)
+
+	// Loading the graph from the request body or from the repository
+	if !bindGraph(c, &graph) {
+		return
+	}
+
+	// Identifying initial and end node from request parameters
+	initialNode, endNode, ok = parseEndNodes(c, &graph)
+	if !ok {
+		return
+	}
+
+	// Identifying heuristic from request header
+	// Request header has to contain information in the following way:
+	// "Heuristic": "euclidean"
+	heuristic, ok = core.Heuristics[c.Query("Heuristic")]
+	if !ok {
+		respondError(c, invalidParameter("Heuristic", "has to be euclidean, manhattan, haversine or none"))
+		return
+	}
+
+	// Calculating relevant paths
+	relevantPaths, err = graph.GenerateAStarPath(c.Request.Context(), initialNode, endNode, heuristic)
+	if err = partialResults(c, err); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	// Binding relevantPaths with request
+	respondPaths(c, &graph, relevantPaths)
+}
+
+// getSpanningTree calls MinimumSpanningForestKruskal, MinimumSpanningForestPrim or MinimumArborescence.
+// Header requirements:
+// Algorithm: "Algorithm": "<kruskal/prim/edmonds>"
+// Kruskal and Prim treat edges as undirected, Edmonds finds a directed arborescence.
+// Root node of the arborescence, for Edmonds only: "Root": "<node>" / for example: "Root": "Zürich"
+// In case of malformed graph or header file the function exits,
+// and it gives an error response.
+func getSpanningTree(c *gin.Context) {
+	var (
+		graph core.Graph
+		root  core.Node
+		tree  core.SpanningTree
+	)
+
+	// Loading the graph from the request body or from the repository
+	if !bindGraph(c, &graph) {
+		return
+	}
+
+	// Identifying algorithm from request header
+	// Request header has to contain information in the following way:
+	// "Algorithm": "kruskal"
+	switch c.Query("Algorithm") {
+	case "kruskal":
+		tree = graph.MinimumSpanningForestKruskal()
+	case "prim":
+		tree = graph.MinimumSpanningForestPrim()
+	case "edmonds":
+		// Identifying root node from request header
+		// Request header has to contain information in the following way:
+		// "Root": "A"
+		root.Name = c.Query("Root")
+
+		if len(root.Name) == 0 {
+			respondError(c, invalidParameter("Root", "is required for edmonds"))
+			return
+		}
+
+		if !graph.HasNode(root) {
+			respondError(c, &core.UnknownNodeError{Node: root, Field: "Root"})
+			return
+		}
+
+		tree = graph.MinimumArborescence(root)
+	default:
+		respondError(c, invalidParameter("Algorithm", "has to be kruskal, prim or edmonds"))
+		return
+	}
+
+	// Binding tree with request, as a path on the graph in graph formats
+	if respondFormat(c) != gin.MIMEJSON {
+		respondPaths(c, &graph, []core.Path{{Subgraph: tree.Subgraph, Weight: tree.Weight}})
+		return
+	}
+
+	c.JSON(200, tree)
+}
+
+// getComponents calls Components.
+// It responds with the strongly and weakly connected components of the graph,
+// and with the condensation of the graph.
+// In case of malformed graph the function exits, and it gives an error response.
+func getComponents(c *gin.Context) {
+	var graph core.Graph
+
+	// Loading the graph from the request body or from the repository
+	if !bindGraph(c, &graph) {
+		return
+	}
+
+	components := graph.Components()
+
+	// Binding components with request, annotated on the graph in graph formats
+	if respondFormat(c) != gin.MIMEJSON {
+		annotated := interchange.AnnotateComponents(graph, components)
+
+		respondGraph(c, &annotated)
+		return
+	}
+
+	c.JSON(200, components)
+}
+
+// getCycles calls GenerateCycles.
+// Header options:
+// Maximum number of edges in a cycle: "MaxEdges": "<a positive integer>", unlimited if missing
+// Maximum sum weight of a cycle: "MaxWeight": "<a floating point number>", unlimited if missing
+// In case of malformed graph or header file the function exits,
+// and it gives an error response.
+func getCycles(c *gin.Context) {
+	var (
+		graph           core.Graph
+		maxEdgesString  string
+		maxEdges        = -1
+		maxWeightString string
+		maxWeight       = -1.0
+		cycles          []core.Path
+		err             error
+	)
+
+	// Loading the graph from the request body or from the repository
+	if !bindGraph(c, &graph) {
+		return
+	}
+
+	// Identifying maximum number of edges from request header
+	// Request header can contain information in the following way:
+	// "MaxEdges": "<a positive integer>"
+	if maxEdgesString = c.Query("MaxEdges"); len(maxEdgesString) != 0 {
+		maxEdges, err = strconv.Atoi(maxEdgesString)
+		if err != nil || maxEdges <= 0 {
+			respondError(c, invalidParameter("MaxEdges", "has to be a positive integer"))
+			return
+		}
+	}
+
+	// Identifying maximum sum weight from request header
+	// Request header can contain information in the following way:
+	// "MaxWeight": "<a floating point number>"
+	if maxWeightString = c.Query("MaxWeight"); len(maxWeightString) != 0 {
+		maxWeight, err = strconv.ParseFloat(maxWeightString, 64)
+		if err != nil {
+			respondError(c, invalidParameter("MaxWeight", "has to be a floating point number"))
+			return
+		}
+	}
+
+	// Calculating cycles
+	cycles, err = graph.GenerateCycles(c.Request.Context(), maxEdges, maxWeight)
+	if err = partialResults(c, err); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	// Binding cycles with request
+	respondPaths(c, &graph, cycles)
+}
+
+// getCycle calls FindCycle.
+// It responds whether the graph has a cycle, and with one cycle if there is any.
+// In case of malformed graph the function exits, and it gives an error response.
+func getCycle(c *gin.Context) {
+	var graph core.Graph
+
+	// Loading the graph from the request body or from the repository
+	if !bindGraph(c, &graph) {
+		return
+	}
+
+	cycle, ok := graph.FindCycle()
+
+	// Binding cycle with request, as a path on the graph in graph formats
+	if respondFormat(c) != gin.MIMEJSON {
+		var cycles []core.Path
+
+		if ok {
+			cycles = append(cycles, cycle)
+		}
+
+		respondPaths(c, &graph, cycles)
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"cyclic": ok,
+		"cycle":  cycle,
+	})
+}
+
+// getMaxFlow calls MaxFlow.
+// Edge weights are used as capacities.
+// Header requirements:
+// Source and sink nodes: "From": "<node1>", "To": "<node2>" / for example: "From": "A", "To": "Zürich"
+// In case of malformed graph or header file the function exits,
+// and it gives an error response.
+func getMaxFlow(c *gin.Context) {
+	var (
+		graph                core.Graph
+		initialNode, endNode core.Node
+		flow                 core.Flow
+		ok                   bool
+		err                  error
+	)
+
+	// Loading the graph from the request body or from the repository
+	if !bindGraph(c, &graph) {
+		return
+	}
+
+	// Identifying source and sink node from request parameters
+	initialNode, endNode, ok = parseEndNodes(c, &graph)
+	if !ok {
+		return
+	}
+
+	// Calculating maximum flow
+	flow, err = graph.MaxFlow(initialNode, endNode)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	// Binding flow with request, annotated on the graph in graph formats
+	if respondFormat(c) != gin.MIMEJSON {
+		annotated := interchange.AnnotateFlow(graph, flow)
+
+		respondGraph(c, &annotated)
+		return
+	}
+
+	c.JSON(200, flow)
 }
 
 // getShortestLongestPath calls GenerateShortestLongestPath.
 // Header requirements:
-// Initial and end nodes: "Nodes": "<node1><node2>" / for example: "Nodes": "AB"
+// Initial and end nodes: "From": "<node1>", "To": "<node2>" / for example: "From": "A", "To": "Zürich"
 // Shortest or longest path: "Shortest": "<true/false>"
+// The "Strategy" response header tells how the paths were found.
 // In case of malformed graph or header file the function exits,
 // and it gives an error response.
 func getShortestLongestPath(c *gin.Context) {
 	var (
 		graph                core.Graph
-		endNodesString       string
 		initialNode, endNode core.Node
 		shortestString       string
 		shortest             bool
 		relevantPaths        []core.Path
+		strategy             core.Strategy
+		ok                   bool
+		err                  error
 	)
 
-	// Decoding request body that contains the graph
-	err := json.NewDecoder(c.Request.Body).Decode(&graph)
-	if err != nil {
-		c.JSON(500, gin.H{
-			"error": malformedGraphErrorMessage,
-		})
+	// Loading the graph from the request body or from the repository
+	if !bindGraph(c, &graph) {
 		return
 	}
 
-	// Identifying initial and end node from request header
-	// Request header has to contain information about the initial and end nodes in the following way:
-	// "Nodes": "AB"
-	endNodesString = c.Query("Nodes")
-
-	if len(endNodesString) != 2 {
-		c.JSON(500, gin.H{
-			"error": malformedNodesErrorMessage,
-		})
+	// Identifying initial and end node from request parameters
+	initialNode, endNode, ok = parseEndNodes(c, &graph)
+	if !ok {
 		return
 	}
 
-	initialNode.Name = endNodesString[:1]
-	endNode.Name = endNodesString[1:]
-
 	// Identifying shortest or longest path from request header
 	// Request header has to contain information in the following way:
 	// "Shortest": "true"
@@ -292,35 +700,61 @@ func getShortestLongestPath(c *gin.Context) {
 	// false if calculation is for longest path.
 	shortestString = c.Query("Shortest")
 	if len(shortestString) == 0 {
-		c.JSON(500, gin.H{
-			"error": "wrong Shortest",
-		})
+		respondError(c, invalidParameter("Shortest", "has to be true or false"))
 		return
 	}
 
 	shortest, err = strconv.ParseBool(shortestString)
 	if err != nil {
-		c.JSON(500, gin.H{
-			"error": "wrong Shortest",
-		})
+		respondError(c, invalidParameter("Shortest", "has to be true or false"))
 		return
 	}
 
 	// Calculating relevant paths
-	relevantPaths = graph.GenerateShortestLongestPath(initialNode, endNode, shortest)
+	relevantPaths, strategy, err = graph.GenerateShortestLongestPathWithStrategy(c.Request.Context(), initialNode, endNode, shortest)
+	if err = partialResults(c, err); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	// Reporting how the paths were found
+	c.Header(strategyHeader, string(strategy))
 
 	// Binding relevantPaths with request
-	c.JSON(200, relevantPaths)
+	respondPaths(c, &graph, relevantPaths)
 }
 
+// renderGraph draws the graph as a picture.
+// It responds with an SVG picture, or a PNG picture if the client prefers "image/png".
+// Header options are the options of pictures (see renderOptions).
+// In case of malformed graph or header file the function exits,
+// and it gives an error response.
+func renderGraph(c *gin.Context) {
+	var graph core.Graph
+
+	// Loading the graph from the request body or from the repository
+	if !bindGraph(c, &graph) {
+		return
+	}
+
+	// Binding the picture with request
+	format := c.NegotiateFormat(render.SVGMediaType, render.PNGMediaType)
+
+	respondPicture(c, format, &graph, nil)
+}
+
+// frontendOrigin is the origin of the frontend, the only origin allowed to reach the backend from browsers.
+const frontendOrigin string = "http://34.76.180.95"
+
 // cors enables middleware handling.
 // It connects frontend to backend with certain settings.
-// Backend application is allowed to be reached from the origin defined here.
+// Backend application is allowed to be reached from frontendOrigin.
 func cors() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		c.Writer.Header().Set("Access-Control-Allow-Origin", "http://34.76.180.95")
+		c.Writer.Header().Set("Access-Control-Allow-Origin", frontendOrigin)
 		c.Writer.Header().Set("Access-Control-Allow-Headers", "*")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
+		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+		c.Writer.Header().Set("Access-Control-Expose-Headers", strategyHeader+", "+truncatedHeader)
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
@@ -332,11 +766,33 @@ func cors() gin.HandlerFunc {
 }
 
 func main() {
+	var err error
+
+	repository, err = newRepository()
+	if err != nil {
+		log.Fatalf("opening graph repository: %v", err)
+	}
+
+	defaultValidation, err = newDefaultValidation()
+	if err != nil {
+		log.Fatalf("reading %v: %v", validationEnv, err)
+	}
+
+	limits, err = newSearchLimits()
+	if err != nil {
+		log.Fatalf("reading search limits: %v", err)
+	}
+
+	newRouter().Run(":8080")
+}
+
+// newRouter returns the router of every endpoint.
+func newRouter() *gin.Engine {
 	router := gin.Default()
 
 	router.SetTrustedProxies([]string{"http://34.76.180.95"})
 
-	router.Use(cors())
+	router.Use(cors(), limitBodies(), limitSearches())
 
 	// Generating all the paths with <= #steps
 	router.POST("/maxSteps", getPathsWithMaxSteps)
@@ -347,8 +803,57 @@ func main() {
 	// Generating lowest/highest weighted paths
 	router.POST("/highLowWeight", getLowestHighestWeightPath)
 
+	// Generating lowest weighted paths, negative weights allowed
+	router.POST("/bellmanFord", getBellmanFordPath)
+
+	// Generating the K lowest weighted paths in order
+	router.POST("/kShortest", getKShortestPaths)
+
+	// Generating lowest weighted path guided by node positions
+	router.POST("/aStar", getAStarPath)
+
+	// Generating maximum flow and minimum cut
+	router.POST("/maxFlow", getMaxFlow)
+
+	// Generating lowest distances between every pair of nodes
+	router.POST("/allPairs", getAllPairsPaths)
+
+	// Generating minimum spanning forest or arborescence
+	router.POST("/spanningTree", getSpanningTree)
+
+	// Generating strongly and weakly connected components
+	router.POST("/components", getComponents)
+
+	// Generating every elementary cycle
+	router.POST("/cycles", getCycles)
+
+	// Detecting a cycle
+	router.POST("/findCycle", getCycle)
+
 	// Generating shoertest/longest paths
 	router.POST("/shortLong", getShortestLongestPath)
 
-	router.Run(":8080")
+	// Drawing graphs as SVG or PNG pictures
+	router.GET("/render", renderGraph)
+	router.POST("/render", renderGraph)
+
+	// Managing stored graphs
+	// Analysis endpoints run against a stored graph if the "GraphID" parameter is given.
+	router.POST("/graphs", createGraph)
+	router.GET("/graphs", listGraphs)
+	router.GET("/graphs/:id", getGraph)
+	router.PUT("/graphs/:id", updateGraph)
+	router.DELETE("/graphs/:id", deleteGraph)
+	router.GET("/graphs/:id/revisions", listRevisions)
+	router.GET("/graphs/:id/revisions/:revision", getRevision)
+	router.GET("/graphs/:id/diff", diffGraph)
+	router.POST("/graphs/:id/nodes", addNode)
+	router.DELETE("/graphs/:id/nodes/:name", removeNode)
+	router.POST("/graphs/:id/edges", addEdge)
+	router.DELETE("/graphs/:id/edges", removeEdge)
+
+	// Editing a graph with live analyses over WebSocket
+	router.GET("/session", openSession)
+
+	return router
 }
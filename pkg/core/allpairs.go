@@ -0,0 +1,274 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+)
+
+// Algorithms computing all-pairs lowest distances.
+const (
+	FloydWarshall = "floyd-warshall" // O(V^3), used for dense graphs
+	Johnson       = "johnson"        // O(VE log V), used for sparse graphs
+)
+
+// AllPairsPaths holds the lowest distances between every pair of nodes of a graph,
+// and the next-hop table rebuilding the corresponding lowest weighted paths.
+type AllPairsPaths struct {
+	Nodes     []Node      `json:"nodes"`     // row and column order of the tables
+	Distances [][]float64 `json:"distances"` // +Inf if unreachable
+	NextHops  [][]int     `json:"nextHops"`  // index of the next node on the path, -1 if unreachable
+	Algorithm string      `json:"algorithm"` // FloydWarshall or Johnson
+
+	graph    *Graph
+	idx      *index
+	hopEdges [][]int // index of the first edge of the path in graph.Edges, -1 if unreachable
+}
+
+// MarshalJSON encodes a, unreachable distances are encoded as null.
+func (a AllPairsPaths) MarshalJSON() ([]byte, error) {
+	distances := make([][]*float64, len(a.Distances))
+
+	for i := range a.Distances {
+		distances[i] = make([]*float64, len(a.Distances[i]))
+
+		for j := range a.Distances[i] {
+			if !math.IsInf(a.Distances[i][j], 1) {
+				distances[i][j] = &a.Distances[i][j]
+			}
+		}
+	}
+
+	return json.Marshal(struct {
+		Nodes     []Node       `json:"nodes"`
+		Distances [][]*float64 `json:"distances"`
+		NextHops  [][]int      `json:"nextHops"`
+		Algorithm string       `json:"algorithm"`
+	}{a.Nodes, distances, a.NextHops, a.Algorithm})
+}
+
+// Path rebuilds the lowest weighted path from node1 to node2.
+// ok is false if node2 is not reachable from node1.
+// The path from a node to itself is empty.
+func (a *AllPairsPaths) Path(node1, node2 Node) (path Path, ok bool) {
+	i, ok1 := a.idx.id(node1)
+	j, ok2 := a.idx.id(node2)
+
+	if !ok1 || !ok2 || math.IsInf(a.Distances[i][j], 1) {
+		return path, false
+	}
+
+	var edges []int
+
+	for i != j {
+		edges = append(edges, a.hopEdges[i][j])
+		i = a.idx.to[a.hopEdges[i][j]]
+	}
+
+	return a.graph.pathFromEdges(edges), true
+}
+
+// MaxAllPairsNodes is the number of nodes of the graphs GenerateAllPairsPaths accepts at most,
+// as its tables grow with the square of the number of nodes.
+const MaxAllPairsNodes int = 2000
+
+// isDense reports whether a graph with v nodes and e edges is dense enough
+// for Floyd-Warshall to be faster than Johnson's algorithm.
+func isDense(v, e int) bool {
+	return float64(e)*math.Log2(float64(v)+1) >= float64(v*v)
+}
+
+// GenerateAllPairsPaths computes the lowest distances between every pair of nodes of g.
+// Floyd-Warshall is used for dense graphs and Johnson's algorithm for sparse ones.
+// Edge weights can be negative. If g contains a negative weighted cycle,
+// it returns a *NegativeCycleError.
+// If ctx is done, it returns a *TruncatedError without any result.
+// It returns a *GraphTooLargeError if g has more than MaxAllPairsNodes nodes.
+func (g *Graph) GenerateAllPairsPaths(ctx context.Context) (AllPairsPaths, error) {
+	if nodes := len(g.internNodes().nodes); nodes > MaxAllPairsNodes {
+		return AllPairsPaths{}, &GraphTooLargeError{Nodes: nodes, Edges: len(g.Edges), MaxNodes: MaxAllPairsNodes}
+	}
+
+	var (
+		idx        = g.buildIndex()
+		search     = newSearch(ctx)
+		potentials []float64
+		cycle      []int
+		allPairs   AllPairsPaths
+	)
+
+	// Looking for negative cycles anywhere in g
+	if idx.hasNegativeWeight() {
+		potentials, cycle = idx.potentials(search, idx.weight)
+		if cycle != nil {
+			path := g.pathFromEdges(cycle)
+
+			return allPairs, &NegativeCycleError{Cycle: path}
+		}
+	}
+
+	if isDense(len(idx.nodes), len(g.Edges)) {
+		allPairs = idx.floydWarshall(search, idx.weight)
+	} else {
+		allPairs = idx.johnson(search, idx.weight, potentials)
+	}
+
+	if search.stopped() {
+		return AllPairsPaths{}, search.result()
+	}
+
+	allPairs.graph, allPairs.idx = g, idx
+	allPairs.Nodes = append([]Node{}, idx.nodes...)
+	allPairs.NextHops = make([][]int, len(idx.nodes))
+
+	for i := range allPairs.hopEdges {
+		allPairs.NextHops[i] = make([]int, len(idx.nodes))
+
+		for j, e := range allPairs.hopEdges[i] {
+			allPairs.NextHops[i][j] = -1
+
+			if e != -1 {
+				allPairs.NextHops[i][j] = idx.to[e]
+			}
+		}
+	}
+
+	return allPairs, nil
+}
+
+// newAllPairsTables returns distance and hop edge tables for idx
+// with every node unreachable apart from itself.
+func (idx *index) newAllPairsTables() ([][]float64, [][]int) {
+	var (
+		distances = make([][]float64, len(idx.nodes))
+		hopEdges  = make([][]int, len(idx.nodes))
+	)
+
+	for i := range idx.nodes {
+		distances[i] = make([]float64, len(idx.nodes))
+		hopEdges[i] = make([]int, len(idx.nodes))
+
+		for j := range idx.nodes {
+			distances[i][j] = math.Inf(1)
+			hopEdges[i][j] = -1
+		}
+
+		distances[i][i] = 0
+	}
+
+	return distances, hopEdges
+}
+
+// floydWarshall computes all-pairs lowest distances with the Floyd-Warshall algorithm.
+// There must not be any negative cycle.
+// Every row of every round is a step of s; the tables are incomplete if s stops.
+func (idx *index) floydWarshall(s *searchState, weight func(e int) float64) AllPairsPaths {
+	distances, hopEdges := idx.newAllPairsTables()
+
+	for e := range idx.from {
+		u, v := idx.from[e], idx.to[e]
+
+		if w := weight(e); u != v && w < distances[u][v] {
+			distances[u][v] = w
+			hopEdges[u][v] = e
+		}
+	}
+
+	for k := range idx.nodes {
+		for i := range idx.nodes {
+			if !s.step() {
+				break
+			}
+
+			if math.IsInf(distances[i][k], 1) {
+				continue
+			}
+
+			for j := range idx.nodes {
+				if d := distances[i][k] + distances[k][j]; d < distances[i][j] {
+					distances[i][j] = d
+					hopEdges[i][j] = hopEdges[i][k]
+				}
+			}
+		}
+	}
+
+	return AllPairsPaths{
+		Distances: distances,
+		Algorithm: FloydWarshall,
+		hopEdges:  hopEdges,
+	}
+}
+
+// johnson computes all-pairs lowest distances with Johnson's algorithm:
+// edges are reweighted by the node potentials to be non-negative, then Dijkstra's algorithm
+// is run from every node. potentials can be nil if there is no negative weight.
+// The tables are incomplete if search stops.
+func (idx *index) johnson(search *searchState, weight func(e int) float64, potentials []float64) AllPairsPaths {
+	var (
+		distances, hopEdges = idx.newAllPairsTables()
+		reweighted          = weight
+	)
+
+	if potentials != nil {
+		reweighted = func(e int) float64 {
+			// Rounding errors must not make weights negative
+			return math.Max(0, weight(e)+potentials[idx.from[e]]-potentials[idx.to[e]])
+		}
+	}
+
+	for s := range idx.nodes {
+		d, preds := idx.dijkstra(search, s, reweighted)
+		if search.stopped() {
+			break
+		}
+
+		for t := range idx.nodes {
+			if t == s || math.IsInf(d[t], 1) {
+				continue
+			}
+
+			distances[s][t] = d[t]
+
+			if potentials != nil {
+				distances[s][t] += potentials[t] - potentials[s]
+			}
+
+			// Walking up the lowest distance tree until a node with known first edge
+			var (
+				chain []int
+				node  = t
+				first int
+			)
+
+			for {
+				e := preds[node][0]
+
+				if idx.from[e] == s {
+					first = e
+					break
+				}
+
+				if hopEdges[s][idx.from[e]] != -1 {
+					first = hopEdges[s][idx.from[e]]
+					break
+				}
+
+				chain = append(chain, node)
+				node = idx.from[e]
+			}
+
+			hopEdges[s][node] = first
+
+			for _, n := range chain {
+				hopEdges[s][n] = first
+			}
+		}
+	}
+
+	return AllPairsPaths{
+		Distances: distances,
+		Algorithm: Johnson,
+		hopEdges:  hopEdges,
+	}
+}
@@ -0,0 +1,161 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strconv"
+	"testing"
+)
+
+func TestGenerateAllPairsPaths(t *testing.T) {
+	t.Parallel()
+
+	nodeA := Node{Name: "A"}
+	nodeB := Node{Name: "B"}
+	nodeC := Node{Name: "C"}
+	nodeD := Node{Name: "D"}
+	nodeE := Node{Name: "E"}
+
+	edgeAB := Edge{
+		Nodes:  [2]Node{nodeA, nodeB},
+		Weight: 5,
+	}
+
+	edgeBC := Edge{
+		Nodes:  [2]Node{nodeB, nodeC},
+		Weight: 4,
+	}
+
+	edgeCD := Edge{
+		Nodes:  [2]Node{nodeC, nodeD},
+		Weight: 8,
+	}
+
+	edgeDC := Edge{
+		Nodes:  [2]Node{nodeD, nodeC},
+		Weight: 8,
+	}
+
+	edgeDE := Edge{
+		Nodes:  [2]Node{nodeD, nodeE},
+		Weight: -6,
+	}
+
+	edgeAD := Edge{
+		Nodes:  [2]Node{nodeA, nodeD},
+		Weight: 5,
+	}
+
+	edgeCE := Edge{
+		Nodes:  [2]Node{nodeC, nodeE},
+		Weight: 2,
+	}
+
+	edgeEB := Edge{
+		Nodes:  [2]Node{nodeE, nodeB},
+		Weight: 3,
+	}
+
+	edgeAE := Edge{
+		Nodes:  [2]Node{nodeA, nodeE},
+		Weight: 7,
+	}
+
+	graph := Graph{
+		Nodes: []Node{nodeA, nodeB, nodeC, nodeD, nodeE},
+		Edges: []Edge{edgeAB, edgeBC, edgeCD, edgeDC, edgeDE, edgeAD, edgeCE, edgeEB, edgeAE},
+	}
+
+	// Case 1: both algorithms agree with Bellman-Ford on every pair
+	allPairs, err := graph.GenerateAllPairsPaths(context.Background())
+
+	if err != nil {
+		t.Fatalf("GenerateAllPairsPaths did not work. Got error %v", err)
+	}
+
+	var (
+		idx    = graph.buildIndex()
+		search = newSearch(context.Background())
+	)
+
+	potentials, _ := idx.potentials(search, idx.weight)
+
+	for _, result := range []AllPairsPaths{allPairs, idx.floydWarshall(search, idx.weight), idx.johnson(search, idx.weight, potentials)} {
+		result.graph, result.idx = &graph, idx
+
+		for i, n1 := range graph.Nodes {
+			for j, n2 := range graph.Nodes {
+				expected := math.Inf(1)
+
+				if i == j {
+					expected = 0
+				} else if paths, _ := graph.GenerateLowestWeightPathsBellmanFord(context.Background(), n1, n2); len(paths) > 0 {
+					expected = paths[0].Weight
+				}
+
+				if result.Distances[i][j] != expected {
+					t.Errorf("%v did not work from %v to %v. Got %v instead of %v",
+						result.Algorithm, n1, n2, result.Distances[i][j], expected)
+				}
+
+				path, ok := result.Path(n1, n2)
+
+				if ok != !math.IsInf(expected, 1) {
+					t.Errorf("%v did not work from %v to %v. Got %v instead of %v", result.Algorithm, n1, n2, ok, !ok)
+				} else if ok && path.Weight != expected {
+					t.Errorf("%v did not work from %v to %v. Got %v instead of %v", result.Algorithm, n1, n2, path.Weight, expected)
+				}
+			}
+		}
+	}
+
+	// Case 2: unreachable nodes are encoded as null
+	encoded, err := allPairs.MarshalJSON()
+
+	if err != nil {
+		t.Errorf("GenerateAllPairsPaths did not work. Got error %v", err)
+	} else if len(encoded) == 0 {
+		t.Errorf("GenerateAllPairsPaths did not work. Got empty JSON")
+	}
+
+	// Case 3: negative cycle
+	graph.Edges = append(graph.Edges, Edge{
+		Nodes:  [2]Node{nodeE, nodeD},
+		Weight: 1,
+	})
+
+	_, err = graph.GenerateAllPairsPaths(context.Background())
+
+	var cycleErr *NegativeCycleError
+
+	if !errors.As(err, &cycleErr) {
+		t.Errorf("GenerateAllPairsPaths did not work. Got error %v instead of a negative cycle", err)
+	} else if cycleErr.Cycle.Weight != -5 {
+		t.Errorf("GenerateAllPairsPaths did not work. Got %v instead of %v", cycleErr.Cycle.Weight, -5)
+	}
+}
+
+func TestGenerateAllPairsPathsLimit(t *testing.T) {
+	t.Parallel()
+
+	var graph Graph
+
+	for i := 0; i < MaxAllPairsNodes; i++ {
+		graph.Nodes = append(graph.Nodes, Node{Name: strconv.Itoa(i)})
+	}
+
+	// Case 1: graphs of MaxAllPairsNodes nodes are accepted
+	if _, err := graph.GenerateAllPairsPaths(context.Background()); err != nil {
+		t.Errorf("GenerateAllPairsPaths did not work. Got error %v", err)
+	}
+
+	// Case 2: larger graphs are refused, nodes only appearing in edges count
+	graph.Edges = append(graph.Edges, Edge{Nodes: [2]Node{graph.Nodes[0], {Name: "extra"}}, Weight: 1})
+
+	var tooLargeErr *GraphTooLargeError
+
+	if _, err := graph.GenerateAllPairsPaths(context.Background()); !errors.As(err, &tooLargeErr) || tooLargeErr.Nodes != MaxAllPairsNodes+1 {
+		t.Errorf("GenerateAllPairsPaths did not work. Got error %v instead of a *GraphTooLargeError", err)
+	}
+}
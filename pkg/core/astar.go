@@ -0,0 +1,184 @@
+package core
+
+import (
+	"container/heap"
+	"context"
+	"math"
+)
+
+// earthRadius is the mean radius of the Earth in kilometres.
+const earthRadius = 6371.0
+
+// Heuristic estimates the weight of the lowest weighted path from node1 to node2 for A* search.
+// The estimate must not exceed the actual weight, otherwise the path found may not be the lowest weighted.
+type Heuristic interface {
+	Estimate(node1, node2 Node) float64
+}
+
+// HeuristicFunc is an adapter to use ordinary functions as Heuristic.
+type HeuristicFunc func(node1, node2 Node) float64
+
+// Estimate calls f(node1, node2).
+func (f HeuristicFunc) Estimate(node1, node2 Node) float64 {
+	return f(node1, node2)
+}
+
+// Built-in heuristics. They estimate 0 if any of the nodes has no position.
+var (
+	// Euclidean estimates the straight-line distance between the positions of the nodes.
+	Euclidean Heuristic = HeuristicFunc(func(node1, node2 Node) float64 {
+		if node1.Position == nil || node2.Position == nil {
+			return 0
+		}
+
+		return math.Hypot(node1.Position.X-node2.Position.X, node1.Position.Y-node2.Position.Y)
+	})
+
+	// Manhattan estimates the axis-aligned distance between the positions of the nodes.
+	Manhattan Heuristic = HeuristicFunc(func(node1, node2 Node) float64 {
+		if node1.Position == nil || node2.Position == nil {
+			return 0
+		}
+
+		return math.Abs(node1.Position.X-node2.Position.X) + math.Abs(node1.Position.Y-node2.Position.Y)
+	})
+
+	// Haversine estimates the great-circle distance in kilometres between the positions of the nodes,
+	// where X is the longitude and Y is the latitude in degrees.
+	Haversine Heuristic = HeuristicFunc(func(node1, node2 Node) float64 {
+		if node1.Position == nil || node2.Position == nil {
+			return 0
+		}
+
+		var (
+			lat1 = node1.Position.Y * math.Pi / 180
+			lat2 = node2.Position.Y * math.Pi / 180
+			dLat = lat2 - lat1
+			dLon = (node2.Position.X - node1.Position.X) * math.Pi / 180
+			a    = math.Pow(math.Sin(dLat/2), 2) + math.Cos(lat1)*math.Cos(lat2)*math.Pow(math.Sin(dLon/2), 2)
+		)
+
+		return 2 * earthRadius * math.Asin(math.Min(1, math.Sqrt(a)))
+	})
+
+	// NoHeuristic estimates 0, turning A* search into Dijkstra's algorithm.
+	NoHeuristic Heuristic = HeuristicFunc(func(node1, node2 Node) float64 {
+		return 0
+	})
+)
+
+// Heuristics are the built-in heuristics by name.
+var Heuristics = map[string]Heuristic{
+	"euclidean": Euclidean,
+	"manhattan": Manhattan,
+	"haversine": Haversine,
+	"none":      NoHeuristic,
+}
+
+// GenerateAStarPath finds a lowest weighted path from node1 to node2 using A* search
+// guided by heuristic. Positions of the nodes are taken from g.Nodes.
+// If node1 equals node2, the lowest weighted cycle through node1 is searched for without guidance.
+// Edge weights must not be negative, otherwise an *InvalidWeightError is returned.
+// It returns the path found, or no path if node2 is not reachable from node1.
+// It returns an *UnknownNodeError if node1 or node2 is not a node of g.
+// If ctx is done, it returns no path with a *TruncatedError.
+func (g *Graph) GenerateAStarPath(ctx context.Context, node1, node2 Node, heuristic Heuristic) ([]Path, error) {
+	if err := g.checkNonNegativeWeights(); err != nil {
+		return nil, err
+	}
+
+	var (
+		idx    = g.buildIndex()
+		search = newSearch(ctx)
+	)
+
+	src, ok1 := idx.id(node1)
+	dst, ok2 := idx.id(node2)
+
+	if !ok1 || !ok2 {
+		return nil, g.checkNodes(node1, node2)
+	}
+
+	if src == dst {
+		distances, preds := idx.dijkstra(search, src, idx.weight)
+		if search.stopped() {
+			return []Path{}, search.result()
+		}
+
+		if sequence := idx.lowestWeightSequence(distances, preds, src, dst, idx.weight); sequence != nil {
+			return []Path{g.pathFromEdges(sequence)}, nil
+		}
+
+		return []Path{}, nil
+	}
+
+	var (
+		distances = make([]float64, len(idx.nodes))
+		estimates = make([]float64, len(idx.nodes))
+		parents   = make([]int, len(idx.nodes))
+		queue     = &distanceHeap{}
+	)
+
+	for i := range distances {
+		distances[i] = math.Inf(1)
+		estimates[i] = math.NaN()
+		parents[i] = -1
+	}
+
+	// estimate caches the heuristic estimate from node to dst
+	estimate := func(node int) float64 {
+		if math.IsNaN(estimates[node]) {
+			estimates[node] = heuristic.Estimate(idx.nodes[node], idx.nodes[dst])
+		}
+
+		return estimates[node]
+	}
+
+	distances[src] = 0
+	heap.Push(queue, distanceItem{node: src, distance: estimate(src)})
+
+	for queue.Len() > 0 {
+		item := heap.Pop(queue).(distanceItem)
+
+		// Skipping outdated queue entries
+		if item.distance > distances[item.node]+estimate(item.node) {
+			continue
+		}
+
+		if item.node == dst {
+			break
+		}
+
+		if !search.step() {
+			return []Path{}, search.result()
+		}
+
+		for _, e := range idx.out(item.node) {
+			next := idx.to[e]
+
+			// Nodes can be reopened, so that admissible but inconsistent heuristics work as well
+			if d := distances[item.node] + idx.weights[e]; d < distances[next] {
+				distances[next] = d
+				parents[next] = e
+
+				heap.Push(queue, distanceItem{node: next, distance: d + estimate(next)})
+			}
+		}
+	}
+
+	if math.IsInf(distances[dst], 1) {
+		return []Path{}, nil
+	}
+
+	var sequence []int
+
+	for node := dst; node != src; node = idx.from[parents[node]] {
+		sequence = append(sequence, parents[node])
+	}
+
+	for i, j := 0, len(sequence)-1; i < j; i, j = i+1, j-1 {
+		sequence[i], sequence[j] = sequence[j], sequence[i]
+	}
+
+	return []Path{g.pathFromEdges(sequence)}, nil
+}
@@ -0,0 +1,140 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestHeuristics(t *testing.T) {
+	t.Parallel()
+
+	nodeA := Node{Name: "A", Position: &Position{X: 0, Y: 0}}
+	nodeB := Node{Name: "B", Position: &Position{X: 3, Y: 4}}
+	nodeC := Node{Name: "C"}
+
+	if estimate := Euclidean.Estimate(nodeA, nodeB); estimate != 5 {
+		t.Errorf("Euclidean did not work. Got %v instead of %v", estimate, 5)
+	}
+
+	if estimate := Manhattan.Estimate(nodeA, nodeB); estimate != 7 {
+		t.Errorf("Manhattan did not work. Got %v instead of %v", estimate, 7)
+	}
+
+	if estimate := Euclidean.Estimate(nodeA, nodeC); estimate != 0 {
+		t.Errorf("Euclidean did not work. Got %v instead of %v", estimate, 0)
+	}
+
+	if estimate := NoHeuristic.Estimate(nodeA, nodeB); estimate != 0 {
+		t.Errorf("NoHeuristic did not work. Got %v instead of %v", estimate, 0)
+	}
+
+	// Budapest to Vienna is about 214 km
+	budapest := Node{Name: "Budapest", Position: &Position{X: 19.0402, Y: 47.4979}}
+	vienna := Node{Name: "Vienna", Position: &Position{X: 16.3738, Y: 48.2082}}
+
+	if estimate := Haversine.Estimate(budapest, vienna); math.Abs(estimate-214) > 2 {
+		t.Errorf("Haversine did not work. Got %v instead of %v", estimate, 214)
+	}
+}
+
+func TestGenerateAStarPath(t *testing.T) {
+	t.Parallel()
+
+	nodeA := Node{Name: "A", Position: &Position{X: 0, Y: 0}}
+	nodeB := Node{Name: "B", Position: &Position{X: 1, Y: 0}}
+	nodeC := Node{Name: "C", Position: &Position{X: 2, Y: 0}}
+	nodeD := Node{Name: "D", Position: &Position{X: 1, Y: 1}}
+	nodeE := Node{Name: "E", Position: &Position{X: 2, Y: 1}}
+
+	edgeAB := Edge{
+		Nodes:  [2]Node{nodeA, nodeB},
+		Weight: 1,
+	}
+
+	edgeBC := Edge{
+		Nodes:  [2]Node{nodeB, nodeC},
+		Weight: 1,
+	}
+
+	edgeAD := Edge{
+		Nodes:  [2]Node{nodeA, nodeD},
+		Weight: 1.5,
+	}
+
+	edgeDE := Edge{
+		Nodes:  [2]Node{nodeD, nodeE},
+		Weight: 1,
+	}
+
+	edgeEC := Edge{
+		Nodes:  [2]Node{nodeE, nodeC},
+		Weight: 1,
+	}
+
+	edgeCA := Edge{
+		Nodes:  [2]Node{nodeC, nodeA},
+		Weight: 2,
+	}
+
+	graph := Graph{
+		Nodes: []Node{nodeA, nodeB, nodeC, nodeD, nodeE},
+		Edges: []Edge{edgeAB, edgeBC, edgeAD, edgeDE, edgeEC, edgeCA},
+	}
+
+	for _, heuristic := range []Heuristic{Euclidean, Manhattan, HeuristicFunc(func(node1, node2 Node) float64 { return 0 })} {
+		// Case 1: same weight as Dijkstra's algorithm on every pair
+		for _, n1 := range graph.Nodes {
+			for _, n2 := range graph.Nodes {
+				var (
+					paths, err = graph.GenerateAStarPath(context.Background(), n1, n2, heuristic)
+					lowest, _  = graph.GenerateLowestWeightPaths(context.Background(), n1, n2)
+					expected   = 0
+				)
+
+				if err != nil {
+					t.Errorf("GenerateAStarPath did not work. Got error %v", err)
+				}
+
+				if len(lowest) > 0 {
+					expected = 1
+				}
+
+				if len(paths) != expected {
+					t.Errorf("GenerateAStarPath did not work. Got %v paths from %v to %v", len(paths), n1, n2)
+				} else if len(paths) > 0 && paths[0].Weight != lowest[0].Weight {
+					t.Errorf("GenerateAStarPath did not work. Got %v instead of %v", paths[0].Weight, lowest[0].Weight)
+				}
+			}
+		}
+
+		// Case 2: path along the nodes
+		paths, _ := graph.GenerateAStarPath(context.Background(), nodeA, nodeC, heuristic)
+
+		if len(paths) != 1 {
+			t.Errorf("GenerateAStarPath did not work. Got %v instead of %v", len(paths), 1)
+		} else {
+			subgraph := paths[0].Subgraph
+
+			nodes := []Node{nodeA, nodeB, nodeC}
+
+			if len(subgraph.Nodes) != len(nodes) {
+				t.Errorf("GenerateAStarPath did not work. Got %v instead of %v", len(subgraph.Nodes), len(nodes))
+			} else {
+				for i, n := range subgraph.Nodes {
+					if !n.Equals(nodes[i]) {
+						t.Errorf("GenerateAStarPath did not work. Got %v instead of %v", n, nodes[i])
+					}
+				}
+			}
+		}
+	}
+
+	// Case 3: negative weights
+	graph.Edges[0].Weight = -1
+
+	if _, err := graph.GenerateAStarPath(context.Background(), nodeA, nodeC, Euclidean); !errors.Is(err, ErrNegativeWeight) {
+		t.Errorf("GenerateAStarPath did not work. Got error %v instead of %v", err, ErrNegativeWeight)
+	}
+}
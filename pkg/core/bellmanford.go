@@ -0,0 +1,191 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// NegativeCycleError is returned when a negative weighted cycle is reachable from the start node.
+// Lowest weights are meaningless in that case, since walking the cycle again always lowers them.
+type NegativeCycleError struct {
+	Cycle Path // the negative weighted cycle
+}
+
+// Error returns the description of the negative weighted cycle.
+func (e *NegativeCycleError) Error() string {
+	names := make([]string, 0, len(e.Cycle.Subgraph.Nodes))
+
+	for _, n := range e.Cycle.Subgraph.Nodes {
+		names = append(names, n.Name)
+	}
+
+	return fmt.Sprintf("negative weighted cycle %s with weight %v", strings.Join(names, "->"), e.Cycle.Weight)
+}
+
+// bellmanFord computes the lowest distances from src to every node of idx.
+// weight returns the weight of an edge by its index; edges weighing +Inf are skipped.
+// Weights can be negative.
+// It returns the distances by node id (+Inf if unreachable) and,
+// by node id, every edge reaching the node at its lowest distance.
+// If a negative weighted cycle is reachable from src, its edge sequence is returned instead.
+// The distances are incomplete if s stops (see relaxRounds).
+func (idx *index) bellmanFord(s *searchState, src int, weight func(e int) float64) ([]float64, [][]int, []int) {
+	var (
+		distances = make([]float64, len(idx.nodes))
+		parents   = make([]int, len(idx.nodes)) // last edge of the current lowest path by node id
+	)
+
+	for i := range distances {
+		distances[i] = math.Inf(1)
+		parents[i] = -1
+	}
+
+	distances[src] = 0
+
+	if relaxed := idx.relaxRounds(s, distances, parents, weight); relaxed != -1 {
+		return nil, nil, idx.parentCycle(parents, idx.to[relaxed])
+	}
+
+	preds := make([][]int, len(idx.nodes))
+
+	for e := range idx.from {
+		w := weight(e)
+		u, v := idx.from[e], idx.to[e]
+
+		if v == src || math.IsInf(distances[u], 1) || math.IsInf(w, 1) {
+			continue
+		}
+
+		if distances[u]+w == distances[v] {
+			preds[v] = append(preds[v], e)
+		}
+	}
+
+	return distances, preds, nil
+}
+
+// potentials computes the lowest distances of every node of idx from a virtual node
+// connected to all of them with zero weighted edges.
+// If idx contains a negative weighted cycle, its edge sequence is returned instead.
+// The distances are incomplete if s stops (see relaxRounds).
+func (idx *index) potentials(s *searchState, weight func(e int) float64) ([]float64, []int) {
+	var (
+		distances = make([]float64, len(idx.nodes))
+		parents   = make([]int, len(idx.nodes))
+	)
+
+	for i := range parents {
+		parents[i] = -1
+	}
+
+	if relaxed := idx.relaxRounds(s, distances, parents, weight); relaxed != -1 {
+		return nil, idx.parentCycle(parents, idx.to[relaxed])
+	}
+
+	return distances, nil
+}
+
+// relaxRounds relaxes every edge of idx |V| times, updating distances and parents.
+// A relaxation in the last round proves a negative weighted cycle:
+// it returns the last relaxed edge in that case; otherwise -1.
+// Every relaxed edge is a step of s; it returns -1 if s stops.
+func (idx *index) relaxRounds(s *searchState, distances []float64, parents []int, weight func(e int) float64) int {
+	relaxed := -1
+
+	for round := 0; round < len(idx.nodes); round++ {
+		relaxed = -1
+
+		for e := range idx.from {
+			if !s.step() {
+				return -1
+			}
+
+			w := weight(e)
+			u, v := idx.from[e], idx.to[e]
+
+			if math.IsInf(distances[u], 1) || math.IsInf(w, 1) {
+				continue
+			}
+
+			if d := distances[u] + w; d < distances[v] {
+				distances[v] = d
+				parents[v] = e
+				relaxed = e
+			}
+		}
+
+		if relaxed == -1 {
+			break
+		}
+	}
+
+	return relaxed
+}
+
+// parentCycle returns the edge sequence of the cycle found by walking parents back from node.
+func (idx *index) parentCycle(parents []int, node int) []int {
+	// Walking back |V| steps surely ends up on the cycle
+	for i := 0; i < len(idx.nodes); i++ {
+		node = idx.from[parents[node]]
+	}
+
+	var (
+		cycle []int
+		start = node
+	)
+
+	for {
+		e := parents[node]
+		cycle = append(cycle, e)
+		node = idx.from[e]
+
+		if node == start {
+			break
+		}
+	}
+
+	// Reversing to walking order
+	for i, j := 0, len(cycle)-1; i < j; i, j = i+1, j-1 {
+		cycle[i], cycle[j] = cycle[j], cycle[i]
+	}
+
+	return cycle
+}
+
+// GenerateLowestWeightPathsBellmanFord finds every lowest weighted path from node1 to node2
+// using the Bellman-Ford algorithm, so edge weights can be negative.
+// If node1 equals node2, the lowest weighted cycles through node1 are searched for.
+// If a negative weighted cycle is reachable from node1, it returns the cycle as the only Path
+// together with a *NegativeCycleError.
+// It returns an *UnknownNodeError if node1 or node2 is not a node of g.
+// If ctx is done or its budget is exhausted, it returns the paths enumerated until then with a *TruncatedError.
+func (g *Graph) GenerateLowestWeightPathsBellmanFord(ctx context.Context, node1, node2 Node) ([]Path, error) {
+	var (
+		idx    = g.buildIndex()
+		search = newSearch(ctx)
+	)
+
+	src, ok1 := idx.id(node1)
+	dst, ok2 := idx.id(node2)
+
+	if !ok1 || !ok2 {
+		return nil, g.checkNodes(node1, node2)
+	}
+
+	distances, preds, cycle := idx.bellmanFord(search, src, idx.weight)
+	if cycle != nil {
+		path := g.pathFromEdges(cycle)
+
+		return []Path{path}, &NegativeCycleError{Cycle: path}
+	}
+
+	if search.stopped() {
+		return []Path{}, search.result()
+	}
+
+	paths := g.pathsFromSequences(idx.lowestWeightSequences(search, distances, preds, src, dst, idx.weight))
+
+	return paths, search.result()
+}
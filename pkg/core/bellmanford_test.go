@@ -0,0 +1,139 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestGenerateLowestWeightPathsBellmanFord(t *testing.T) {
+	t.Parallel()
+
+	nodeA := Node{Name: "A"}
+	nodeB := Node{Name: "B"}
+	nodeC := Node{Name: "C"}
+	nodeD := Node{Name: "D"}
+
+	edgeAB := Edge{
+		Nodes:  [2]Node{nodeA, nodeB},
+		Weight: 4,
+	}
+
+	edgeAC := Edge{
+		Nodes:  [2]Node{nodeA, nodeC},
+		Weight: 2,
+	}
+
+	edgeCB := Edge{
+		Nodes:  [2]Node{nodeC, nodeB},
+		Weight: -3,
+	}
+
+	edgeBD := Edge{
+		Nodes:  [2]Node{nodeB, nodeD},
+		Weight: 1,
+	}
+
+	graph := Graph{
+		Nodes: []Node{nodeA, nodeB, nodeC, nodeD},
+		Edges: []Edge{edgeAB, edgeAC, edgeCB, edgeBD},
+	}
+
+	// Case 1: path along a negative edge
+	paths, err := graph.GenerateLowestWeightPathsBellmanFord(context.Background(), nodeA, nodeD)
+
+	if err != nil {
+		t.Errorf("GenerateLowestWeightPathsBellmanFord did not work. Got error %v", err)
+	}
+
+	if len(paths) != 1 {
+		t.Errorf("GenerateLowestWeightPathsBellmanFord did not work. Got %v instead of %v", len(paths), 1)
+	} else {
+		subgraph := paths[0].Subgraph
+
+		nodes := []Node{nodeA, nodeC, nodeB, nodeD}
+		edges := []Edge{edgeAC, edgeCB, edgeBD}
+
+		if paths[0].Weight != 0 {
+			t.Errorf("GenerateLowestWeightPathsBellmanFord did not work. Got %v instead of %v", paths[0].Weight, 0)
+		}
+
+		if len(subgraph.Nodes) != len(nodes) {
+			t.Errorf("GenerateLowestWeightPathsBellmanFord did not work. Got %v instead of %v", len(subgraph.Nodes), len(nodes))
+		} else {
+			for i, n := range subgraph.Nodes {
+				if n != nodes[i] {
+					t.Errorf("GenerateLowestWeightPathsBellmanFord did not work. Got %v instead of %v", n, nodes[i])
+				}
+			}
+		}
+
+		if len(subgraph.Edges) != len(edges) {
+			t.Errorf("GenerateLowestWeightPathsBellmanFord did not work. Got %v instead of %v", len(subgraph.Edges), len(edges))
+		} else {
+			for i, tr := range subgraph.Edges {
+				if !tr.Equals(&edges[i]) {
+					t.Errorf("GenerateLowestWeightPathsBellmanFord did not work. Got %v instead of %v", tr, edges[i])
+				}
+			}
+		}
+	}
+
+	// Case 2: no path found
+	paths, err = graph.GenerateLowestWeightPathsBellmanFord(context.Background(), nodeD, nodeA)
+
+	if err != nil {
+		t.Errorf("GenerateLowestWeightPathsBellmanFord did not work. Got error %v", err)
+	}
+
+	if len(paths) != 0 {
+		t.Errorf("GenerateLowestWeightPathsBellmanFord did not work. Got %v instead of %v", len(paths), 0)
+	}
+
+	// Case 3: negative cycle reachable from the start node
+	edgeDC := Edge{
+		Nodes:  [2]Node{nodeD, nodeC},
+		Weight: -5,
+	}
+
+	graph.Edges = append(graph.Edges, edgeDC)
+
+	paths, err = graph.GenerateLowestWeightPathsBellmanFord(context.Background(), nodeA, nodeD)
+
+	var cycleErr *NegativeCycleError
+
+	if !errors.As(err, &cycleErr) {
+		t.Errorf("GenerateLowestWeightPathsBellmanFord did not work. Got error %v instead of a negative cycle", err)
+	} else if cycleErr.Cycle.Weight != -7 {
+		t.Errorf("GenerateLowestWeightPathsBellmanFord did not work. Got %v instead of %v", cycleErr.Cycle.Weight, -7)
+	}
+
+	if len(paths) != 1 {
+		t.Errorf("GenerateLowestWeightPathsBellmanFord did not work. Got %v instead of %v", len(paths), 1)
+	} else {
+		subgraph := paths[0].Subgraph
+
+		if len(subgraph.Edges) != 3 {
+			t.Errorf("GenerateLowestWeightPathsBellmanFord did not work. Got %v instead of %v", len(subgraph.Edges), 3)
+		}
+
+		if subgraph.Nodes[0] != subgraph.Nodes[len(subgraph.Nodes)-1] {
+			t.Errorf("GenerateLowestWeightPathsBellmanFord did not work. Got %v instead of %v",
+				subgraph.Nodes[len(subgraph.Nodes)-1], subgraph.Nodes[0])
+		}
+	}
+
+	// Case 4: negative cycle not reachable from the start node
+	paths, err = (&Graph{
+		Nodes: graph.Nodes,
+		Edges: []Edge{edgeAB, edgeDC, {Nodes: [2]Node{nodeC, nodeD}, Weight: 1}},
+	}).GenerateLowestWeightPathsBellmanFord(context.Background(), nodeA, nodeB)
+
+	if err != nil {
+		t.Errorf("GenerateLowestWeightPathsBellmanFord did not work. Got error %v", err)
+	}
+
+	if len(paths) != 1 {
+		t.Errorf("GenerateLowestWeightPathsBellmanFord did not work. Got %v instead of %v", len(paths), 1)
+	}
+}
@@ -0,0 +1,161 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+// Reasons of a *TruncatedError besides the errors of contexts.
+var (
+	ErrStateBudgetExhausted = errors.New("explored state budget exhausted")
+	ErrPathBudgetExhausted  = errors.New("path budget exhausted")
+)
+
+// errStopped stops searches whose results are not wanted anymore, such as streams whose client failed.
+var errStopped = errors.New("search stopped")
+
+// contextCheckInterval is the number of steps of a search between checks of its context.
+const contextCheckInterval int = 1024
+
+// Budget limits the work of the searches run with a context (see WithBudget).
+// Zero fields are unlimited.
+type Budget struct {
+	MaxStates int // maximum number of states explored by path enumerations, such as partial paths
+	MaxPaths  int // maximum number of paths found by path enumerations
+}
+
+// budgetKey is the context key of budgets.
+type budgetKey struct{}
+
+// WithBudget returns a copy of ctx carrying budget.
+// The Generate* functions of Graph run with the returned context stop once budget is exhausted.
+func WithBudget(ctx context.Context, budget Budget) context.Context {
+	return context.WithValue(ctx, budgetKey{}, budget)
+}
+
+// BudgetFromContext returns the budget carried by ctx, or an unlimited budget if there is none.
+func BudgetFromContext(ctx context.Context) Budget {
+	budget, _ := ctx.Value(budgetKey{}).(Budget)
+
+	return budget
+}
+
+// searchState tracks the progress of a search against its context and budget.
+// Searches split across goroutines share their progress through forks (see fork).
+type searchState struct {
+	ctx      context.Context
+	budget   Budget
+	steps    int // steps of this goroutine, to check the context
+	progress *searchProgress
+}
+
+// searchProgress is the progress of a search shared by its forks.
+type searchProgress struct {
+	states  int64 // explored states, accessed atomically
+	paths   int64 // paths found, accessed atomically
+	stopped int32 // 1 once err is set, accessed atomically
+	mu      sync.Mutex
+	err     error // reason of stopping the search, nil while it goes on
+}
+
+// newSearch returns a search run with ctx and the budget it carries.
+func newSearch(ctx context.Context) *searchState {
+	return &searchState{
+		ctx:      ctx,
+		budget:   BudgetFromContext(ctx),
+		progress: &searchProgress{},
+	}
+}
+
+// fork returns a search sharing the budget and the progress of s, to be used by another goroutine.
+// Once any of them stops, every one stops.
+func (s *searchState) fork() *searchState {
+	return &searchState{
+		ctx:      s.ctx,
+		budget:   s.budget,
+		progress: s.progress,
+	}
+}
+
+// halt stops the search because of err, unless it has already stopped.
+func (s *searchState) halt(err error) {
+	s.progress.mu.Lock()
+	defer s.progress.mu.Unlock()
+
+	if s.progress.err == nil {
+		s.progress.err = err
+		atomic.StoreInt32(&s.progress.stopped, 1)
+	}
+}
+
+// step counts a step of the search, checking the context every contextCheckInterval steps.
+// It returns false if the search has to stop.
+func (s *searchState) step() bool {
+	if s.stopped() {
+		return false
+	}
+
+	if s.steps++; s.steps%contextCheckInterval == 1 {
+		if err := s.ctx.Err(); err != nil {
+			s.halt(err)
+			return false
+		}
+	}
+
+	return true
+}
+
+// explore counts a state explored by a path enumeration.
+// It returns false if the search has to stop.
+func (s *searchState) explore() bool {
+	if !s.step() {
+		return false
+	}
+
+	states := atomic.AddInt64(&s.progress.states, 1)
+
+	switch {
+	case s.budget.MaxStates > 0 && states > int64(s.budget.MaxStates):
+		s.halt(ErrStateBudgetExhausted)
+	case s.budget.MaxPaths > 0 && atomic.LoadInt64(&s.progress.paths) >= int64(s.budget.MaxPaths):
+		s.halt(ErrPathBudgetExhausted)
+	}
+
+	return !s.stopped()
+}
+
+// found counts a path found by a path enumeration.
+// It returns false, stopping the search, if the path is beyond the budget;
+// otherwise the search stops at its next explored state once the budget is reached.
+func (s *searchState) found() bool {
+	if paths := atomic.AddInt64(&s.progress.paths, 1); s.budget.MaxPaths > 0 && paths > int64(s.budget.MaxPaths) {
+		s.halt(ErrPathBudgetExhausted)
+		return false
+	}
+
+	return true
+}
+
+// stop stops the search at its next step.
+func (s *searchState) stop() {
+	s.halt(errStopped)
+}
+
+// stopped reports whether the search has stopped.
+func (s *searchState) stopped() bool {
+	return atomic.LoadInt32(&s.progress.stopped) == 1
+}
+
+// result returns a *TruncatedError if the search has stopped before its end; otherwise nil.
+func (s *searchState) result() error {
+	s.progress.mu.Lock()
+	defer s.progress.mu.Unlock()
+
+	if s.progress.err == nil {
+		return nil
+	}
+
+	return &TruncatedError{Err: s.progress.err}
+}
@@ -0,0 +1,112 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// completeGraph returns the complete directed graph of n nodes with unit weights.
+func completeGraph(n int) Graph {
+	var graph Graph
+
+	for i := 0; i < n; i++ {
+		graph.Nodes = append(graph.Nodes, Node{Name: fmt.Sprint(i)})
+	}
+
+	for _, n1 := range graph.Nodes {
+		for _, n2 := range graph.Nodes {
+			if !n1.Equals(n2) {
+				graph.Edges = append(graph.Edges, Edge{Nodes: [2]Node{n1, n2}, Weight: 1})
+			}
+		}
+	}
+
+	return graph
+}
+
+func TestBudget(t *testing.T) {
+	t.Parallel()
+
+	var (
+		graph        = completeGraph(7)
+		node1, node2 = graph.Nodes[0], graph.Nodes[6]
+		truncated    *TruncatedError
+	)
+
+	// Case 1: paths are limited, the paths found until then are returned
+	ctx := WithBudget(context.Background(), Budget{MaxPaths: 5})
+
+	paths, err := graph.GeneratePathsWithoutEdgeRepetition(ctx, node1, node2)
+	if !errors.As(err, &truncated) || !errors.Is(err, ErrPathBudgetExhausted) || len(paths) != 5 {
+		t.Errorf("GeneratePathsWithoutEdgeRepetition did not work. Got %v paths and error %v instead of 5 paths and %v",
+			len(paths), err, ErrPathBudgetExhausted)
+	}
+
+	cycles, err := graph.GenerateCycles(ctx, -1, -1)
+	if !errors.Is(err, ErrPathBudgetExhausted) || len(cycles) != 5 {
+		t.Errorf("GenerateCycles did not work. Got %v cycles and error %v instead of 5 cycles and %v",
+			len(cycles), err, ErrPathBudgetExhausted)
+	}
+
+	paths, err = graph.GenerateKShortestPaths(ctx, node1, node2, 10)
+	if !errors.Is(err, ErrPathBudgetExhausted) || len(paths) != 5 || paths[0].Weight != 1 {
+		t.Errorf("GenerateKShortestPaths did not work. Got %v paths and error %v instead of 5 paths and %v",
+			len(paths), err, ErrPathBudgetExhausted)
+	}
+
+	ctx = WithBudget(context.Background(), Budget{MaxPaths: 3})
+
+	// Parallel edges close several cycles at once
+	parallel := Graph{Nodes: []Node{node1, node2}, Edges: []Edge{{Nodes: [2]Node{node1, node2}, Weight: 1}}}
+
+	for i := 0; i < 5; i++ {
+		parallel.Edges = append(parallel.Edges, Edge{Nodes: [2]Node{node2, node1}, Weight: float64(i)})
+	}
+
+	cycles, err = parallel.GenerateCycles(ctx, -1, -1)
+	if !errors.Is(err, ErrPathBudgetExhausted) || len(cycles) != 3 {
+		t.Errorf("GenerateCycles did not work. Got %v cycles and error %v instead of 3 cycles and %v",
+			len(cycles), err, ErrPathBudgetExhausted)
+	}
+
+	// The 6 tied lowest weighted cycles through node1
+	paths, err = graph.GenerateLowestWeightPaths(ctx, node1, node1)
+	if !errors.Is(err, ErrPathBudgetExhausted) || len(paths) != 3 {
+		t.Errorf("GenerateLowestWeightPaths did not work. Got %v paths and error %v instead of 3 paths and %v",
+			len(paths), err, ErrPathBudgetExhausted)
+	}
+
+	// Case 2: explored states are limited
+	ctx = WithBudget(context.Background(), Budget{MaxStates: 100})
+
+	paths, _, err = graph.GenerateShortestLongestPathWithStrategy(ctx, node1, node2, true)
+	if !errors.Is(err, ErrStateBudgetExhausted) || len(paths) == 0 {
+		t.Errorf("GenerateShortestLongestPathWithStrategy did not work. Got %v paths and error %v instead of %v",
+			len(paths), err, ErrStateBudgetExhausted)
+	}
+
+	// Case 3: cancelled searches stop at once
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if paths, err = graph.GeneratePathsWithMaxSteps(ctx, node1, node2, 6, false); !errors.Is(err, context.Canceled) || len(paths) != 0 {
+		t.Errorf("GeneratePathsWithMaxSteps did not work. Got %v paths and error %v instead of %v", len(paths), err, context.Canceled)
+	}
+
+	if _, err = graph.GenerateAllPairsPaths(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("GenerateAllPairsPaths did not work. Got error %v instead of %v", err, context.Canceled)
+	}
+
+	if _, err = graph.GenerateLowestWeightPaths(ctx, node1, node2); !errors.Is(err, context.Canceled) {
+		t.Errorf("GenerateLowestWeightPaths did not work. Got error %v instead of %v", err, context.Canceled)
+	}
+
+	// Case 4: searches within the budget are complete
+	ctx = WithBudget(context.Background(), Budget{MaxStates: 1000, MaxPaths: 10})
+
+	if paths, err = graph.GenerateLowestWeightPaths(ctx, node1, node2); err != nil || len(paths) != 1 {
+		t.Errorf("GenerateLowestWeightPaths did not work. Got %v paths and error %v instead of 1 path", len(paths), err)
+	}
+}
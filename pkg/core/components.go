@@ -0,0 +1,226 @@
+package core
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Components describes the connectivity structure of a graph.
+type Components struct {
+	Strong       [][]Node `json:"strong"`       // strongly connected components in topological order
+	Weak         [][]Node `json:"weak"`         // weakly connected components
+	Condensation Graph    `json:"condensation"` // DAG of the strongly connected components
+}
+
+// strongComponents finds the strongly connected components of idx using Tarjan's algorithm.
+// It returns the component of every node by node id and the number of components.
+// Components are numbered in topological order.
+func (idx *index) strongComponents() ([]int, int) {
+	var (
+		components = make([]int, len(idx.nodes))
+		indices    = make([]int, len(idx.nodes)) // discovery order, starting from 1
+		lowLinks   = make([]int, len(idx.nodes))
+		onStack    = make([]bool, len(idx.nodes))
+		stack      []int
+		count      int
+		counter    int
+	)
+
+	// frame is a node being visited together with its next outgoing edge
+	type frame struct {
+		node, next int
+	}
+
+	for start := range idx.nodes {
+		if indices[start] != 0 {
+			continue
+		}
+
+		calls := []frame{{node: start}}
+
+		for len(calls) > 0 {
+			f := &calls[len(calls)-1]
+			v := f.node
+
+			if f.next == 0 && indices[v] == 0 {
+				counter++
+				indices[v], lowLinks[v] = counter, counter
+				stack = append(stack, v)
+				onStack[v] = true
+			}
+
+			// Visiting the next outgoing edge
+			if f.next < len(idx.out(v)) {
+				w := idx.to[idx.out(v)[f.next]]
+				f.next++
+
+				if indices[w] == 0 {
+					calls = append(calls, frame{node: w})
+				} else if onStack[w] && indices[w] < lowLinks[v] {
+					lowLinks[v] = indices[w]
+				}
+
+				continue
+			}
+
+			// Every edge is visited: v is the root of a component or passes its low link to its caller
+			if lowLinks[v] == indices[v] {
+				for {
+					w := stack[len(stack)-1]
+					stack = stack[:len(stack)-1]
+					onStack[w] = false
+					components[w] = count
+
+					if w == v {
+						break
+					}
+				}
+
+				count++
+			}
+
+			calls = calls[:len(calls)-1]
+
+			if len(calls) > 0 {
+				if u := calls[len(calls)-1].node; lowLinks[v] < lowLinks[u] {
+					lowLinks[u] = lowLinks[v]
+				}
+			}
+		}
+	}
+
+	// Tarjan's algorithm finds the components in reverse topological order
+	for v := range components {
+		components[v] = count - 1 - components[v]
+	}
+
+	return components, count
+}
+
+// groupNodes groups the nodes of idx by their component.
+func (idx *index) groupNodes(components []int, count int) [][]Node {
+	groups := make([][]Node, count)
+
+	for v, c := range components {
+		groups[c] = append(groups[c], idx.nodes[v])
+	}
+
+	return groups
+}
+
+// StronglyConnectedComponents returns the strongly connected components of g
+// in topological order: no edge leads from a component to an earlier one.
+func (g *Graph) StronglyConnectedComponents() [][]Node {
+	idx := g.buildIndex()
+
+	return idx.groupNodes(idx.strongComponents())
+}
+
+// weakComponents finds the connected components of idx with edges treated as undirected.
+// It returns the component of every node by node id and the number of components.
+func (idx *index) weakComponents() ([]int, int) {
+	var (
+		sets       = newUnionFind(len(idx.nodes))
+		components = make([]int, len(idx.nodes))
+		ids        = make(map[int]int)
+	)
+
+	for e := range idx.from {
+		sets.union(idx.from[e], idx.to[e])
+	}
+
+	for v := range components {
+		root := sets.find(v)
+
+		if _, ok := ids[root]; !ok {
+			ids[root] = len(ids)
+		}
+
+		components[v] = ids[root]
+	}
+
+	return components, len(ids)
+}
+
+// WeaklyConnectedComponents returns the connected components of g with edges treated as undirected.
+func (g *Graph) WeaklyConnectedComponents() [][]Node {
+	idx := g.buildIndex()
+
+	return idx.groupNodes(idx.weakComponents())
+}
+
+// condensation returns the condensation of idx from the strongly connected component
+// of every node and the nodes grouped by component, as given by strongComponents and groupNodes.
+func (idx *index) condensation(components []int, groups [][]Node) Graph {
+	var (
+		condensation Graph
+		weights      = make(map[[2]int]float64)
+		pairs        [][2]int
+	)
+
+	for i, group := range groups {
+		names := make([]string, len(group))
+
+		for j, n := range group {
+			names[j] = n.Name
+		}
+
+		node := Node{Name: strconv.Itoa(i)}
+		node.SetAttribute("members", strings.Join(names, ","))
+
+		condensation.Nodes = append(condensation.Nodes, node)
+	}
+
+	for e := range idx.from {
+		pair := [2]int{components[idx.from[e]], components[idx.to[e]]}
+		if pair[0] == pair[1] {
+			continue
+		}
+
+		weight, ok := weights[pair]
+		if !ok {
+			weight = math.Inf(1)
+			pairs = append(pairs, pair)
+		}
+
+		weights[pair] = math.Min(weight, idx.weights[e])
+	}
+
+	for _, pair := range pairs {
+		condensation.Edges = append(condensation.Edges, Edge{
+			Nodes:  [2]Node{condensation.Nodes[pair[0]], condensation.Nodes[pair[1]]},
+			Weight: weights[pair],
+		})
+	}
+
+	return condensation
+}
+
+// Condensation returns the condensation of g: a DAG whose nodes are the strongly connected
+// components of g, named by their index in StronglyConnectedComponents.
+// The names of the member nodes are listed in the "members" attribute of each node.
+// An edge leads from one component to another if any edge of g does,
+// with the lowest weight among those edges.
+func (g *Graph) Condensation() Graph {
+	idx := g.buildIndex()
+	components, count := idx.strongComponents()
+
+	return idx.condensation(components, idx.groupNodes(components, count))
+}
+
+// Components returns the strongly and weakly connected components of g and its condensation.
+// g is indexed, and its strongly connected components are found, only once for all three.
+func (g *Graph) Components() Components {
+	var (
+		idx               = g.buildIndex()
+		components, count = idx.strongComponents()
+		strong            = idx.groupNodes(components, count)
+	)
+
+	return Components{
+		Strong:       strong,
+		Weak:         idx.groupNodes(idx.weakComponents()),
+		Condensation: idx.condensation(components, strong),
+	}
+}
@@ -0,0 +1,131 @@
+package core
+
+import (
+	"math/rand"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestComponents(t *testing.T) {
+	t.Parallel()
+
+	nodeA := Node{Name: "A"}
+	nodeB := Node{Name: "B"}
+	nodeC := Node{Name: "C"}
+	nodeD := Node{Name: "D"}
+	nodeE := Node{Name: "E"}
+	nodeF := Node{Name: "F"}
+	nodeG := Node{Name: "G"}
+
+	graph := Graph{
+		Nodes: []Node{nodeA, nodeB, nodeC, nodeD, nodeE, nodeF, nodeG},
+		Edges: []Edge{
+			{Nodes: [2]Node{nodeA, nodeB}, Weight: 5},
+			{Nodes: [2]Node{nodeB, nodeC}, Weight: 4},
+			{Nodes: [2]Node{nodeC, nodeD}, Weight: 8},
+			{Nodes: [2]Node{nodeD, nodeC}, Weight: 8},
+			{Nodes: [2]Node{nodeD, nodeE}, Weight: 6},
+			{Nodes: [2]Node{nodeA, nodeD}, Weight: 5},
+			{Nodes: [2]Node{nodeC, nodeE}, Weight: 2},
+			{Nodes: [2]Node{nodeE, nodeB}, Weight: 3},
+			{Nodes: [2]Node{nodeA, nodeE}, Weight: 7},
+			{Nodes: [2]Node{nodeG, nodeF}, Weight: 1},
+		},
+	}
+
+	components := graph.Components()
+
+	// Case 1: strongly connected components
+	if len(components.Strong) != 4 {
+		t.Errorf("StronglyConnectedComponents did not work. Got %v instead of %v", len(components.Strong), 4)
+	}
+
+	for _, component := range components.Strong {
+		if component[0] == nodeB && len(component) != 4 {
+			t.Errorf("StronglyConnectedComponents did not work. Got %v instead of %v", len(component), 4)
+		}
+	}
+
+	// Case 2: weakly connected components
+	if len(components.Weak) != 2 {
+		t.Errorf("WeaklyConnectedComponents did not work. Got %v instead of %v", len(components.Weak), 2)
+	}
+
+	// Case 3: condensation
+	if len(components.Condensation.Nodes) != 4 {
+		t.Errorf("Condensation did not work. Got %v instead of %v", len(components.Condensation.Nodes), 4)
+	}
+
+	if len(components.Condensation.Edges) != 2 {
+		t.Errorf("Condensation did not work. Got %v instead of %v", len(components.Condensation.Edges), 2)
+	}
+
+	for _, e := range components.Condensation.Edges {
+		if members, _ := e.Nodes[0].Attribute("members"); members == "A" && e.Weight != 5 {
+			t.Errorf("Condensation did not work. Got %v instead of %v", e.Weight, 5)
+		}
+	}
+
+	// Case 4: mutually reachable nodes share components on random graphs
+	r := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 50; i++ {
+		graph := randomGraph(r, 10, 0.15, 1, 10)
+
+		var (
+			idx               = graph.buildIndex()
+			strong, count     = idx.strongComponents()
+			reachable         = make([][]bool, len(idx.nodes))
+			condensation      = graph.Condensation()
+			condensationOrder = true
+		)
+
+		for s := range idx.nodes {
+			reachable[s] = make([]bool, len(idx.nodes))
+			reachable[s][s] = true
+			queue := []int{s}
+
+			for j := 0; j < len(queue); j++ {
+				for _, e := range idx.out(queue[j]) {
+					if !reachable[s][idx.to[e]] {
+						reachable[s][idx.to[e]] = true
+						queue = append(queue, idx.to[e])
+					}
+				}
+			}
+		}
+
+		for u := range idx.nodes {
+			for v := range idx.nodes {
+				if (strong[u] == strong[v]) != (reachable[u][v] && reachable[v][u]) {
+					t.Errorf("StronglyConnectedComponents did not work for %v and %v", idx.nodes[u], idx.nodes[v])
+				}
+			}
+		}
+
+		for _, e := range condensation.Edges {
+			from, _ := strconv.Atoi(e.Nodes[0].Name)
+			to, _ := strconv.Atoi(e.Nodes[1].Name)
+
+			if from >= to {
+				condensationOrder = false
+			}
+		}
+
+		if len(condensation.Nodes) != count || !condensation.IsAcyclic() || !condensationOrder {
+			t.Errorf("Condensation did not work. Got %v", condensation)
+		}
+
+		// Case 5: the components of a single index are those of the separate functions
+		want := Components{
+			Strong:       graph.StronglyConnectedComponents(),
+			Weak:         graph.WeaklyConnectedComponents(),
+			Condensation: condensation,
+		}
+
+		if got := graph.Components(); !reflect.DeepEqual(got, want) {
+			t.Errorf("Components did not work. Got %v instead of %v", got, want)
+		}
+	}
+}
@@ -0,0 +1,229 @@
+package core
+
+import "context"
+
+// FindCycle looks for a cycle in g using depth-first search.
+// It returns one cycle as a witness, or ok is false if g is acyclic.
+// Cycle paths start and end with the same node.
+func (g *Graph) FindCycle() (cycle Path, ok bool) {
+	const (
+		unvisited = iota
+		onPath
+		finished
+	)
+
+	var (
+		idx     = g.buildIndex()
+		states  = make([]int, len(idx.nodes))
+		parents = make([]int, len(idx.nodes)) // edge leading to the node on the current path
+		nexts   = make([]int, len(idx.nodes)) // next outgoing edge to visit
+	)
+
+	for start := range idx.nodes {
+		if states[start] != unvisited {
+			continue
+		}
+
+		states[start] = onPath
+		path := []int{start}
+
+		for len(path) > 0 {
+			v := path[len(path)-1]
+
+			if nexts[v] == len(idx.out(v)) {
+				states[v] = finished
+				path = path[:len(path)-1]
+
+				continue
+			}
+
+			e := idx.out(v)[nexts[v]]
+			nexts[v]++
+
+			switch w := idx.to[e]; states[w] {
+			case unvisited:
+				states[w] = onPath
+				parents[w] = e
+				path = append(path, w)
+			case onPath:
+				// Walking back along the current path to close the cycle
+				sequence := []int{e}
+
+				for u := v; u != w; u = idx.from[parents[u]] {
+					sequence = append(sequence, parents[u])
+				}
+
+				for i, j := 0, len(sequence)-1; i < j; i, j = i+1, j-1 {
+					sequence[i], sequence[j] = sequence[j], sequence[i]
+				}
+
+				return g.pathFromEdges(sequence), true
+			}
+		}
+	}
+
+	return cycle, false
+}
+
+// GenerateCycles finds every elementary cycle of g using Johnson's algorithm.
+// Elementary cycles do not repeat nodes apart from their first and last one.
+// maxLength: max. number of edges in a cycle, -1 if unlimited.
+// maxWeight: max. sum weight of a cycle, -1 if unlimited.
+// If a limit is set, cycles are searched for without Johnson's blocking,
+// which would skip cycles hidden behind pruned branches.
+// Cycle paths start and end with the same node, which is the first of them in g.
+// If ctx is done or its budget is exhausted, it returns the cycles found until then with a *TruncatedError.
+// Every call of the circuit search is an explored state.
+func (g *Graph) GenerateCycles(ctx context.Context, maxLength int, maxWeight float64) ([]Path, error) {
+	var (
+		idx       = g.buildIndex()
+		search    = newSearch(ctx)
+		negative  = idx.hasNegativeWeight()
+		limited   = maxLength != -1 || maxWeight != -1
+		sequences [][]int
+	)
+
+	for s := 0; s < len(idx.nodes) && !search.stopped(); s++ {
+		var (
+			component = idx.startComponent(s)
+			blocked   = make(map[int]bool)
+			blockers  = make(map[int]map[int]bool) // nodes to unblock when the key node is unblocked
+			stack     []int
+			weight    float64
+			unblock   func(v int)
+			circuit   func(v int) bool
+		)
+
+		if component == nil {
+			continue
+		}
+
+		unblock = func(v int) {
+			blocked[v] = false
+
+			for w := range blockers[v] {
+				delete(blockers[v], w)
+
+				if blocked[w] {
+					unblock(w)
+				}
+			}
+		}
+
+		circuit = func(v int) bool {
+			if !search.explore() {
+				return false
+			}
+
+			found := false
+			blocked[v] = true
+
+			for _, e := range idx.out(v) {
+				w := idx.to[e]
+
+				if search.stopped() {
+					break
+				}
+
+				if !component[w] {
+					continue
+				}
+
+				// Pruning branches over the limits, weights can only grow if none is negative
+				if maxLength != -1 && len(stack)+1 > maxLength {
+					continue
+				}
+
+				if maxWeight != -1 && !negative && weight+idx.weights[e] > maxWeight {
+					continue
+				}
+
+				stack = append(stack, e)
+				weight += idx.weights[e]
+
+				if w == s {
+					// Cycles beyond the budget of paths are dropped, found stops the search
+					if (maxWeight == -1 || weight <= maxWeight) && search.found() {
+						sequences = append(sequences, append([]int{}, stack...))
+					}
+
+					found = true
+				} else if !blocked[w] && circuit(w) {
+					found = true
+				}
+
+				stack = stack[:len(stack)-1]
+				weight -= idx.weights[e]
+			}
+
+			if found || limited {
+				unblock(v)
+			} else {
+				for _, e := range idx.out(v) {
+					if w := idx.to[e]; component[w] {
+						if blockers[w] == nil {
+							blockers[w] = make(map[int]bool)
+						}
+
+						blockers[w][v] = true
+					}
+				}
+			}
+
+			return found
+		}
+
+		circuit(s)
+	}
+
+	return g.pathsFromSequences(sequences), search.result()
+}
+
+// startComponent returns the strongly connected component of s within the subgraph of idx
+// induced by the nodes with id not lower than s, or nil if s has no cycle in it.
+func (idx *index) startComponent(s int) map[int]bool {
+	// reach collects the nodes not lower than s reachable from s along edges
+	reach := func(edges func(v int) []int, next func(e int) int) map[int]bool {
+		var (
+			reached = map[int]bool{s: true}
+			queue   = []int{s}
+		)
+
+		for i := 0; i < len(queue); i++ {
+			for _, e := range edges(queue[i]) {
+				if n := next(e); n >= s && !reached[n] {
+					reached[n] = true
+					queue = append(queue, n)
+				}
+			}
+		}
+
+		return reached
+	}
+
+	var (
+		forward   = reach(idx.out, func(e int) int { return idx.to[e] })
+		backward  = reach(idx.in, func(e int) int { return idx.from[e] })
+		component = make(map[int]bool)
+		cyclic    bool
+	)
+
+	for n := range forward {
+		if backward[n] {
+			component[n] = true
+		}
+	}
+
+	// A single node is a component with a cycle only if it has a self-loop
+	cyclic = len(component) > 1
+
+	for _, e := range idx.out(s) {
+		cyclic = cyclic || idx.to[e] == s
+	}
+
+	if !cyclic {
+		return nil
+	}
+
+	return component
+}
@@ -0,0 +1,165 @@
+package core
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+)
+
+// bruteForceCycles counts the elementary cycles of g within the limits,
+// walking every path from each node over higher nodes only.
+func bruteForceCycles(g *Graph, maxLength int, maxWeight float64) int {
+	var (
+		idx    = g.buildIndex()
+		count  int
+		onPath = make([]bool, len(idx.nodes))
+		walk   func(s, v, length int, weight float64)
+	)
+
+	walk = func(s, v, length int, weight float64) {
+		for _, e := range idx.out(v) {
+			var (
+				w         = idx.to[e]
+				newLength = length + 1
+				newWeight = weight + g.Edges[e].Weight
+			)
+
+			if w < s || onPath[w] && w != s || maxLength != -1 && newLength > maxLength {
+				continue
+			}
+
+			if w == s {
+				if maxWeight == -1 || newWeight <= maxWeight {
+					count++
+				}
+
+				continue
+			}
+
+			onPath[w] = true
+			walk(s, w, newLength, newWeight)
+			onPath[w] = false
+		}
+	}
+
+	for s := range idx.nodes {
+		onPath[s] = true
+		walk(s, s, 0, 0)
+		onPath[s] = false
+	}
+
+	return count
+}
+
+func TestFindCycle(t *testing.T) {
+	t.Parallel()
+
+	nodeA := Node{Name: "A"}
+	nodeB := Node{Name: "B"}
+	nodeC := Node{Name: "C"}
+
+	graph := Graph{
+		Nodes: []Node{nodeA, nodeB, nodeC},
+		Edges: []Edge{
+			{Nodes: [2]Node{nodeA, nodeB}, Weight: 1},
+			{Nodes: [2]Node{nodeB, nodeC}, Weight: 2},
+		},
+	}
+
+	// Case 1: acyclic graph
+	if _, ok := graph.FindCycle(); ok {
+		t.Errorf("FindCycle did not work. Got true instead of false")
+	}
+
+	// Case 2: cyclic graph
+	graph.Edges = append(graph.Edges, Edge{Nodes: [2]Node{nodeC, nodeB}, Weight: 3})
+
+	cycle, ok := graph.FindCycle()
+
+	if !ok {
+		t.Errorf("FindCycle did not work. Got false instead of true")
+	} else {
+		nodes := []Node{nodeB, nodeC, nodeB}
+
+		if cycle.Weight != 5 {
+			t.Errorf("FindCycle did not work. Got %v instead of %v", cycle.Weight, 5)
+		}
+
+		if len(cycle.Subgraph.Nodes) != len(nodes) {
+			t.Errorf("FindCycle did not work. Got %v instead of %v", len(cycle.Subgraph.Nodes), len(nodes))
+		} else {
+			for i, n := range cycle.Subgraph.Nodes {
+				if n != nodes[i] {
+					t.Errorf("FindCycle did not work. Got %v instead of %v", n, nodes[i])
+				}
+			}
+		}
+	}
+}
+
+func TestGenerateCycles(t *testing.T) {
+	t.Parallel()
+
+	nodeA := Node{Name: "A"}
+	nodeB := Node{Name: "B"}
+	nodeC := Node{Name: "C"}
+	nodeD := Node{Name: "D"}
+	nodeE := Node{Name: "E"}
+
+	graph := Graph{
+		Nodes: []Node{nodeA, nodeB, nodeC, nodeD, nodeE},
+		Edges: []Edge{
+			{Nodes: [2]Node{nodeA, nodeB}, Weight: 5},
+			{Nodes: [2]Node{nodeB, nodeC}, Weight: 4},
+			{Nodes: [2]Node{nodeC, nodeD}, Weight: 8},
+			{Nodes: [2]Node{nodeD, nodeC}, Weight: 8},
+			{Nodes: [2]Node{nodeD, nodeE}, Weight: 6},
+			{Nodes: [2]Node{nodeA, nodeD}, Weight: 5},
+			{Nodes: [2]Node{nodeC, nodeE}, Weight: 2},
+			{Nodes: [2]Node{nodeE, nodeB}, Weight: 3},
+			{Nodes: [2]Node{nodeA, nodeE}, Weight: 7},
+		},
+	}
+
+	// Case 1: every cycle: B-C-E-B, B-C-D-E-B, C-D-C
+	cycles, _ := graph.GenerateCycles(context.Background(), -1, -1)
+
+	if len(cycles) != 3 {
+		t.Errorf("GenerateCycles did not work. Got %v instead of %v", len(cycles), 3)
+	}
+
+	for _, cycle := range cycles {
+		nodes := cycle.Subgraph.Nodes
+
+		if nodes[0] != nodes[len(nodes)-1] {
+			t.Errorf("GenerateCycles did not work. Got %v instead of %v", nodes[len(nodes)-1], nodes[0])
+		}
+	}
+
+	// Case 2: limits
+	if cycles, _ = graph.GenerateCycles(context.Background(), 3, -1); len(cycles) != 2 {
+		t.Errorf("GenerateCycles did not work. Got %v instead of %v", len(cycles), 2)
+	}
+
+	if cycles, _ = graph.GenerateCycles(context.Background(), -1, 10); len(cycles) != 1 {
+		t.Errorf("GenerateCycles did not work. Got %v instead of %v", len(cycles), 1)
+	}
+
+	// Case 3: same count as brute force on random graphs
+	r := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 30; i++ {
+		graph := randomGraph(r, 7, 0.35, -2, 10)
+
+		for _, limits := range []struct {
+			maxLength int
+			maxWeight float64
+		}{{-1, -1}, {3, -1}, {-1, 12}, {4, 15}} {
+			cycles, _ := graph.GenerateCycles(context.Background(), limits.maxLength, limits.maxWeight)
+
+			if expected := bruteForceCycles(&graph, limits.maxLength, limits.maxWeight); len(cycles) != expected {
+				t.Errorf("GenerateCycles did not work with limits %v. Got %v instead of %v", limits, len(cycles), expected)
+			}
+		}
+	}
+}
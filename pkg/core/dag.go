@@ -0,0 +1,109 @@
+package core
+
+import "math"
+
+// Strategy names the way a path query is answered.
+type Strategy string
+
+// Strategies used to answer path queries.
+const (
+	StrategyExhaustive  Strategy = "exhaustive"  // enumerating every path
+	StrategyDijkstra    Strategy = "dijkstra"    // Dijkstra's algorithm
+	StrategyTopological Strategy = "topological" // dynamic programming in topological order, for DAGs only
+)
+
+// topologicalOrder returns the node ids of idx in topological order using Kahn's algorithm.
+// ok is false if idx contains a cycle.
+func (idx *index) topologicalOrder() (order []int, ok bool) {
+	inDegrees := make([]int, len(idx.nodes))
+
+	for _, v := range idx.to {
+		inDegrees[v]++
+	}
+
+	for n, d := range inDegrees {
+		if d == 0 {
+			order = append(order, n)
+		}
+	}
+
+	// order doubles as the queue of nodes without remaining incoming edges
+	for i := 0; i < len(order); i++ {
+		for _, e := range idx.out(order[i]) {
+			if inDegrees[idx.to[e]]--; inDegrees[idx.to[e]] == 0 {
+				order = append(order, idx.to[e])
+			}
+		}
+	}
+
+	return order, len(order) == len(idx.nodes)
+}
+
+// IsAcyclic reports whether g is a directed acyclic graph (DAG).
+func (g *Graph) IsAcyclic() bool {
+	_, ok := g.buildIndex().topologicalOrder()
+
+	return ok
+}
+
+// highestDistances computes the highest distances from src to every node of an acyclic idx,
+// walking the nodes in topological order.
+// It returns the distances by node id (-Inf if unreachable) and,
+// by node id, every edge reaching the node at its highest distance.
+func (idx *index) highestDistances(order []int, src int, weight func(e int) float64) ([]float64, [][]int) {
+	var (
+		distances = make([]float64, len(idx.nodes))
+		preds     = make([][]int, len(idx.nodes))
+	)
+
+	for i := range distances {
+		distances[i] = math.Inf(-1)
+	}
+
+	distances[src] = 0
+
+	for _, u := range order {
+		if math.IsInf(distances[u], -1) {
+			continue
+		}
+
+		for _, e := range idx.out(u) {
+			v := idx.to[e]
+
+			if d := distances[u] + weight(e); d > distances[v] {
+				distances[v] = d
+				preds[v] = append(preds[v][:0], e)
+			} else if d == distances[v] {
+				preds[v] = append(preds[v], e)
+			}
+		}
+	}
+
+	return distances, preds
+}
+
+// generateHighestPathsDAG finds every highest weighted path from node1 to node2 in an acyclic g, indexed by idx.
+// weight returns the weight of an edge by its index.
+// ok is false if g contains a cycle.
+// The paths are enumerated as a search of s (see tiedEdgeSequences).
+func (g *Graph) generateHighestPathsDAG(s *searchState, idx *index, node1, node2 Node, weight func(e int) float64) (paths []Path, ok bool) {
+	order, ok := idx.topologicalOrder()
+	if !ok {
+		return nil, false
+	}
+
+	src, ok1 := idx.id(node1)
+	dst, ok2 := idx.id(node2)
+
+	// There is no path from a node to itself in a DAG
+	if !ok1 || !ok2 || src == dst {
+		return []Path{}, true
+	}
+
+	distances, preds := idx.highestDistances(order, src, weight)
+	if math.IsInf(distances[dst], -1) {
+		return []Path{}, true
+	}
+
+	return g.pathsFromSequences(idx.tiedEdgeSequences(s, preds, src, dst)), true
+}
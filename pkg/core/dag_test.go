@@ -0,0 +1,157 @@
+package core
+
+import (
+	"context"
+	"testing"
+)
+
+func TestIsAcyclic(t *testing.T) {
+	t.Parallel()
+
+	nodeA := Node{Name: "A"}
+	nodeB := Node{Name: "B"}
+	nodeC := Node{Name: "C"}
+
+	graph := Graph{
+		Nodes: []Node{nodeA, nodeB, nodeC},
+		Edges: []Edge{
+			{Nodes: [2]Node{nodeA, nodeB}, Weight: 1},
+			{Nodes: [2]Node{nodeB, nodeC}, Weight: 1},
+		},
+	}
+
+	if !graph.IsAcyclic() {
+		t.Errorf("IsAcyclic did not work. Got false instead of true")
+	}
+
+	graph.Edges = append(graph.Edges, Edge{Nodes: [2]Node{nodeC, nodeA}, Weight: 1})
+
+	if graph.IsAcyclic() {
+		t.Errorf("IsAcyclic did not work. Got true instead of false")
+	}
+}
+
+func TestGenerateHighestPathsDAG(t *testing.T) {
+	t.Parallel()
+
+	nodeA := Node{Name: "A"}
+	nodeB := Node{Name: "B"}
+	nodeC := Node{Name: "C"}
+	nodeD := Node{Name: "D"}
+	nodeE := Node{Name: "E"}
+
+	edgeAB := Edge{
+		Nodes:  [2]Node{nodeA, nodeB},
+		Weight: 5,
+	}
+
+	edgeAC := Edge{
+		Nodes:  [2]Node{nodeA, nodeC},
+		Weight: 2,
+	}
+
+	edgeBD := Edge{
+		Nodes:  [2]Node{nodeB, nodeD},
+		Weight: 1,
+	}
+
+	edgeCD := Edge{
+		Nodes:  [2]Node{nodeC, nodeD},
+		Weight: 3,
+	}
+
+	edgeCB := Edge{
+		Nodes:  [2]Node{nodeC, nodeB},
+		Weight: 4,
+	}
+
+	edgeDE := Edge{
+		Nodes:  [2]Node{nodeD, nodeE},
+		Weight: 2,
+	}
+
+	edgeAE := Edge{
+		Nodes:  [2]Node{nodeA, nodeE},
+		Weight: 7,
+	}
+
+	graph := Graph{
+		Nodes: []Node{nodeA, nodeB, nodeC, nodeD, nodeE},
+		Edges: []Edge{edgeAB, edgeAC, edgeBD, edgeCD, edgeCB, edgeDE, edgeAE},
+	}
+
+	// Case 1: highest weighted path
+	paths, strategy, _ := graph.GenerateLowestHighestWeightPathWithStrategy(context.Background(), nodeA, nodeE, false)
+
+	if strategy != StrategyTopological {
+		t.Errorf("GenerateLowestHighestWeightPathWithStrategy did not work. Got %v instead of %v", strategy, StrategyTopological)
+	}
+
+	if len(paths) != 1 {
+		t.Errorf("GenerateLowestHighestWeightPathWithStrategy did not work. Got %v instead of %v", len(paths), 1)
+	} else {
+		subgraph := paths[0].Subgraph
+
+		nodes := []Node{nodeA, nodeC, nodeB, nodeD, nodeE}
+		edges := []Edge{edgeAC, edgeCB, edgeBD, edgeDE}
+
+		if paths[0].Weight != 9 {
+			t.Errorf("GenerateLowestHighestWeightPathWithStrategy did not work. Got %v instead of %v", paths[0].Weight, 9)
+		}
+
+		if len(subgraph.Nodes) != len(nodes) {
+			t.Errorf("GenerateLowestHighestWeightPathWithStrategy did not work. Got %v instead of %v", len(subgraph.Nodes), len(nodes))
+		} else {
+			for i, n := range subgraph.Nodes {
+				if n != nodes[i] {
+					t.Errorf("GenerateLowestHighestWeightPathWithStrategy did not work. Got %v instead of %v", n, nodes[i])
+				}
+			}
+		}
+
+		if len(subgraph.Edges) != len(edges) {
+			t.Errorf("GenerateLowestHighestWeightPathWithStrategy did not work. Got %v instead of %v", len(subgraph.Edges), len(edges))
+		} else {
+			for i, tr := range subgraph.Edges {
+				if !tr.Equals(&edges[i]) {
+					t.Errorf("GenerateLowestHighestWeightPathWithStrategy did not work. Got %v instead of %v", tr, edges[i])
+				}
+			}
+		}
+	}
+
+	// Case 2: same results as exhaustive search
+	for _, n1 := range graph.Nodes {
+		for _, n2 := range graph.Nodes {
+			var (
+				highest, _, _     = graph.GenerateLowestHighestWeightPathWithStrategy(context.Background(), n1, n2, false)
+				longest, _, _     = graph.GenerateShortestLongestPathWithStrategy(context.Background(), n1, n2, false)
+				exhaustiveHigh, _ = graph.generateLowestHighestWeightPathExhaustive(context.Background(), graph.buildIndex(), n1, n2, false)
+				exhaustiveLong, _ = graph.generateShortestLongestPathExhaustive(context.Background(), graph.buildIndex(), n1, n2, false)
+			)
+
+			if len(highest) != len(exhaustiveHigh) {
+				t.Errorf("GenerateLowestHighestWeightPathWithStrategy did not work. Got %v instead of %v", len(highest), len(exhaustiveHigh))
+			} else if len(highest) > 0 && highest[0].Weight != exhaustiveHigh[0].Weight {
+				t.Errorf("GenerateLowestHighestWeightPathWithStrategy did not work. Got %v instead of %v", highest[0].Weight, exhaustiveHigh[0].Weight)
+			}
+
+			if len(longest) != len(exhaustiveLong) {
+				t.Errorf("GenerateShortestLongestPathWithStrategy did not work. Got %v instead of %v", len(longest), len(exhaustiveLong))
+			} else if len(longest) > 0 && len(longest[0].Subgraph.Edges) != len(exhaustiveLong[0].Subgraph.Edges) {
+				t.Errorf("GenerateShortestLongestPathWithStrategy did not work. Got %v instead of %v",
+					len(longest[0].Subgraph.Edges), len(exhaustiveLong[0].Subgraph.Edges))
+			}
+		}
+	}
+
+	// Case 3: cyclic graph falls back to exhaustive search
+	graph.Edges = append(graph.Edges, Edge{
+		Nodes:  [2]Node{nodeE, nodeA},
+		Weight: 1,
+	})
+
+	if _, strategy, _ = graph.GenerateShortestLongestPathWithStrategy(context.Background(), nodeA, nodeE, false); strategy != StrategyExhaustive {
+		t.Errorf("GenerateShortestLongestPathWithStrategy did not work. Got %v instead of %v", strategy, StrategyExhaustive)
+	}
+}
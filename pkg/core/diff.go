@@ -0,0 +1,123 @@
+package core
+
+// WeightChange represents an edge whose weight changed between two graphs.
+type WeightChange struct {
+	Nodes [2]Node `json:"nodes"`
+	From  float64 `json:"from"` // weight in the old graph
+	To    float64 `json:"to"`   // weight in the new graph
+}
+
+// GraphDiff represents the differences between two graphs.
+type GraphDiff struct {
+	AddedNodes    []Node         `json:"addedNodes"`
+	RemovedNodes  []Node         `json:"removedNodes"`
+	AddedEdges    []Edge         `json:"addedEdges"`
+	RemovedEdges  []Edge         `json:"removedEdges"`
+	WeightChanges []WeightChange `json:"weightChanges"`
+}
+
+// weightedEdgeKey identifies equal edges (see Edge.Equals).
+type weightedEdgeKey struct {
+	edgeKey
+	weight float64
+}
+
+// EqualsIgnoringWeight checks equality between e and edge2 regardless of their weights.
+// It returns true if they connect the same nodes in the same direction; otherwise false.
+func (e *Edge) EqualsIgnoringWeight(edge2 *Edge) bool {
+	return e.Nodes[0].Equals(edge2.Nodes[0]) && e.Nodes[1].Equals(edge2.Nodes[1])
+}
+
+// Diff returns the differences leading from oldGraph to newGraph.
+// considerWeight: true, if edges are equal only with equal weights,
+// and weight changes of edges between the same nodes are reported; otherwise false.
+// Edges are paired in order, the first unpaired equal edge of newGraph with each edge of oldGraph.
+func Diff(oldGraph, newGraph Graph, considerWeight bool) GraphDiff {
+	diff := GraphDiff{
+		AddedNodes:    []Node{},
+		RemovedNodes:  []Node{},
+		AddedEdges:    []Edge{},
+		RemovedEdges:  []Edge{},
+		WeightChanges: []WeightChange{},
+	}
+
+	var (
+		oldNodes = oldGraph.internNodes()
+		newNodes = newGraph.internNodes()
+	)
+
+	for _, n := range newGraph.Nodes {
+		if _, ok := oldNodes.id(n); !ok {
+			diff.AddedNodes = append(diff.AddedNodes, n)
+		}
+	}
+
+	for _, n := range oldGraph.Nodes {
+		if _, ok := newNodes.id(n); !ok {
+			diff.RemovedNodes = append(diff.RemovedNodes, n)
+		}
+	}
+
+	var (
+		oldMatched = make([]bool, len(oldGraph.Edges))
+		newMatched = make([]bool, len(newGraph.Edges))
+	)
+
+	// match pairs unmatched edges of the two graphs with the same key, in order
+	match := func(key func(e *Edge) weightedEdgeKey, pair func(e1, e2 *Edge)) {
+		unmatched := make(map[weightedEdgeKey][]int) // unmatched edges of newGraph by key
+
+		for j := range newGraph.Edges {
+			if !newMatched[j] {
+				k := key(&newGraph.Edges[j])
+				unmatched[k] = append(unmatched[k], j)
+			}
+		}
+
+		for i := range oldGraph.Edges {
+			k := key(&oldGraph.Edges[i])
+
+			if oldMatched[i] || len(unmatched[k]) == 0 {
+				continue
+			}
+
+			j := unmatched[k][0]
+			unmatched[k] = unmatched[k][1:]
+
+			oldMatched[i], newMatched[j] = true, true
+			pair(&oldGraph.Edges[i], &newGraph.Edges[j])
+		}
+	}
+
+	if considerWeight {
+		match(func(e *Edge) weightedEdgeKey {
+			return weightedEdgeKey{edgeKey: edgeKey{from: e.Nodes[0].Name, to: e.Nodes[1].Name}, weight: e.Weight}
+		}, func(e1, e2 *Edge) {})
+	}
+
+	match(func(e *Edge) weightedEdgeKey {
+		return weightedEdgeKey{edgeKey: edgeKey{from: e.Nodes[0].Name, to: e.Nodes[1].Name}}
+	}, func(e1, e2 *Edge) {
+		if considerWeight {
+			diff.WeightChanges = append(diff.WeightChanges, WeightChange{
+				Nodes: e2.Nodes,
+				From:  e1.Weight,
+				To:    e2.Weight,
+			})
+		}
+	})
+
+	for i, e := range oldGraph.Edges {
+		if !oldMatched[i] {
+			diff.RemovedEdges = append(diff.RemovedEdges, e)
+		}
+	}
+
+	for j, e := range newGraph.Edges {
+		if !newMatched[j] {
+			diff.AddedEdges = append(diff.AddedEdges, e)
+		}
+	}
+
+	return diff
+}
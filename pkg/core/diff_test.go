@@ -0,0 +1,83 @@
+package core
+
+import (
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+
+	nodeA := Node{Name: "A"}
+	nodeB := Node{Name: "B"}
+	nodeC := Node{Name: "C"}
+	nodeD := Node{Name: "D"}
+
+	edgeAB := Edge{
+		Nodes:  [2]Node{nodeA, nodeB},
+		Weight: 1,
+	}
+
+	edgeBC := Edge{
+		Nodes:  [2]Node{nodeB, nodeC},
+		Weight: 2,
+	}
+
+	edgeBC2 := Edge{
+		Nodes:  [2]Node{nodeB, nodeC},
+		Weight: 5,
+	}
+
+	edgeCD := Edge{
+		Nodes:  [2]Node{nodeC, nodeD},
+		Weight: 3,
+	}
+
+	oldGraph := Graph{
+		Nodes: []Node{nodeA, nodeB, nodeC},
+		Edges: []Edge{edgeAB, edgeBC},
+	}
+
+	newGraph := Graph{
+		Nodes: []Node{nodeB, nodeC, nodeD},
+		Edges: []Edge{edgeBC2, edgeCD},
+	}
+
+	// Case 1: weight considered
+	diff := Diff(oldGraph, newGraph, true)
+
+	if len(diff.AddedNodes) != 1 || diff.AddedNodes[0] != nodeD {
+		t.Errorf("Diff did not work. Got %v instead of %v", diff.AddedNodes, []Node{nodeD})
+	}
+
+	if len(diff.RemovedNodes) != 1 || diff.RemovedNodes[0] != nodeA {
+		t.Errorf("Diff did not work. Got %v instead of %v", diff.RemovedNodes, []Node{nodeA})
+	}
+
+	if len(diff.AddedEdges) != 1 || !diff.AddedEdges[0].Equals(&edgeCD) {
+		t.Errorf("Diff did not work. Got %v instead of %v", diff.AddedEdges, []Edge{edgeCD})
+	}
+
+	if len(diff.RemovedEdges) != 1 || !diff.RemovedEdges[0].Equals(&edgeAB) {
+		t.Errorf("Diff did not work. Got %v instead of %v", diff.RemovedEdges, []Edge{edgeAB})
+	}
+
+	if len(diff.WeightChanges) != 1 {
+		t.Errorf("Diff did not work. Got %v instead of %v", len(diff.WeightChanges), 1)
+	} else if change := diff.WeightChanges[0]; change.From != 2 || change.To != 5 {
+		t.Errorf("Diff did not work. Got %v", change)
+	}
+
+	// Case 2: weight ignored
+	diff = Diff(oldGraph, newGraph, false)
+
+	if len(diff.WeightChanges) != 0 || len(diff.AddedEdges) != 1 || len(diff.RemovedEdges) != 1 {
+		t.Errorf("Diff did not work. Got %v", diff)
+	}
+
+	// Case 3: no difference
+	diff = Diff(oldGraph, oldGraph.Copy(), true)
+
+	if len(diff.AddedNodes)+len(diff.RemovedNodes)+len(diff.AddedEdges)+len(diff.RemovedEdges)+len(diff.WeightChanges) != 0 {
+		t.Errorf("Diff did not work. Got %v", diff)
+	}
+}
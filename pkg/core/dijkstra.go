@@ -0,0 +1,216 @@
+package core
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"math"
+)
+
+// ErrNegativeWeight is the reason of an *InvalidWeightError returned by searches that require non-negative edge weights.
+var ErrNegativeWeight = errors.New("negative edge weights are not allowed")
+
+// distanceItem is an entry of the priority queue used by Dijkstra's algorithm.
+type distanceItem struct {
+	node     int
+	distance float64
+}
+
+// distanceHeap is a binary min-heap of distanceItems ordered by distance.
+type distanceHeap []distanceItem
+
+func (h distanceHeap) Len() int            { return len(h) }
+func (h distanceHeap) Less(i, j int) bool  { return h[i].distance < h[j].distance }
+func (h distanceHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *distanceHeap) Push(x interface{}) { *h = append(*h, x.(distanceItem)) }
+
+func (h *distanceHeap) Pop() interface{} {
+	old := *h
+	item := old[len(old)-1]
+	*h = old[:len(old)-1]
+
+	return item
+}
+
+// dijkstra computes the lowest distances from src to every node of idx.
+// weight returns the weight of an edge by its index; edges weighing +Inf are skipped.
+// Weights must not be negative.
+// It returns the distances by node id (+Inf if unreachable) and,
+// by node id, every edge reaching the node at its lowest distance.
+// Every settled node is a step of s; the distances are incomplete if s stops.
+func (idx *index) dijkstra(s *searchState, src int, weight func(e int) float64) ([]float64, [][]int) {
+	var (
+		distances = make([]float64, len(idx.nodes))
+		preds     = make([][]int, len(idx.nodes))
+		settled   = make([]bool, len(idx.nodes))
+		queue     = &distanceHeap{{node: src}}
+	)
+
+	for i := range distances {
+		distances[i] = math.Inf(1)
+	}
+
+	distances[src] = 0
+
+	for queue.Len() > 0 {
+		item := heap.Pop(queue).(distanceItem)
+
+		// Skipping outdated queue entries
+		if settled[item.node] {
+			continue
+		}
+
+		if !s.step() {
+			break
+		}
+
+		settled[item.node] = true
+
+		for _, e := range idx.out(item.node) {
+			w := weight(e)
+			next := idx.to[e]
+
+			if math.IsInf(w, 1) {
+				continue
+			}
+
+			// Settled nodes can still be reached at their distance through zero weighted edges
+			if d := item.distance + w; d < distances[next] && !settled[next] {
+				distances[next] = d
+				preds[next] = append(preds[next][:0], e)
+
+				heap.Push(queue, distanceItem{node: next, distance: d})
+			} else if d == distances[next] {
+				preds[next] = append(preds[next], e)
+			}
+		}
+	}
+
+	return distances, preds
+}
+
+// GenerateLowestWeightPaths finds every lowest weighted path from node1 to node2
+// using Dijkstra's algorithm, so it does not enumerate every path of g.
+// If node1 equals node2, the lowest weighted cycles through node1 are searched for.
+// Edge weights must not be negative.
+// It returns all the tied lowest weighted paths.
+// If ctx is done or its budget is exhausted, it returns the paths enumerated until then with a *TruncatedError.
+func (g *Graph) GenerateLowestWeightPaths(ctx context.Context, node1, node2 Node) ([]Path, error) {
+	return g.lowestWeightPaths(ctx, g.buildIndex(), node1, node2)
+}
+
+// lowestWeightPaths is GenerateLowestWeightPaths on the index of g.
+func (g *Graph) lowestWeightPaths(ctx context.Context, idx *index, node1, node2 Node) ([]Path, error) {
+	search := newSearch(ctx)
+
+	src, ok1 := idx.id(node1)
+	dst, ok2 := idx.id(node2)
+
+	if !ok1 || !ok2 {
+		return []Path{}, nil
+	}
+
+	distances, preds := idx.dijkstra(search, src, idx.weight)
+	if search.stopped() {
+		return []Path{}, search.result()
+	}
+
+	paths := g.pathsFromSequences(idx.lowestWeightSequences(search, distances, preds, src, dst, idx.weight))
+
+	return paths, search.result()
+}
+
+// lowestWeightSequences enumerates the edge sequences of the lowest weighted paths from src to dst
+// given the result of a single-source lowest distance search.
+// If src equals dst, the sequences of the lowest weighted cycles through src are enumerated.
+// The enumeration is a search of s (see tiedEdgeSequences).
+func (idx *index) lowestWeightSequences(s *searchState, distances []float64, preds [][]int, src, dst int,
+	weight func(e int) float64) [][]int {
+	if src != dst {
+		if math.IsInf(distances[dst], 1) {
+			return nil
+		}
+
+		return idx.tiedEdgeSequences(s, preds, src, dst)
+	}
+
+	// Closing the lowest weighted paths leading back to src
+	var (
+		lowest    = math.Inf(1)
+		closing   []int
+		sequences [][]int
+	)
+
+	for _, e := range idx.in(src) {
+		if math.IsInf(distances[idx.from[e]], 1) {
+			continue
+		}
+
+		if d := distances[idx.from[e]] + weight(e); d < lowest {
+			lowest = d
+			closing = append(closing[:0], e)
+		} else if d == lowest {
+			closing = append(closing, e)
+		}
+	}
+
+	for _, e := range closing {
+		for _, sequence := range idx.tiedEdgeSequences(s, preds, src, idx.from[e]) {
+			sequences = append(sequences, append(sequence, e))
+		}
+	}
+
+	return sequences
+}
+
+// lowestWeightSequence returns the edge sequence of one lowest weighted path from src to dst
+// given the result of Dijkstra's algorithm, or nil if there is none.
+// If src equals dst, the sequence of one lowest weighted cycle through src is returned.
+func (idx *index) lowestWeightSequence(distances []float64, preds [][]int, src, dst int,
+	weight func(e int) float64) []int {
+	var (
+		sequence []int
+		node     = dst
+	)
+
+	if src == dst {
+		// Choosing the closing edge of the lowest weighted cycle
+		var (
+			lowest  = math.Inf(1)
+			closing = -1
+		)
+
+		for _, e := range idx.in(src) {
+			if math.IsInf(distances[idx.from[e]], 1) {
+				continue
+			}
+
+			if d := distances[idx.from[e]] + weight(e); d < lowest {
+				lowest = d
+				closing = e
+			}
+		}
+
+		if closing == -1 {
+			return nil
+		}
+
+		sequence = append(sequence, closing)
+		node = idx.from[closing]
+	} else if math.IsInf(distances[dst], 1) {
+		return nil
+	}
+
+	// Walking backwards along the first predecessors
+	for node != src {
+		e := preds[node][0]
+		sequence = append(sequence, e)
+		node = idx.from[e]
+	}
+
+	for i, j := 0, len(sequence)-1; i < j; i, j = i+1, j-1 {
+		sequence[i], sequence[j] = sequence[j], sequence[i]
+	}
+
+	return sequence
+}
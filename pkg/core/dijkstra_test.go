@@ -0,0 +1,218 @@
+package core
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+)
+
+func TestGenerateLowestWeightPaths(t *testing.T) {
+	t.Parallel()
+
+	nodeA := Node{Name: "A"}
+	nodeB := Node{Name: "B"}
+	nodeC := Node{Name: "C"}
+	nodeD := Node{Name: "D"}
+	nodeE := Node{Name: "E"}
+
+	edgeAB := Edge{
+		Nodes:  [2]Node{nodeA, nodeB},
+		Weight: 5,
+	}
+
+	edgeBC := Edge{
+		Nodes:  [2]Node{nodeB, nodeC},
+		Weight: 4,
+	}
+
+	edgeCD := Edge{
+		Nodes:  [2]Node{nodeC, nodeD},
+		Weight: 8,
+	}
+
+	edgeDC := Edge{
+		Nodes:  [2]Node{nodeD, nodeC},
+		Weight: 8,
+	}
+
+	edgeDE := Edge{
+		Nodes:  [2]Node{nodeD, nodeE},
+		Weight: 6,
+	}
+
+	edgeAD := Edge{
+		Nodes:  [2]Node{nodeA, nodeD},
+		Weight: 5,
+	}
+
+	edgeCE := Edge{
+		Nodes:  [2]Node{nodeC, nodeE},
+		Weight: 2,
+	}
+
+	edgeEB := Edge{
+		Nodes:  [2]Node{nodeE, nodeB},
+		Weight: 3,
+	}
+
+	edgeAE := Edge{
+		Nodes:  [2]Node{nodeA, nodeE},
+		Weight: 7,
+	}
+
+	graph := Graph{
+		Nodes: []Node{nodeA, nodeB, nodeC, nodeD, nodeE},
+		Edges: []Edge{edgeAB, edgeBC, edgeCD, edgeDC, edgeDE, edgeAD, edgeCE, edgeEB, edgeAE},
+	}
+
+	// Case 1: single lowest weighted path
+	paths, _ := graph.GenerateLowestWeightPaths(context.Background(), nodeA, nodeC)
+
+	if len(paths) != 1 {
+		t.Errorf("GenerateLowestWeightPaths did not work. Got %v instead of %v", len(paths), 1)
+	} else {
+		subgraph := paths[0].Subgraph
+
+		nodes := []Node{nodeA, nodeB, nodeC}
+		edges := []Edge{edgeAB, edgeBC}
+
+		if paths[0].Weight != 9 {
+			t.Errorf("GenerateLowestWeightPaths did not work. Got %v instead of %v", paths[0].Weight, 9)
+		}
+
+		if len(subgraph.Nodes) != len(nodes) {
+			t.Errorf("GenerateLowestWeightPaths did not work. Got %v instead of %v", len(subgraph.Nodes), len(nodes))
+		} else {
+			for i, n := range subgraph.Nodes {
+				if n != nodes[i] {
+					t.Errorf("GenerateLowestWeightPaths did not work. Got %v instead of %v", n, nodes[i])
+				}
+			}
+		}
+
+		if len(subgraph.Edges) != len(edges) {
+			t.Errorf("GenerateLowestWeightPaths did not work. Got %v instead of %v", len(subgraph.Edges), len(edges))
+		} else {
+			for i, tr := range subgraph.Edges {
+				if !tr.Equals(&edges[i]) {
+					t.Errorf("GenerateLowestWeightPaths did not work. Got %v instead of %v", tr, edges[i])
+				}
+			}
+		}
+	}
+
+	// Case 2: no path found
+	paths, _ = graph.GenerateLowestWeightPaths(context.Background(), nodeC, nodeA)
+
+	if len(paths) != 0 {
+		t.Errorf("GenerateLowestWeightPaths did not work. Got %v instead of %v", len(paths), 0)
+	}
+
+	// Case 3: lowest weighted cycle
+	paths, _ = graph.GenerateLowestWeightPaths(context.Background(), nodeB, nodeB)
+
+	if len(paths) != 1 {
+		t.Errorf("GenerateLowestWeightPaths did not work. Got %v instead of %v", len(paths), 1)
+	} else if paths[0].Weight != 9 {
+		t.Errorf("GenerateLowestWeightPaths did not work. Got %v instead of %v", paths[0].Weight, 9)
+	}
+
+	// Case 4: tied lowest weighted paths
+	edgeEC := Edge{
+		Nodes:  [2]Node{nodeE, nodeC},
+		Weight: 2,
+	}
+
+	graph.Edges = append(graph.Edges, edgeEC)
+
+	paths, _ = graph.GenerateLowestWeightPaths(context.Background(), nodeD, nodeC)
+
+	if len(paths) != 2 {
+		t.Errorf("GenerateLowestWeightPaths did not work. Got %v instead of %v", len(paths), 2)
+	} else {
+		for _, p := range paths {
+			if p.Weight != 8 {
+				t.Errorf("GenerateLowestWeightPaths did not work. Got %v instead of %v", p.Weight, 8)
+			}
+		}
+	}
+
+	// Case 5: same result as exhaustive search
+	for _, n1 := range graph.Nodes {
+		for _, n2 := range graph.Nodes {
+			var (
+				dijkstraPaths, _   = graph.GenerateLowestWeightPaths(context.Background(), n1, n2)
+				exhaustivePaths, _ = graph.GeneratePathsWithoutEdgeRepetition(context.Background(), n1, n2)
+			)
+
+			if len(exhaustivePaths) == 0 {
+				if len(dijkstraPaths) != 0 {
+					t.Errorf("GenerateLowestWeightPaths did not work. Got %v instead of %v", len(dijkstraPaths), 0)
+				}
+
+				continue
+			}
+
+			weight := exhaustivePaths[0].Weight
+
+			for _, p := range exhaustivePaths {
+				if p.Weight < weight {
+					weight = p.Weight
+				}
+			}
+
+			if len(dijkstraPaths) == 0 {
+				t.Errorf("GenerateLowestWeightPaths did not work. Got no path from %v to %v", n1, n2)
+			} else if dijkstraPaths[0].Weight != weight {
+				t.Errorf("GenerateLowestWeightPaths did not work. Got %v instead of %v", dijkstraPaths[0].Weight, weight)
+			}
+		}
+	}
+}
+
+func TestGenerateLowestWeightPathsZeroWeights(t *testing.T) {
+	t.Parallel()
+
+	nodeS := Node{Name: "S"}
+	nodeX := Node{Name: "X"}
+	nodeY := Node{Name: "Y"}
+
+	graph := Graph{
+		Nodes: []Node{nodeS, nodeX, nodeY},
+		Edges: []Edge{
+			{Nodes: [2]Node{nodeS, nodeX}, Weight: 1},
+			{Nodes: [2]Node{nodeS, nodeY}, Weight: 1},
+			{Nodes: [2]Node{nodeY, nodeX}, Weight: 0},
+		},
+	}
+
+	// Case 1: tie through a zero weighted edge to an already settled node
+	if paths, _ := graph.GenerateLowestWeightPaths(context.Background(), nodeS, nodeX); len(paths) != 2 {
+		t.Errorf("GenerateLowestWeightPaths did not work. Got %v instead of %v", len(paths), 2)
+	}
+
+	// Case 2: as many tied paths as exhaustive search
+	r := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 20; i++ {
+		graph = randomGraph(r, 7, 0.4, 0, 4)
+
+		for _, n1 := range graph.Nodes {
+			for _, n2 := range graph.Nodes {
+				if n1.Equals(n2) {
+					continue
+				}
+
+				var (
+					dijkstraPaths, _   = graph.GenerateLowestWeightPaths(context.Background(), n1, n2)
+					exhaustivePaths, _ = graph.generateLowestHighestWeightPathExhaustive(context.Background(), graph.buildIndex(), n1, n2, true)
+				)
+
+				if len(dijkstraPaths) != len(exhaustivePaths) {
+					t.Errorf("GenerateLowestWeightPaths did not work from %v to %v. Got %v instead of %v",
+						n1, n2, len(dijkstraPaths), len(exhaustivePaths))
+				}
+			}
+		}
+	}
+}
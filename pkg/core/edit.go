@@ -0,0 +1,96 @@
+package core
+
+import "errors"
+
+// Errors of graph editing.
+var (
+	ErrNodeExists  = errors.New("node already exists")
+	ErrNodeMissing = errors.New("node does not exist")
+	ErrEdgeExists  = errors.New("edge already exists")
+	ErrEdgeMissing = errors.New("edge does not exist")
+	ErrSelfLoop    = errors.New("edge nodes cannot be identical")
+)
+
+// AddNode adds n to g.
+// It returns ErrNodeExists if g already has a node with the same name.
+func (g *Graph) AddNode(n Node) error {
+	if g.HasNode(n) {
+		return ErrNodeExists
+	}
+
+	g.Nodes = append(g.Nodes, n)
+
+	return nil
+}
+
+// RemoveNode removes n and every edge of n from g.
+// It returns an *UnknownNodeError, wrapping ErrNodeMissing, if n is not a node of g.
+func (g *Graph) RemoveNode(n Node) error {
+	if err := g.checkNodes(n); err != nil {
+		return err
+	}
+
+	nodes := g.Nodes[:0]
+
+	for _, node := range g.Nodes {
+		if !node.Equals(n) {
+			nodes = append(nodes, node)
+		}
+	}
+
+	g.Nodes = nodes
+
+	edges := g.Edges[:0]
+
+	for _, e := range g.Edges {
+		if !e.Nodes[0].Equals(n) && !e.Nodes[1].Equals(n) {
+			edges = append(edges, e)
+		}
+	}
+
+	g.Edges = edges
+
+	return nil
+}
+
+// AddEdge adds e to g. Both nodes of e have to be nodes of g already.
+// It returns ErrSelfLoop, an *UnknownNodeError wrapping ErrNodeMissing, an *InvalidWeightError for NaN and infinite weights,
+// or ErrEdgeExists if g already has an edge between the same nodes, whatever its weight
+// (see RuleDuplicateEdge).
+func (g *Graph) AddEdge(e Edge) error {
+	if e.Nodes[0].Equals(e.Nodes[1]) {
+		return ErrSelfLoop
+	}
+
+	if err := g.checkNodes(e.Nodes[0], e.Nodes[1]); err != nil {
+		return err
+	}
+
+	if err := checkFiniteWeight(e); err != nil {
+		return err
+	}
+
+	for i := range g.Edges {
+		if g.Edges[i].EqualsIgnoringWeight(&e) {
+			return ErrEdgeExists
+		}
+	}
+
+	g.Edges = append(g.Edges, e)
+
+	return nil
+}
+
+// RemoveEdge removes e from g.
+// It returns ErrEdgeMissing if g has no edge equal to e.
+func (g *Graph) RemoveEdge(e Edge) error {
+	for i := range g.Edges {
+		if g.Edges[i].Equals(&e) {
+			g.Edges = append(g.Edges[:i], g.Edges[i+1:]...)
+
+			return nil
+		}
+	}
+
+	return ErrEdgeMissing
+}
@@ -0,0 +1,78 @@
+package core
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestEdit(t *testing.T) {
+	t.Parallel()
+
+	nodeA := Node{Name: "A"}
+	nodeB := Node{Name: "B"}
+	nodeC := Node{Name: "C"}
+
+	edgeAB := Edge{
+		Nodes:  [2]Node{nodeA, nodeB},
+		Weight: 1,
+	}
+
+	edgeBC := Edge{
+		Nodes:  [2]Node{nodeB, nodeC},
+		Weight: 2,
+	}
+
+	var graph Graph
+
+	for _, n := range []Node{nodeA, nodeB, nodeC} {
+		if err := graph.AddNode(n); err != nil {
+			t.Errorf("AddNode did not work. Got error %v", err)
+		}
+	}
+
+	if err := graph.AddNode(nodeA); !errors.Is(err, ErrNodeExists) {
+		t.Errorf("AddNode did not work. Got error %v instead of %v", err, ErrNodeExists)
+	}
+
+	for _, e := range []Edge{edgeAB, edgeBC} {
+		if err := graph.AddEdge(e); err != nil {
+			t.Errorf("AddEdge did not work. Got error %v", err)
+		}
+	}
+
+	// Edges between the same nodes exist already whatever their weights, as for strict validation
+	for _, weight := range []float64{1, 2} {
+		if err := graph.AddEdge(Edge{Nodes: edgeAB.Nodes, Weight: weight}); !errors.Is(err, ErrEdgeExists) {
+			t.Errorf("AddEdge did not work. Got error %v instead of %v for weight %v", err, ErrEdgeExists, weight)
+		}
+	}
+
+	if err := graph.Validate(ValidationStrict); err != nil {
+		t.Errorf("AddEdge did not work. Got error %v", err)
+	}
+
+	if err := graph.AddEdge(Edge{Nodes: [2]Node{nodeA, nodeA}}); !errors.Is(err, ErrSelfLoop) {
+		t.Errorf("AddEdge did not work. Got error %v instead of %v", err, ErrSelfLoop)
+	}
+
+	if err := graph.AddEdge(Edge{Nodes: [2]Node{nodeA, {Name: "D"}}}); !errors.Is(err, ErrNodeMissing) {
+		t.Errorf("AddEdge did not work. Got error %v instead of %v", err, ErrNodeMissing)
+	}
+
+	if err := graph.RemoveEdge(edgeAB); err != nil || len(graph.Edges) != 1 {
+		t.Errorf("RemoveEdge did not work. Got error %v and %v edges", err, len(graph.Edges))
+	}
+
+	if err := graph.RemoveEdge(edgeAB); !errors.Is(err, ErrEdgeMissing) {
+		t.Errorf("RemoveEdge did not work. Got error %v instead of %v", err, ErrEdgeMissing)
+	}
+
+	// Removing a node removes its edges as well
+	if err := graph.RemoveNode(nodeC); err != nil || len(graph.Nodes) != 2 || len(graph.Edges) != 0 {
+		t.Errorf("RemoveNode did not work. Got error %v, %v nodes and %v edges", err, len(graph.Nodes), len(graph.Edges))
+	}
+
+	if err := graph.RemoveNode(nodeC); !errors.Is(err, ErrNodeMissing) {
+		t.Errorf("RemoveNode did not work. Got error %v instead of %v", err, ErrNodeMissing)
+	}
+}
@@ -0,0 +1,139 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrNonFiniteWeight is the reason of an *InvalidWeightError for NaN and infinite weights.
+var ErrNonFiniteWeight = errors.New("weights have to be finite numbers")
+
+// UnknownNodeError is returned when a node is not a node of the graph.
+// It wraps ErrNodeMissing.
+type UnknownNodeError struct {
+	Node  Node
+	Field string // name of the parameter holding the node, if any
+}
+
+// Error returns the description of the unknown node.
+func (e *UnknownNodeError) Error() string {
+	return fmt.Sprintf("unknown node %q", e.Node.Name)
+}
+
+// Unwrap returns ErrNodeMissing.
+func (e *UnknownNodeError) Unwrap() error {
+	return ErrNodeMissing
+}
+
+// InvalidWeightError is returned when the weight of an edge is not allowed,
+// such as a negative weight for algorithms requiring non-negative ones.
+type InvalidWeightError struct {
+	Edge Edge
+	Err  error // reason of the error, ErrNegativeWeight or ErrNonFiniteWeight
+}
+
+// Error returns the description of the invalid weight with its edge.
+func (e *InvalidWeightError) Error() string {
+	return fmt.Sprintf("invalid weight %v of edge from %q to %q: %v", e.Edge.Weight, e.Edge.Nodes[0].Name, e.Edge.Nodes[1].Name, e.Err)
+}
+
+// Unwrap returns the reason of the error.
+func (e *InvalidWeightError) Unwrap() error {
+	return e.Err
+}
+
+// GraphTooLargeError is returned when a graph has more nodes or edges than allowed.
+type GraphTooLargeError struct {
+	Nodes, Edges       int // size of the graph
+	MaxNodes, MaxEdges int // allowed size of the graph, MaxEdges is 0 if edges are unlimited
+}
+
+// Error returns the size of the graph with the allowed size.
+func (e *GraphTooLargeError) Error() string {
+	if e.MaxEdges == 0 {
+		return fmt.Sprintf("graph too large: %v nodes, at most %v nodes are allowed", e.Nodes, e.MaxNodes)
+	}
+
+	return fmt.Sprintf("graph too large: %v nodes and %v edges, at most %v nodes and %v edges are allowed",
+		e.Nodes, e.Edges, e.MaxNodes, e.MaxEdges)
+}
+
+// InvalidParameterError is returned when a parameter of an algorithm is out of its range.
+type InvalidParameterError struct {
+	Field   string // name of the parameter
+	Message string // description of the allowed values
+}
+
+// Error returns the name of the parameter with the description of the allowed values.
+func (e *InvalidParameterError) Error() string {
+	if len(e.Field) == 0 {
+		return e.Message
+	}
+
+	return e.Field + ": " + e.Message
+}
+
+// TruncatedError is returned together with partial results by searches stopped before their end,
+// because their context is done or their budget (see WithBudget) is exhausted.
+// Results found until then are returned, but there may be more of them, or better ones.
+type TruncatedError struct {
+	Err error // reason: the error of the context, ErrStateBudgetExhausted or ErrPathBudgetExhausted
+}
+
+// Error returns the reason of truncating the search.
+func (e *TruncatedError) Error() string {
+	return "search truncated: " + e.Err.Error()
+}
+
+// Unwrap returns the reason of truncating the search.
+func (e *TruncatedError) Unwrap() error {
+	return e.Err
+}
+
+// CheckSize returns a *GraphTooLargeError if g has more than maxNodes nodes or maxEdges edges.
+func (g *Graph) CheckSize(maxNodes, maxEdges int) error {
+	nodes := len(g.internNodes().nodes)
+
+	if nodes > maxNodes || len(g.Edges) > maxEdges {
+		return &GraphTooLargeError{
+			Nodes:    nodes,
+			Edges:    len(g.Edges),
+			MaxNodes: maxNodes,
+			MaxEdges: maxEdges,
+		}
+	}
+
+	return nil
+}
+
+// checkNodes returns an *UnknownNodeError for the first of nodes which is not a node of g.
+func (g *Graph) checkNodes(nodes ...Node) error {
+	for _, n := range nodes {
+		if !g.HasNode(n) {
+			return &UnknownNodeError{Node: n}
+		}
+	}
+
+	return nil
+}
+
+// checkNonNegativeWeights returns an *InvalidWeightError for the first edge of g with a negative weight.
+func (g *Graph) checkNonNegativeWeights() error {
+	for _, e := range g.Edges {
+		if e.Weight < 0 {
+			return &InvalidWeightError{Edge: e, Err: ErrNegativeWeight}
+		}
+	}
+
+	return nil
+}
+
+// checkFiniteWeight returns an *InvalidWeightError if the weight of e is NaN or infinite.
+func checkFiniteWeight(e Edge) error {
+	if math.IsNaN(e.Weight) || math.IsInf(e.Weight, 0) {
+		return &InvalidWeightError{Edge: e, Err: ErrNonFiniteWeight}
+	}
+
+	return nil
+}
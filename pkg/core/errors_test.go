@@ -0,0 +1,89 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestCheckSize(t *testing.T) {
+	t.Parallel()
+
+	nodeA := Node{Name: "A"}
+	nodeB := Node{Name: "B"}
+	nodeC := Node{Name: "C"}
+
+	graph := Graph{
+		Nodes: []Node{nodeA, nodeB},
+		Edges: []Edge{
+			{Nodes: [2]Node{nodeA, nodeB}, Weight: 1},
+			{Nodes: [2]Node{nodeB, nodeC}, Weight: 1},
+		},
+	}
+
+	if err := graph.CheckSize(3, 2); err != nil {
+		t.Errorf("CheckSize did not work. Got error %v", err)
+	}
+
+	// Nodes only appearing in edges are counted as well
+	var tooLarge *GraphTooLargeError
+
+	if err := graph.CheckSize(2, 2); !errors.As(err, &tooLarge) || tooLarge.Nodes != 3 || tooLarge.Edges != 2 {
+		t.Errorf("CheckSize did not work. Got error %v instead of a GraphTooLargeError of 3 nodes and 2 edges", err)
+	}
+
+	if err := graph.CheckSize(3, 1); !errors.As(err, &tooLarge) {
+		t.Errorf("CheckSize did not work. Got error %v instead of a GraphTooLargeError", err)
+	}
+}
+
+func TestTypedErrors(t *testing.T) {
+	t.Parallel()
+
+	nodeA := Node{Name: "A"}
+	nodeB := Node{Name: "B"}
+	nodeX := Node{Name: "X"}
+
+	edgeAB := Edge{
+		Nodes:  [2]Node{nodeA, nodeB},
+		Weight: -1,
+	}
+
+	graph := Graph{
+		Nodes: []Node{nodeA, nodeB},
+		Edges: []Edge{edgeAB},
+	}
+
+	// Negative weights are reported with their edge
+	var weightErr *InvalidWeightError
+
+	if _, err := graph.MaxFlow(nodeA, nodeB); !errors.As(err, &weightErr) || weightErr.Edge != edgeAB || !errors.Is(err, ErrNegativeWeight) {
+		t.Errorf("MaxFlow did not work. Got error %v instead of an InvalidWeightError of %v", err, edgeAB)
+	}
+
+	graph.Edges[0].Weight = 1
+
+	// Unknown nodes are reported, and they are missing nodes
+	var nodeErr *UnknownNodeError
+
+	if _, err := graph.GenerateKShortestPaths(context.Background(), nodeA, nodeX, 1); !errors.As(err, &nodeErr) || nodeErr.Node != nodeX || !errors.Is(err, ErrNodeMissing) {
+		t.Errorf("GenerateKShortestPaths did not work. Got error %v instead of an UnknownNodeError of %v", err, nodeX)
+	}
+
+	if _, err := graph.GenerateLowestWeightPathsBellmanFord(context.Background(), nodeX, nodeA); !errors.As(err, &nodeErr) || nodeErr.Node != nodeX {
+		t.Errorf("GenerateLowestWeightPathsBellmanFord did not work. Got error %v instead of an UnknownNodeError of %v", err, nodeX)
+	}
+
+	// Parameters out of range are reported with their name
+	var parameterErr *InvalidParameterError
+
+	if _, err := graph.GenerateKShortestPaths(context.Background(), nodeA, nodeB, 0); !errors.As(err, &parameterErr) || parameterErr.Field != "k" {
+		t.Errorf("GenerateKShortestPaths did not work. Got error %v instead of an InvalidParameterError of k", err)
+	}
+
+	// Weights have to be finite
+	if err := graph.AddEdge(Edge{Nodes: [2]Node{nodeB, nodeA}, Weight: math.NaN()}); !errors.Is(err, ErrNonFiniteWeight) {
+		t.Errorf("AddEdge did not work. Got error %v instead of %v", err, ErrNonFiniteWeight)
+	}
+}
@@ -0,0 +1,176 @@
+package core
+
+import "math"
+
+// EdgeFlow represents the flow assigned to an edge.
+type EdgeFlow struct {
+	Edge Edge    `json:"edge"`
+	Flow float64 `json:"flow"`
+}
+
+// Flow represents a maximum flow between two nodes and the corresponding minimum cut.
+type Flow struct {
+	Value  float64    `json:"value"`  // total flow leaving the source
+	Edges  []EdgeFlow `json:"edges"`  // flow of every edge of the graph
+	MinCut Graph      `json:"minCut"` // saturated edges separating the source from the sink
+}
+
+// flowNetwork is a residual network for Dinic's algorithm.
+// Edge 2i is the forward residual edge of Graph.Edges[i], edge 2i+1 is its reverse.
+type flowNetwork struct {
+	idx       *index
+	adjacency [][]int   // residual edges by node id
+	capacity  []float64 // residual capacities
+	levels    []int
+	nexts     []int
+}
+
+// newFlowNetwork builds the residual network of g, using edge weights as capacities.
+func (g *Graph) newFlowNetwork(idx *index) *flowNetwork {
+	network := &flowNetwork{
+		idx:       idx,
+		adjacency: make([][]int, len(idx.nodes)),
+		capacity:  make([]float64, 2*len(g.Edges)),
+		levels:    make([]int, len(idx.nodes)),
+		nexts:     make([]int, len(idx.nodes)),
+	}
+
+	for e := range g.Edges {
+		network.capacity[2*e] = idx.weights[e]
+		network.adjacency[idx.from[e]] = append(network.adjacency[idx.from[e]], 2*e)
+		network.adjacency[idx.to[e]] = append(network.adjacency[idx.to[e]], 2*e+1)
+	}
+
+	return network
+}
+
+// head returns the node a residual edge leads to.
+func (n *flowNetwork) head(r int) int {
+	if r%2 == 0 {
+		return n.idx.to[r/2]
+	}
+
+	return n.idx.from[r/2]
+}
+
+// buildLevels labels nodes by their distance from src in the residual network.
+// It returns whether sink is reachable.
+func (n *flowNetwork) buildLevels(src, sink int) bool {
+	for i := range n.levels {
+		n.levels[i] = -1
+	}
+
+	n.levels[src] = 0
+	queue := []int{src}
+
+	for i := 0; i < len(queue); i++ {
+		for _, r := range n.adjacency[queue[i]] {
+			if w := n.head(r); n.capacity[r] > 0 && n.levels[w] == -1 {
+				n.levels[w] = n.levels[queue[i]] + 1
+				queue = append(queue, w)
+			}
+		}
+	}
+
+	return n.levels[sink] != -1
+}
+
+// augment pushes at most limit flow from v to sink along the level graph.
+// It returns the flow pushed.
+func (n *flowNetwork) augment(v, sink int, limit float64) float64 {
+	if v == sink {
+		return limit
+	}
+
+	for ; n.nexts[v] < len(n.adjacency[v]); n.nexts[v]++ {
+		r := n.adjacency[v][n.nexts[v]]
+		w := n.head(r)
+
+		if n.capacity[r] <= 0 || n.levels[w] != n.levels[v]+1 {
+			continue
+		}
+
+		if pushed := n.augment(w, sink, math.Min(limit, n.capacity[r])); pushed > 0 {
+			n.capacity[r] -= pushed
+			n.capacity[r^1] += pushed
+
+			return pushed
+		}
+	}
+
+	return 0
+}
+
+// MaxFlow finds a maximum flow from node1 to node2 using Dinic's algorithm,
+// with edge weights as capacities.
+// It returns the flow value, the flow of every edge and the minimum cut.
+// Capacities must not be negative, otherwise an *InvalidWeightError is returned.
+// It returns an *UnknownNodeError if node1 or node2 is not a node of g.
+func (g *Graph) MaxFlow(node1, node2 Node) (Flow, error) {
+	var flow Flow
+
+	if err := g.checkNonNegativeWeights(); err != nil {
+		return flow, err
+	}
+
+	if err := g.checkNodes(node1, node2); err != nil {
+		return flow, err
+	}
+
+	var (
+		idx     = g.buildIndex()
+		network = g.newFlowNetwork(idx)
+	)
+
+	src, ok1 := idx.id(node1)
+	sink, ok2 := idx.id(node2)
+
+	if ok1 && ok2 && src != sink {
+		for network.buildLevels(src, sink) {
+			for i := range network.nexts {
+				network.nexts[i] = 0
+			}
+
+			for pushed := network.augment(src, sink, math.Inf(1)); pushed > 0; pushed = network.augment(src, sink, math.Inf(1)) {
+				flow.Value += pushed
+			}
+		}
+
+		// Nodes still reachable from the source form its side of the minimum cut
+		network.buildLevels(src, sink)
+	}
+
+	flow.Edges = make([]EdgeFlow, len(g.Edges))
+	flow.MinCut.Nodes = []Node{}
+	flow.MinCut.Edges = []Edge{}
+
+	for e := range g.Edges {
+		// Flow on an edge is the capacity used up, that is the residual capacity of its reverse
+		flow.Edges[e] = EdgeFlow{
+			Edge: g.Edges[e],
+			Flow: network.capacity[2*e+1],
+		}
+
+		if ok1 && ok2 && src != sink && network.levels[idx.from[e]] != -1 && network.levels[idx.to[e]] == -1 {
+			flow.MinCut.Edges = append(flow.MinCut.Edges, g.Edges[e])
+		}
+	}
+
+	for _, e := range flow.MinCut.Edges {
+		flow.MinCut.Nodes = appendNode(flow.MinCut.Nodes, e.Nodes[0])
+		flow.MinCut.Nodes = appendNode(flow.MinCut.Nodes, e.Nodes[1])
+	}
+
+	return flow, nil
+}
+
+// appendNode appends n to nodes unless it is already present.
+func appendNode(nodes []Node, n Node) []Node {
+	for i := range nodes {
+		if nodes[i].Equals(n) {
+			return nodes
+		}
+	}
+
+	return append(nodes, n)
+}
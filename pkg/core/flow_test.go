@@ -0,0 +1,107 @@
+package core
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+// checkFlow checks capacity constraints and flow conservation of flow from src to sink,
+// and that the minimum cut weighs as much as the flow.
+func checkFlow(t *testing.T, flow Flow, src, sink Node) {
+	t.Helper()
+
+	var (
+		balances  = make(map[string]float64)
+		cutWeight float64
+	)
+
+	for _, f := range flow.Edges {
+		if f.Flow < 0 || f.Flow > f.Edge.Weight {
+			t.Errorf("MaxFlow did not work. Got flow %v on edge %v", f.Flow, f.Edge)
+		}
+
+		balances[f.Edge.Nodes[0].Name] -= f.Flow
+		balances[f.Edge.Nodes[1].Name] += f.Flow
+	}
+
+	for name, balance := range balances {
+		if name != src.Name && name != sink.Name && balance != 0 {
+			t.Errorf("MaxFlow did not work. Got balance %v at %v", balance, name)
+		}
+	}
+
+	if balances[sink.Name] != flow.Value {
+		t.Errorf("MaxFlow did not work. Got %v instead of %v", balances[sink.Name], flow.Value)
+	}
+
+	for _, e := range flow.MinCut.Edges {
+		cutWeight += e.Weight
+	}
+
+	if cutWeight != flow.Value {
+		t.Errorf("MaxFlow did not work. Got minimum cut of %v instead of %v", cutWeight, flow.Value)
+	}
+}
+
+func TestMaxFlow(t *testing.T) {
+	t.Parallel()
+
+	nodeS := Node{Name: "S"}
+	nodeA := Node{Name: "A"}
+	nodeB := Node{Name: "B"}
+	nodeC := Node{Name: "C"}
+	nodeD := Node{Name: "D"}
+	nodeT := Node{Name: "T"}
+
+	graph := Graph{
+		Nodes: []Node{nodeS, nodeA, nodeB, nodeC, nodeD, nodeT},
+		Edges: []Edge{
+			{Nodes: [2]Node{nodeS, nodeA}, Weight: 16},
+			{Nodes: [2]Node{nodeS, nodeB}, Weight: 13},
+			{Nodes: [2]Node{nodeA, nodeC}, Weight: 12},
+			{Nodes: [2]Node{nodeB, nodeA}, Weight: 4},
+			{Nodes: [2]Node{nodeB, nodeD}, Weight: 14},
+			{Nodes: [2]Node{nodeC, nodeB}, Weight: 9},
+			{Nodes: [2]Node{nodeC, nodeT}, Weight: 20},
+			{Nodes: [2]Node{nodeD, nodeC}, Weight: 7},
+			{Nodes: [2]Node{nodeD, nodeT}, Weight: 4},
+		},
+	}
+
+	// Case 1: maximum flow
+	flow, err := graph.MaxFlow(nodeS, nodeT)
+
+	if err != nil {
+		t.Errorf("MaxFlow did not work. Got error %v", err)
+	}
+
+	if flow.Value != 23 {
+		t.Errorf("MaxFlow did not work. Got %v instead of %v", flow.Value, 23)
+	}
+
+	checkFlow(t, flow, nodeS, nodeT)
+
+	// Case 2: no flow
+	if flow, _ = graph.MaxFlow(nodeT, nodeS); flow.Value != 0 || len(flow.MinCut.Edges) != 0 {
+		t.Errorf("MaxFlow did not work. Got %v instead of %v", flow.Value, 0)
+	}
+
+	// Case 3: random graphs
+	r := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 50; i++ {
+		graph := randomGraph(r, 8, 0.3, 0, 10)
+
+		flow, _ := graph.MaxFlow(graph.Nodes[0], graph.Nodes[7])
+
+		checkFlow(t, flow, graph.Nodes[0], graph.Nodes[7])
+	}
+
+	// Case 4: negative capacity
+	graph.Edges[0].Weight = -1
+
+	if _, err = graph.MaxFlow(nodeS, nodeT); !errors.Is(err, ErrNegativeWeight) {
+		t.Errorf("MaxFlow did not work. Got error %v instead of %v", err, ErrNegativeWeight)
+	}
+}
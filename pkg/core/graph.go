@@ -1,20 +1,32 @@
 package core
 
 import (
-	"sync"
-
-	"github.com/ellescotz/graph_backend/pkg/utils"
+	"context"
 )
 
+// Position represents the coordinates of a node.
+// For geographic graphs X is the longitude and Y is the latitude in degrees.
+type Position struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+// Attributes represents arbitrary key-value attributes.
+type Attributes map[string]string
+
 // Node represents a node in the graph.
+// Nodes are identified by their names.
 type Node struct {
-	Name string `json:"name"`
+	Name       string      `json:"name"`
+	Position   *Position   `json:"position,omitempty"`   // optional
+	Attributes *Attributes `json:"attributes,omitempty"` // optional, pointer keeps Node comparable
 }
 
 // Edge represents an edge in the graph.
 type Edge struct {
-	Nodes  [2]Node `json:"nodes"` // cannot be identical
-	Weight float64 `json:"weight"`
+	Nodes      [2]Node     `json:"nodes"` // cannot be identical
+	Weight     float64     `json:"weight"`
+	Attributes *Attributes `json:"attributes,omitempty"` // optional, pointer keeps Edge comparable
 }
 
 // Graph represents a directed graph with its nodes and edges.
@@ -35,10 +47,52 @@ func (n *Node) Equals(node2 Node) bool {
 	return n.Name == node2.Name
 }
 
+// Attribute returns the value of the attribute of n with the given key.
+// ok is false if n has no such attribute.
+func (n *Node) Attribute(key string) (value string, ok bool) {
+	if n.Attributes == nil {
+		return "", false
+	}
+
+	value, ok = (*n.Attributes)[key]
+
+	return value, ok
+}
+
+// SetAttribute sets the attribute of n with the given key to value.
+func (n *Node) SetAttribute(key, value string) {
+	if n.Attributes == nil {
+		n.Attributes = &Attributes{}
+	}
+
+	(*n.Attributes)[key] = value
+}
+
+// Attribute returns the value of the attribute of e with the given key.
+// ok is false if e has no such attribute.
+func (e *Edge) Attribute(key string) (value string, ok bool) {
+	if e.Attributes == nil {
+		return "", false
+	}
+
+	value, ok = (*e.Attributes)[key]
+
+	return value, ok
+}
+
+// SetAttribute sets the attribute of e with the given key to value.
+func (e *Edge) SetAttribute(key, value string) {
+	if e.Attributes == nil {
+		e.Attributes = &Attributes{}
+	}
+
+	(*e.Attributes)[key] = value
+}
+
 // Equals checks equality between e and edge2.
 // It returns true if equal; otherwise false.
 func (e *Edge) Equals(edge2 *Edge) bool {
-	return e.Nodes == edge2.Nodes && e.Weight == edge2.Weight
+	return e.Nodes[0].Equals(edge2.Nodes[0]) && e.Nodes[1].Equals(edge2.Nodes[1]) && e.Weight == edge2.Weight
 }
 
 // Copy returns a copy of g.
@@ -51,6 +105,29 @@ func (g *Graph) Copy() Graph {
 	return copyGraph
 }
 
+// HasNode reports whether n is a node of g,
+// either listed in g.Nodes or as an end of any edge.
+func (g *Graph) HasNode(n Node) bool {
+	for i := range g.Nodes {
+		if g.Nodes[i].Equals(n) {
+			return true
+		}
+	}
+
+	for i := range g.Edges {
+		if g.Edges[i].Nodes[0].Equals(n) || g.Edges[i].Nodes[1].Equals(n) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AllNodes returns the nodes of g, followed by the nodes only appearing in edges of g.
+func (g *Graph) AllNodes() []Node {
+	return append([]Node{}, g.internNodes().nodes...)
+}
+
 // Copy returns a copy of P.
 func (p *Path) Copy() Path {
 	return Path{
@@ -69,178 +146,120 @@ func (p *Path) GetWeight() float64 {
 	return p.Weight
 }
 
-// findPathOnGraphWithoutEdgeRepetition generates subgraph
-// containing all nodes starting from node1 ending with node2.
-// It walks on g's nodes and edges without edge repetition.
-// If a path is finished it puts it into paths map.
-// It is a recursive function.
-// excludeNodes: a map containing nodes to track path.
-// path: pointer to the currently examined path.
-// maxLength: max. number of nodes in path.
-// maxWeight: max. sum weight of path.
-func (g *Graph) findPathOnGraphWithoutEdgeRepetition(node1, node2 Node,
-	excludeNodes *sync.Map, path *Path, paths *sync.Map, maxLength int, maxWeight float64) {
-	// Appending node1 to path
-	path.Subgraph.Nodes = append(path.Subgraph.Nodes, node1)
-
-	// Excluding current node
-	excludeNodes.Store(node1, true)
+// PathFunc is called by the Stream* functions of Graph with every path as soon as it is found.
+// Returning an error stops the search, which then returns that error.
+// It is never called concurrently, even by searches with several workers (see WithWorkers).
+type PathFunc func(path Path) error
 
-	var (
-		wG        sync.WaitGroup
-		stopLoop  = make(chan bool)
-		pathFound bool
-	)
+// collectPaths returns the paths given by stream to its PathFunc, and the error of stream.
+func collectPaths(stream func(emit PathFunc) error) ([]Path, error) {
+	pathSlice := make([]Path, 0)
 
-	// Checking whether node1 is just one edge away from node2
-	for i := 0; i < len(g.Edges) && !pathFound; i++ {
-		wG.Add(1)
-
-		go func(i int, stopLoop chan bool) {
-			defer wG.Done()
-
-			select {
-			case _, ok := <-stopLoop:
-				if !ok {
-					return
-				}
-			default:
-				if g.Edges[i].Nodes[0].Equals(node1) && g.Edges[i].Nodes[1].Equals(node2) {
-					close(stopLoop)
-					pathFound = true
-
-					path.Subgraph.Nodes = append(path.Subgraph.Nodes, node2)
-					path.Subgraph.Edges = append(path.Subgraph.Edges, g.Edges[i])
-
-					if sumWeight := path.GetWeight(); sumWeight <= maxWeight || maxWeight == -1 {
-						paths.Store(utils.SyncMapLength(*paths), path.Copy())
-
-						// In order to check other possibilities, last element has to be removed.
-						path.Subgraph.Nodes = path.Subgraph.Nodes[:len(path.Subgraph.Nodes)-1]
-						path.Subgraph.Edges = path.Subgraph.Edges[:len(path.Subgraph.Edges)-1]
-					}
-
-					// Making sure new path is not equal to current one
-					excludeNodes.Store(node2, true)
-				}
-			}
-		}(i, stopLoop)
-	}
-
-	wG.Wait()
-
-	if sumWeight := path.GetWeight(); (sumWeight <= maxWeight || maxWeight == -1) && len(path.Subgraph.Nodes) < maxLength {
-		// Walking further from node1 on every nonexcluded edge in g.
-		for i := range g.Edges {
-			present, ok := excludeNodes.Load(g.Edges[i].Nodes[1])
-
-			if g.Edges[i].Nodes[0].Equals(node1) && (!ok || present == false) {
-				path.Subgraph.Edges = append(path.Subgraph.Edges, g.Edges[i])
-
-				g.findPathOnGraphWithoutEdgeRepetition(g.Edges[i].Nodes[1], node2, excludeNodes, path, paths, maxLength, maxWeight)
-			}
-		}
-	}
-
-	// If every adjacent node is excluded, or there is no edge from node1,
-	// remove last node and edge from path.
-	path.Subgraph.Nodes = path.Subgraph.Nodes[:len(path.Subgraph.Nodes)-1]
-
-	if len(path.Subgraph.Edges) > 0 {
-		path.Subgraph.Edges = path.Subgraph.Edges[:len(path.Subgraph.Edges)-1]
-	}
+	err := stream(func(path Path) error {
+		pathSlice = append(pathSlice, path)
+		return nil
+	})
 
-	// Including node1 again
-	excludeNodes.Store(node1, false)
+	return pathSlice, err
 }
 
 // GeneratePathsWithoutEdgeRepetition finds all paths from node1 to node2.
 // Each path contains an edge only once, no repetition is allowed.
 // It returns a map of all paths.
-func (g *Graph) GeneratePathsWithoutEdgeRepetition(node1, node2 Node) []Path {
-	var (
-		checkedNodes = sync.Map{} // to avoid repetition
-		paths        = sync.Map{} // contains all paths of g from node1 to node2
-		path         Path         // current path
-		pathSlice    = make([]Path, 0, utils.SyncMapLength(paths))
-	)
-
-	g.findPathOnGraphWithoutEdgeRepetition(node1, node2, &checkedNodes, &path, &paths, len(g.Nodes), -1)
-
-	paths.Range(func(key, value interface{}) bool {
-		path := value.(Path)
-
-		pathSlice = append(pathSlice, path)
+// If ctx is done or its budget is exhausted, it returns the paths found until then with a *TruncatedError.
+func (g *Graph) GeneratePathsWithoutEdgeRepetition(ctx context.Context, node1, node2 Node) ([]Path, error) {
+	return g.pathsWithoutEdgeRepetition(ctx, g.buildIndex(), node1, node2)
+}
 
-		return true
+// pathsWithoutEdgeRepetition is GeneratePathsWithoutEdgeRepetition on the index of g.
+func (g *Graph) pathsWithoutEdgeRepetition(ctx context.Context, idx *index, node1, node2 Node) ([]Path, error) {
+	return collectPaths(func(emit PathFunc) error {
+		return g.streamPaths(ctx, idx, node1, node2, len(g.Nodes), -1, func([]int, float64) bool { return true }, emit)
 	})
-
-	return pathSlice
 }
 
 // GeneratePthsWithMaxSteps finds paths from node1 to node2 in no more than numberOfEdges steps.
 // Edge repetition is not allowed.
 // numberOfEdges: maximum amount of edges required for paths.
 // exactSteps: true, if paths have to contain exactly numberOfEdges steps; otherwise false.
-func (g *Graph) GeneratePathsWithMaxSteps(node1, node2 Node, numberOfEdges int, exactSteps bool) []Path {
-	var (
-		checkedNodes = sync.Map{} // to avoid repetition
-		paths        = sync.Map{} // contains all paths of g from node1 to node2
-		path         Path         // current path
-		pathSlice    = make([]Path, 0, utils.SyncMapLength(paths))
-	)
-
-	g.findPathOnGraphWithoutEdgeRepetition(node1, node2, &checkedNodes, &path, &paths, numberOfEdges, -1)
-
-	paths.Range(func(key, value interface{}) bool {
-		path := value.(Path)
-
-		if (exactSteps && len(path.Subgraph.Nodes) == numberOfEdges+1) || !exactSteps {
-			pathSlice = append(pathSlice, path)
-		}
-
-		return true
+// If ctx is done or its budget is exhausted, it returns the paths found until then with a *TruncatedError.
+func (g *Graph) GeneratePathsWithMaxSteps(ctx context.Context, node1, node2 Node, numberOfEdges int, exactSteps bool) ([]Path, error) {
+	return collectPaths(func(emit PathFunc) error {
+		return g.StreamPathsWithMaxSteps(ctx, node1, node2, numberOfEdges, exactSteps, emit)
 	})
+}
 
-	return pathSlice
+// StreamPathsWithMaxSteps gives the paths of GeneratePathsWithMaxSteps to emit as soon as they are found.
+// If ctx is done or its budget is exhausted, it stops with a *TruncatedError.
+func (g *Graph) StreamPathsWithMaxSteps(ctx context.Context, node1, node2 Node, numberOfEdges int, exactSteps bool, emit PathFunc) error {
+	return g.streamPaths(ctx, g.buildIndex(), node1, node2, numberOfEdges, -1, func(edges []int, _ float64) bool {
+		return (exactSteps && len(edges) == numberOfEdges) || !exactSteps
+	}, emit)
 }
 
 // GeneratePathsWithMaxWeight finds paths from node1 to node2 with no greater weight than sumWeight.
 // Edge repetition is not allowed.
 // sumWeight: maximum sum of weight along the paths.
 // exactWeight: true, if paths can have exactly sumWeight; otherwise false.
-func (g *Graph) GeneratePathsWithMaxWeight(node1, node2 Node, sumWeight float64, exactWeight bool) []Path {
-	var (
-		checkedNodes = sync.Map{} // to avoid repetition
-		paths        = sync.Map{} // contains all paths of g from node1 to node2
-		path         Path         // current path
-		pathSlice    = make([]Path, 0, utils.SyncMapLength(paths))
-	)
+// If ctx is done or its budget is exhausted, it returns the paths found until then with a *TruncatedError.
+func (g *Graph) GeneratePathsWithMaxWeight(ctx context.Context, node1, node2 Node, sumWeight float64, exactWeight bool) ([]Path, error) {
+	return collectPaths(func(emit PathFunc) error {
+		return g.StreamPathsWithMaxWeight(ctx, node1, node2, sumWeight, exactWeight, emit)
+	})
+}
+
+// StreamPathsWithMaxWeight gives the paths of GeneratePathsWithMaxWeight to emit as soon as they are found.
+// If ctx is done or its budget is exhausted, it stops with a *TruncatedError.
+func (g *Graph) StreamPathsWithMaxWeight(ctx context.Context, node1, node2 Node, sumWeight float64, exactWeight bool, emit PathFunc) error {
+	return g.streamPaths(ctx, g.buildIndex(), node1, node2, len(g.Nodes), sumWeight, func(_ []int, weight float64) bool {
+		return (exactWeight && weight == sumWeight) || !exactWeight
+	}, emit)
+}
 
-	g.findPathOnGraphWithoutEdgeRepetition(node1, node2, &checkedNodes, &path, &paths, len(g.Nodes), sumWeight)
+// GenerateLowestHighestWeightPath finds the path from node1 to node2 that is
+// either the lowest or highest weighted path.
+// lowest: true, if path is the lowest weighted; otherwise false.
+func (g *Graph) GenerateLowestHighestWeightPath(ctx context.Context, node1, node2 Node, lowest bool) ([]Path, error) {
+	paths, _, err := g.GenerateLowestHighestWeightPathWithStrategy(ctx, node1, node2, lowest)
+
+	return paths, err
+}
+
+// GenerateLowestHighestWeightPathWithStrategy is GenerateLowestHighestWeightPath
+// that also returns the strategy used.
+// Lowest weighted paths are found with Dijkstra's algorithm unless g has negative weights.
+// Highest weighted paths are found in topological order if g is acyclic.
+// Otherwise it is a naive solution to Travelling Salesperson Problem and Hamiltonian Cycle Problem.
+// If ctx is done or its budget is exhausted, it returns the best paths found until then with a *TruncatedError.
+func (g *Graph) GenerateLowestHighestWeightPathWithStrategy(ctx context.Context, node1, node2 Node, lowest bool) ([]Path, Strategy, error) {
+	idx := g.buildIndex()
 
-	paths.Range(func(key, value interface{}) bool {
-		path := value.(Path)
+	if lowest && !idx.hasNegativeWeight() {
+		paths, err := g.lowestWeightPaths(ctx, idx, node1, node2)
 
-		if (exactWeight && path.Weight == sumWeight) || !exactWeight {
-			pathSlice = append(pathSlice, path)
+		return paths, StrategyDijkstra, err
+	}
+
+	if !lowest {
+		search := newSearch(ctx)
+
+		if paths, ok := g.generateHighestPathsDAG(search, idx, node1, node2, idx.weight); ok {
+			return paths, StrategyTopological, search.result()
 		}
+	}
 
-		return true
-	})
+	paths, err := g.generateLowestHighestWeightPathExhaustive(ctx, idx, node1, node2, lowest)
 
-	return pathSlice
+	return paths, StrategyExhaustive, err
 }
 
-// GenerateLowestHighestWeightPath finds the path from node1 to node2 that is
-// either the lowest or highest weighted path.
-// lowest: true, if path is the lowest weighted; otherwise false.
-// Naive solution to Travelling Salesperson Problem and Hamiltonian Cycle Problem.
-func (g *Graph) GenerateLowestHighestWeightPath(node1, node2 Node, lowest bool) []Path {
-	paths := g.GeneratePathsWithoutEdgeRepetition(node1, node2)
+// generateLowestHighestWeightPathExhaustive finds the lowest or highest weighted paths
+// from node1 to node2 by enumerating every path.
+func (g *Graph) generateLowestHighestWeightPathExhaustive(ctx context.Context, idx *index, node1, node2 Node, lowest bool) ([]Path, error) {
+	paths, err := g.pathsWithoutEdgeRepetition(ctx, idx, node1, node2)
 
 	if len(paths) == 0 {
-		return []Path{}
+		return []Path{}, err
 	}
 
 	var (
@@ -266,16 +285,48 @@ func (g *Graph) GenerateLowestHighestWeightPath(node1, node2 Node, lowest bool)
 		}
 	}
 
-	return relevantPaths
+	return relevantPaths, err
 }
 
 // GenerateShortestLongestPath find the shortest/longest path from node1 to node2.
 // shortest: true, if path is the shortest; otherwise false.
-func (g *Graph) GenerateShortestLongestPath(node1, node2 Node, shortest bool) []Path {
-	paths := g.GeneratePathsWithoutEdgeRepetition(node1, node2)
+func (g *Graph) GenerateShortestLongestPath(ctx context.Context, node1, node2 Node, shortest bool) ([]Path, error) {
+	paths, _, err := g.GenerateShortestLongestPathWithStrategy(ctx, node1, node2, shortest)
+
+	return paths, err
+}
+
+// GenerateShortestLongestPathWithStrategy is GenerateShortestLongestPath
+// that also returns the strategy used.
+// Longest paths are found in topological order if g is acyclic;
+// otherwise every path is enumerated.
+// If ctx is done or its budget is exhausted, it returns the best paths found until then with a *TruncatedError.
+func (g *Graph) GenerateShortestLongestPathWithStrategy(ctx context.Context, node1, node2 Node, shortest bool) ([]Path, Strategy, error) {
+	idx := g.buildIndex()
+
+	if !shortest {
+		search := newSearch(ctx)
+
+		paths, ok := g.generateHighestPathsDAG(search, idx, node1, node2, func(e int) float64 {
+			return 1
+		})
+		if ok {
+			return paths, StrategyTopological, search.result()
+		}
+	}
+
+	paths, err := g.generateShortestLongestPathExhaustive(ctx, idx, node1, node2, shortest)
+
+	return paths, StrategyExhaustive, err
+}
+
+// generateShortestLongestPathExhaustive finds the shortest or longest paths
+// from node1 to node2 by enumerating every path.
+func (g *Graph) generateShortestLongestPathExhaustive(ctx context.Context, idx *index, node1, node2 Node, shortest bool) ([]Path, error) {
+	paths, err := g.pathsWithoutEdgeRepetition(ctx, idx, node1, node2)
 
 	if len(paths) == 0 {
-		return []Path{}
+		return []Path{}, err
 	}
 
 	var (
@@ -301,5 +352,5 @@ func (g *Graph) GenerateShortestLongestPath(node1, node2 Node, shortest bool) []
 		}
 	}
 
-	return relevantPaths
+	return relevantPaths, err
 }
@@ -1,6 +1,8 @@
 package core
 
 import (
+	"context"
+	"errors"
 	"testing"
 )
 
@@ -102,6 +104,57 @@ func TestCopy(t *testing.T) {
 	}
 }
 
+func TestHasNode(t *testing.T) {
+	t.Parallel()
+
+	nodeA := Node{Name: "warehouse-12"}
+	nodeB := Node{Name: "Zürich"}
+	nodeC := Node{Name: "C"}
+
+	graph := Graph{
+		Nodes: []Node{nodeA},
+		Edges: []Edge{{Nodes: [2]Node{nodeA, nodeB}, Weight: 1}},
+	}
+
+	if !graph.HasNode(Node{Name: "warehouse-12"}) {
+		t.Errorf("HasNode did not work. Got false instead of true")
+	}
+
+	if !graph.HasNode(nodeB) {
+		t.Errorf("HasNode did not work. Got false instead of true")
+	}
+
+	if graph.HasNode(nodeC) {
+		t.Errorf("HasNode did not work. Got true instead of false")
+	}
+}
+
+func TestAllNodes(t *testing.T) {
+	t.Parallel()
+
+	nodeA := Node{Name: "A"}
+	nodeB := Node{Name: "B"}
+	nodeC := Node{Name: "C"}
+
+	graph := Graph{
+		Nodes: []Node{nodeB},
+		Edges: []Edge{{Nodes: [2]Node{nodeA, nodeB}, Weight: 1}, {Nodes: [2]Node{nodeC, nodeA}, Weight: 1}},
+	}
+
+	expected := []Node{nodeB, nodeA, nodeC}
+	nodes := graph.AllNodes()
+
+	if len(nodes) != len(expected) {
+		t.Fatalf("AllNodes did not work. Got %v instead of %v", nodes, expected)
+	}
+
+	for i := range expected {
+		if !nodes[i].Equals(expected[i]) {
+			t.Errorf("AllNodes did not work. Got %v instead of %v", nodes, expected)
+		}
+	}
+}
+
 func TestGeneratePathsWithoutEdgeRepetition(t *testing.T) {
 	t.Parallel()
 
@@ -162,14 +215,14 @@ func TestGeneratePathsWithoutEdgeRepetition(t *testing.T) {
 	}
 
 	// Case 1: no path found
-	paths := graph.GeneratePathsWithoutEdgeRepetition(nodeA, nodeA)
+	paths, _ := graph.GeneratePathsWithoutEdgeRepetition(context.Background(), nodeA, nodeA)
 
 	if len(paths) != 0 {
 		t.Errorf("GeneratePathsWithoutEdgeRepetition did not work. Got %v instead of %v", len(paths), 0)
 	}
 
 	// Case 2: path found
-	paths = graph.GeneratePathsWithoutEdgeRepetition(nodeC, nodeD)
+	paths, _ = graph.GeneratePathsWithoutEdgeRepetition(context.Background(), nodeC, nodeD)
 
 	if len(paths) != 1 {
 		t.Errorf("GeneratePathsWithoutEdgeRepetition did not work. Got %v instead of %v", len(paths), 1)
@@ -252,7 +305,7 @@ func TestGeneratePathsWithMaxSteps(t *testing.T) {
 	}
 
 	// Case 1: path found in exact steps
-	paths := graph.GeneratePathsWithMaxSteps(nodeA, nodeC, 3, true)
+	paths, _ := graph.GeneratePathsWithMaxSteps(context.Background(), nodeA, nodeC, 3, true)
 
 	if len(paths) != 1 {
 		t.Errorf("GeneratePathsWithMaxSteps did not work. Got %v instead of %v", len(paths), 1)
@@ -284,14 +337,14 @@ func TestGeneratePathsWithMaxSteps(t *testing.T) {
 	}
 
 	// Case 2: no path found in exact steps
-	paths = graph.GeneratePathsWithMaxSteps(nodeA, nodeD, 2, true)
+	paths, _ = graph.GeneratePathsWithMaxSteps(context.Background(), nodeA, nodeD, 2, true)
 
 	if len(paths) != 0 {
 		t.Errorf("GeneratePathsWithMaxSteps did not work. Got %v instead of %v", len(paths), 0)
 	}
 
 	// Case 3: path found in maximal steps
-	paths = graph.GeneratePathsWithMaxSteps(nodeC, nodeE, 2, false)
+	paths, _ = graph.GeneratePathsWithMaxSteps(context.Background(), nodeC, nodeE, 2, false)
 
 	if len(paths) != 2 {
 		t.Errorf("GeneratePathsWithMaxSteps did not work. Got %v instead of %v", len(paths), 2)
@@ -329,7 +382,7 @@ func TestGeneratePathsWithMaxSteps(t *testing.T) {
 	}
 
 	// Case 5: no path found in maximal steps
-	paths = graph.GeneratePathsWithMaxSteps(nodeA, nodeA, 2, false)
+	paths, _ = graph.GeneratePathsWithMaxSteps(context.Background(), nodeA, nodeA, 2, false)
 
 	if len(paths) != 0 {
 		t.Errorf("GeneratePathsWithMaxSteps did not work. Got %v instead of %v", len(paths), 0)
@@ -396,14 +449,14 @@ func TestGenerateSubgraphsWithMaxWeight(t *testing.T) {
 	}
 
 	// Case 1: no path found
-	paths := graph.GeneratePathsWithMaxWeight(nodeA, nodeA, 30, true)
+	paths, _ := graph.GeneratePathsWithMaxWeight(context.Background(), nodeA, nodeA, 30, true)
 
 	if len(paths) != 0 {
 		t.Errorf("GeneratePathsWithMaxWeight did not work. Got %v instead of %v", len(paths), 0)
 	}
 
 	// Case 2: path found
-	paths = graph.GeneratePathsWithMaxWeight(nodeA, nodeD, 17, false)
+	paths, _ = graph.GeneratePathsWithMaxWeight(context.Background(), nodeA, nodeD, 17, false)
 
 	if len(paths) != 2 {
 		t.Errorf("GeneratePathsWithMaxWeight did not work. Got %v instead of %v", len(paths), 2)
@@ -441,7 +494,7 @@ func TestGenerateSubgraphsWithMaxWeight(t *testing.T) {
 	}
 
 	// Case 3: path found
-	paths = graph.GeneratePathsWithMaxWeight(nodeA, nodeD, 17, true)
+	paths, _ = graph.GeneratePathsWithMaxWeight(context.Background(), nodeA, nodeD, 17, true)
 
 	if len(paths) != 1 {
 		t.Errorf("GeneratePathsWithMaxWeight did not work. Got %v instead of %v", len(paths), 1)
@@ -473,6 +526,66 @@ func TestGenerateSubgraphsWithMaxWeight(t *testing.T) {
 	}
 }
 
+func TestStreamPaths(t *testing.T) {
+	t.Parallel()
+
+	var (
+		graph        = completeGraph(5)
+		node1, node2 = graph.Nodes[0], graph.Nodes[4]
+		errStop      = errors.New("stop")
+		streamed     []Path
+	)
+
+	// Case 1: every path is streamed
+	err := graph.StreamPathsWithMaxSteps(context.Background(), node1, node2, 5, false, func(path Path) error {
+		streamed = append(streamed, path)
+		return nil
+	})
+
+	if paths, _ := graph.GeneratePathsWithMaxSteps(context.Background(), node1, node2, 5, false); err != nil || len(streamed) != 16 || len(paths) != 16 {
+		t.Errorf("StreamPathsWithMaxSteps did not work. Got %v paths and error %v instead of %v paths", len(streamed), err, 16)
+	}
+
+	// Case 2: failing emit stops the search with its error
+	streamed = nil
+
+	err = graph.StreamPathsWithMaxWeight(context.Background(), node1, node2, 10, false, func(path Path) error {
+		if streamed = append(streamed, path); len(streamed) == 3 {
+			return errStop
+		}
+
+		return nil
+	})
+
+	if err != errStop || len(streamed) != 3 {
+		t.Errorf("StreamPathsWithMaxWeight did not work. Got %v paths and error %v instead of 3 paths and %v", len(streamed), err, errStop)
+	}
+
+	// Case 3: paths closed by too heavy edges do not spoil the following paths
+	nodeA, nodeB, nodeC, nodeD := Node{Name: "A"}, Node{Name: "B"}, Node{Name: "C"}, Node{Name: "D"}
+
+	graph = Graph{
+		Nodes: []Node{nodeA, nodeB, nodeC, nodeD},
+		Edges: []Edge{
+			{Nodes: [2]Node{nodeA, nodeB}, Weight: 1},
+			{Nodes: [2]Node{nodeB, nodeC}, Weight: 10},
+			{Nodes: [2]Node{nodeA, nodeD}, Weight: 1},
+			{Nodes: [2]Node{nodeD, nodeC}, Weight: 1},
+		},
+	}
+	streamed = nil
+
+	err = graph.StreamPathsWithMaxWeight(context.Background(), nodeA, nodeC, 5, false, func(path Path) error {
+		streamed = append(streamed, path)
+		return nil
+	})
+
+	if err != nil || len(streamed) != 1 || len(streamed[0].Subgraph.Nodes) != 3 || len(streamed[0].Subgraph.Edges) != 2 ||
+		streamed[0].Subgraph.Nodes[1] != nodeD || streamed[0].Weight != 2 {
+		t.Errorf("StreamPathsWithMaxWeight did not work. Got %v and error %v instead of the path A, D, C", streamed, err)
+	}
+}
+
 func TestGenerateLowestHighestWeightPath(t *testing.T) {
 	t.Parallel()
 
@@ -533,14 +646,14 @@ func TestGenerateLowestHighestWeightPath(t *testing.T) {
 	}
 
 	// Case 1: HCP
-	paths := graph.GenerateLowestHighestWeightPath(nodeB, nodeB, true)
+	paths, _ := graph.GenerateLowestHighestWeightPath(context.Background(), nodeB, nodeB, true)
 
 	if paths[0].Weight != 9 {
 		t.Errorf("GenerateLowestHighestWeightPath did not work. Got %v instead of %v", paths[0].Weight, 9)
 	}
 
 	// Case 2: TSP
-	paths = graph.GenerateLowestHighestWeightPath(nodeA, nodeD, false)
+	paths, _ = graph.GenerateLowestHighestWeightPath(context.Background(), nodeA, nodeD, false)
 
 	if paths[0].Weight != 22 {
 		t.Errorf("GenerateLowestHighestWeightPath did not work. Got %v instead of %v", paths[0].Weight, 22)
@@ -606,13 +719,13 @@ func TestGenerateShortestLongestPath(t *testing.T) {
 		Edges: []Edge{edgeAB, edgeBC, edgeCD, edgeDC, edgeDE, edgeAD, edgeCE, edgeEB, edgeAE},
 	}
 
-	paths := graph.GenerateShortestLongestPath(nodeB, nodeB, true)
+	paths, _ := graph.GenerateShortestLongestPath(context.Background(), nodeB, nodeB, true)
 
 	if len(paths[0].Subgraph.Edges) != 3 {
 		t.Errorf("GenerateShortestLongestPath did not work. Got %v instead of %v", len(paths[0].Subgraph.Edges), 3)
 	}
 
-	paths = graph.GenerateShortestLongestPath(nodeA, nodeD, false)
+	paths, _ = graph.GenerateShortestLongestPath(context.Background(), nodeA, nodeD, false)
 
 	if len(paths[0].Subgraph.Edges) != 4 {
 		t.Errorf("GenerateShortestLongestPath did not work. Got %v instead of %v", len(paths[0].Subgraph.Edges), 4)
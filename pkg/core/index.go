@@ -0,0 +1,215 @@
+package core
+
+// index is the compiled representation of a graph used by the algorithms, built once per call.
+// It maps node names to dense integer ids and keeps the ends and weights of the edges in arrays by edge index.
+// The outgoing and incoming edges of every node are stored contiguously in compressed sparse rows,
+// ordered by edge index, so that algorithms do not have to scan every edge at each step.
+type index struct {
+	nodes    []Node         // nodes by id
+	ids      map[string]int // node name -> id
+	from     []int          // source node id by edge index
+	to       []int          // target node id by edge index
+	weights  []float64      // weight by edge index
+	outStart []int          // outgoing edges of node v are outEdges[outStart[v]:outStart[v+1]]
+	outEdges []int
+	inStart  []int // incoming edges of node v are inEdges[inStart[v]:inStart[v+1]]
+	inEdges  []int
+}
+
+// internNodes returns an index of the nodes of g without their edges.
+// Nodes that only appear in edges are indexed as well.
+func (g *Graph) internNodes() *index {
+	idx := &index{ids: make(map[string]int, len(g.Nodes))}
+
+	for _, n := range g.Nodes {
+		idx.add(n)
+	}
+
+	for _, e := range g.Edges {
+		idx.add(e.Nodes[0])
+		idx.add(e.Nodes[1])
+	}
+
+	return idx
+}
+
+// buildIndex compiles g into its index.
+func (g *Graph) buildIndex() *index {
+	idx := g.internNodes()
+
+	idx.from = make([]int, len(g.Edges))
+	idx.to = make([]int, len(g.Edges))
+	idx.weights = make([]float64, len(g.Edges))
+
+	for i, e := range g.Edges {
+		idx.from[i] = idx.ids[e.Nodes[0].Name]
+		idx.to[i] = idx.ids[e.Nodes[1].Name]
+		idx.weights[i] = e.Weight
+	}
+
+	idx.outStart, idx.outEdges = idx.rows(idx.from)
+	idx.inStart, idx.inEdges = idx.rows(idx.to)
+
+	return idx
+}
+
+// rows groups the edges by the node ids of ends, by counting sort.
+// It returns the start of the row of every node, followed by the end of the last row, and the edges in rows.
+func (idx *index) rows(ends []int) (start, edges []int) {
+	start = make([]int, len(idx.nodes)+1)
+	edges = make([]int, len(ends))
+
+	for _, v := range ends {
+		start[v+1]++
+	}
+
+	for v := range idx.nodes {
+		start[v+1] += start[v]
+	}
+
+	next := append([]int{}, start[:len(idx.nodes)]...)
+
+	for e, v := range ends {
+		edges[next[v]] = e
+		next[v]++
+	}
+
+	return start, edges
+}
+
+// out returns the outgoing edges of node v in order of their indices.
+func (idx *index) out(v int) []int {
+	return idx.outEdges[idx.outStart[v]:idx.outStart[v+1]]
+}
+
+// in returns the incoming edges of node v in order of their indices.
+func (idx *index) in(v int) []int {
+	return idx.inEdges[idx.inStart[v]:idx.inStart[v+1]]
+}
+
+// weight returns the weight of the edge at index e.
+func (idx *index) weight(e int) float64 {
+	return idx.weights[e]
+}
+
+// hasNegativeWeight reports whether any edge has a negative weight.
+func (idx *index) hasNegativeWeight() bool {
+	for _, w := range idx.weights {
+		if w < 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// add registers n in idx unless it is already present.
+// It returns the id of n.
+func (idx *index) add(n Node) int {
+	if id, ok := idx.ids[n.Name]; ok {
+		return id
+	}
+
+	idx.ids[n.Name] = len(idx.nodes)
+	idx.nodes = append(idx.nodes, n)
+
+	return len(idx.nodes) - 1
+}
+
+// id returns the id of n.
+// ok is false if n is not part of the graph.
+func (idx *index) id(n Node) (id int, ok bool) {
+	id, ok = idx.ids[n.Name]
+
+	return id, ok
+}
+
+// pathFromEdges builds a Path walking along the given edges of g in order.
+func (g *Graph) pathFromEdges(edges []int) Path {
+	var path Path
+
+	if len(edges) == 0 {
+		return path
+	}
+
+	path.Subgraph.Nodes = append(path.Subgraph.Nodes, g.Edges[edges[0]].Nodes[0])
+
+	for _, e := range edges {
+		path.Subgraph.Nodes = append(path.Subgraph.Nodes, g.Edges[e].Nodes[1])
+		path.Subgraph.Edges = append(path.Subgraph.Edges, g.Edges[e])
+	}
+
+	path.GetWeight()
+
+	return path
+}
+
+// pathsFromSequences converts edge sequences of g into Paths.
+func (g *Graph) pathsFromSequences(sequences [][]int) []Path {
+	paths := make([]Path, 0, len(sequences))
+
+	for _, sequence := range sequences {
+		paths = append(paths, g.pathFromEdges(sequence))
+	}
+
+	return paths
+}
+
+// tiedEdgeSequences enumerates every edge sequence leading from src to dst
+// along predecessor edges.
+// preds holds, by node id, the edges through which the node is reached at its best distance.
+// Nodes are not repeated within a sequence.
+// Every step backwards is an explored state of s, and every sequence a path found.
+func (idx *index) tiedEdgeSequences(s *searchState, preds [][]int, src, dst int) [][]int {
+	var (
+		sequences [][]int
+		reversed  []int
+		onPath    = make([]bool, len(idx.nodes))
+		walk      func(node int)
+	)
+
+	// Walking backwards from dst to src
+	walk = func(node int) {
+		if !s.explore() {
+			return
+		}
+
+		if node == src {
+			if !s.found() {
+				return
+			}
+
+			sequence := make([]int, len(reversed))
+
+			for i, e := range reversed {
+				sequence[len(reversed)-1-i] = e
+			}
+
+			sequences = append(sequences, sequence)
+
+			return
+		}
+
+		onPath[node] = true
+
+		for _, e := range preds[node] {
+			if s.stopped() {
+				break
+			}
+
+			if onPath[idx.from[e]] {
+				continue
+			}
+
+			reversed = append(reversed, e)
+			walk(idx.from[e])
+			reversed = reversed[:len(reversed)-1]
+		}
+
+		onPath[node] = false
+	}
+
+	walk(dst)
+
+	return sequences
+}
@@ -0,0 +1,56 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBuildIndex(t *testing.T) {
+	t.Parallel()
+
+	nodeA := Node{Name: "A"}
+	nodeB := Node{Name: "B"}
+	nodeC := Node{Name: "C"}
+	nodeD := Node{Name: "D"}
+
+	// D only appears in an edge, A has a self-loop and B and C are linked twice
+	graph := Graph{
+		Nodes: []Node{nodeA, nodeB, nodeC},
+		Edges: []Edge{
+			{Nodes: [2]Node{nodeB, nodeC}, Weight: 1},
+			{Nodes: [2]Node{nodeA, nodeB}, Weight: 2},
+			{Nodes: [2]Node{nodeA, nodeA}, Weight: 3},
+			{Nodes: [2]Node{nodeB, nodeC}, Weight: 4},
+			{Nodes: [2]Node{nodeC, nodeD}, Weight: 5},
+		},
+	}
+
+	idx := graph.buildIndex()
+
+	if len(idx.nodes) != 4 {
+		t.Errorf("buildIndex did not work. Got %v nodes instead of %v", len(idx.nodes), 4)
+	}
+
+	if want := []float64{1, 2, 3, 4, 5}; !reflect.DeepEqual(idx.weights, want) {
+		t.Errorf("buildIndex did not work. Got weights %v instead of %v", idx.weights, want)
+	}
+
+	var (
+		out = [][]int{{1, 2}, {0, 3}, {4}, {}}
+		in  = [][]int{{2}, {1}, {0, 3}, {4}}
+	)
+
+	for v := range idx.nodes {
+		if got := idx.out(v); !reflect.DeepEqual(append([]int{}, got...), out[v]) {
+			t.Errorf("buildIndex did not work. Got outgoing edges %v of %v instead of %v", got, idx.nodes[v], out[v])
+		}
+
+		if got := idx.in(v); !reflect.DeepEqual(append([]int{}, got...), in[v]) {
+			t.Errorf("buildIndex did not work. Got incoming edges %v of %v instead of %v", got, idx.nodes[v], in[v])
+		}
+	}
+
+	if idx.hasNegativeWeight() {
+		t.Errorf("hasNegativeWeight did not work. Got true instead of false")
+	}
+}
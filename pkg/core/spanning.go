@@ -0,0 +1,342 @@
+package core
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+)
+
+// SpanningTree represents a spanning tree, forest or arborescence of a graph.
+type SpanningTree struct {
+	Subgraph Graph   `json:"subgraph"`
+	Weight   float64 `json:"weight"` // sum weight of edges
+}
+
+// spanningTree builds the SpanningTree of g made of the given nodes and edges.
+func (g *Graph) spanningTree(idx *index, nodes []int, edges []int) SpanningTree {
+	var tree SpanningTree
+
+	tree.Subgraph.Nodes = make([]Node, 0, len(nodes))
+	tree.Subgraph.Edges = make([]Edge, 0, len(edges))
+
+	for _, n := range nodes {
+		tree.Subgraph.Nodes = append(tree.Subgraph.Nodes, idx.nodes[n])
+	}
+
+	for _, e := range edges {
+		tree.Subgraph.Edges = append(tree.Subgraph.Edges, g.Edges[e])
+		tree.Weight += idx.weights[e]
+	}
+
+	return tree
+}
+
+// allNodes returns the id of every node of idx.
+func (idx *index) allNodes() []int {
+	nodes := make([]int, len(idx.nodes))
+
+	for i := range nodes {
+		nodes[i] = i
+	}
+
+	return nodes
+}
+
+// unionFind is a disjoint-set forest over node ids.
+type unionFind []int
+
+// newUnionFind returns a unionFind of n singleton sets.
+func newUnionFind(n int) unionFind {
+	sets := make(unionFind, n)
+
+	for i := range sets {
+		sets[i] = i
+	}
+
+	return sets
+}
+
+// find returns the representative of the set containing x.
+func (u unionFind) find(x int) int {
+	for u[x] != x {
+		u[x] = u[u[x]]
+		x = u[x]
+	}
+
+	return x
+}
+
+// union merges the sets containing x and y.
+// It returns false if they were already the same set.
+func (u unionFind) union(x, y int) bool {
+	x, y = u.find(x), u.find(y)
+	if x == y {
+		return false
+	}
+
+	u[x] = y
+
+	return true
+}
+
+// MinimumSpanningForestKruskal finds a minimum spanning forest of g using Kruskal's algorithm.
+// Edges are treated as undirected, but keep their direction in the result.
+// It returns a minimum spanning tree for every connected component of g.
+func (g *Graph) MinimumSpanningForestKruskal() SpanningTree {
+	var (
+		idx    = g.buildIndex()
+		sets   = newUnionFind(len(idx.nodes))
+		sorted = make([]int, len(g.Edges))
+		chosen []int
+	)
+
+	for e := range sorted {
+		sorted[e] = e
+	}
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return idx.weights[sorted[i]] < idx.weights[sorted[j]]
+	})
+
+	for _, e := range sorted {
+		if sets.union(idx.from[e], idx.to[e]) {
+			chosen = append(chosen, e)
+		}
+	}
+
+	return g.spanningTree(idx, idx.allNodes(), chosen)
+}
+
+// MinimumSpanningForestPrim finds a minimum spanning forest of g using Prim's algorithm.
+// Edges are treated as undirected, but keep their direction in the result.
+// It returns a minimum spanning tree for every connected component of g.
+func (g *Graph) MinimumSpanningForestPrim() SpanningTree {
+	var (
+		idx     = g.buildIndex()
+		keys    = make([]float64, len(idx.nodes)) // lowest weight connecting the node to the tree
+		parents = make([]int, len(idx.nodes))     // edge connecting the node to the tree
+		inTree  = make([]bool, len(idx.nodes))
+		chosen  []int
+	)
+
+	for i := range keys {
+		keys[i] = math.Inf(1)
+		parents[i] = -1
+	}
+
+	// Growing a tree from every node not spanned yet
+	for start := range idx.nodes {
+		if inTree[start] {
+			continue
+		}
+
+		keys[start] = 0
+		queue := &distanceHeap{{node: start}}
+
+		for queue.Len() > 0 {
+			item := heap.Pop(queue).(distanceItem)
+
+			if inTree[item.node] {
+				continue
+			}
+
+			inTree[item.node] = true
+
+			if parents[item.node] != -1 {
+				chosen = append(chosen, parents[item.node])
+			}
+
+			for _, edges := range [][]int{idx.out(item.node), idx.in(item.node)} {
+				for _, e := range edges {
+					next := idx.to[e]
+					if next == item.node {
+						next = idx.from[e]
+					}
+
+					if !inTree[next] && idx.weights[e] < keys[next] {
+						keys[next] = idx.weights[e]
+						parents[next] = e
+
+						heap.Push(queue, distanceItem{node: next, distance: keys[next]})
+					}
+				}
+			}
+		}
+	}
+
+	return g.spanningTree(idx, idx.allNodes(), chosen)
+}
+
+// arc is a directed edge between node ids used by Chu-Liu/Edmonds' algorithm.
+type arc struct {
+	from, to int
+	weight   float64
+}
+
+// edmonds finds a minimum cost arborescence rooted at root over n nodes using
+// Chu-Liu/Edmonds' algorithm. Every node must be reachable from root.
+// It returns the indices of the chosen arcs.
+func edmonds(n, root int, arcs []arc) []int {
+	// Choosing the lowest weighted incoming arc of every node
+	cheapest := make([]int, n)
+
+	for v := range cheapest {
+		cheapest[v] = -1
+	}
+
+	for i, a := range arcs {
+		if a.from != a.to && a.to != root && (cheapest[a.to] == -1 || a.weight < arcs[cheapest[a.to]].weight) {
+			cheapest[a.to] = i
+		}
+	}
+
+	// Looking for cycles among the chosen arcs
+	var (
+		components = make([]int, n) // id of the contracted node
+		visited    = make([]int, n) // last walk that visited the node
+		cycles     int
+	)
+
+	for v := range components {
+		components[v] = -1
+		visited[v] = -1
+	}
+
+	for start := range components {
+		v := start
+
+		for v != root && visited[v] == -1 {
+			visited[v] = start
+			v = arcs[cheapest[v]].from
+		}
+
+		// Closing a cycle found in the current walk
+		if v != root && visited[v] == start && components[v] == -1 {
+			for u := v; components[u] == -1; u = arcs[cheapest[u]].from {
+				components[u] = cycles
+			}
+
+			cycles++
+		}
+	}
+
+	if cycles == 0 {
+		chosen := make([]int, 0, n-1)
+
+		for v, a := range cheapest {
+			if v != root {
+				chosen = append(chosen, a)
+			}
+		}
+
+		return chosen
+	}
+
+	// Contracting every cycle to a single node
+	contracted := cycles
+
+	for v := range components {
+		if components[v] == -1 {
+			components[v] = contracted
+			contracted++
+		}
+	}
+
+	var (
+		contractedArcs []arc
+		originals      []int // original arc index by contracted arc index
+	)
+
+	for i, a := range arcs {
+		u, v := components[a.from], components[a.to]
+		if u == v {
+			continue
+		}
+
+		weight := a.weight
+
+		// Entering a cycle replaces the cycle arc of the entered node
+		if v < cycles {
+			weight -= arcs[cheapest[a.to]].weight
+		}
+
+		contractedArcs = append(contractedArcs, arc{from: u, to: v, weight: weight})
+		originals = append(originals, i)
+	}
+
+	// Expanding the cycles of the contracted arborescence
+	var (
+		chosen  []int
+		entered = make([]bool, n)
+	)
+
+	for _, i := range edmonds(contracted, components[root], contractedArcs) {
+		chosen = append(chosen, originals[i])
+		entered[arcs[originals[i]].to] = true
+	}
+
+	for v := range components {
+		if components[v] < cycles && !entered[v] {
+			chosen = append(chosen, cheapest[v])
+		}
+	}
+
+	return chosen
+}
+
+// MinimumArborescence finds a minimum cost arborescence of g rooted at root
+// using Chu-Liu/Edmonds' algorithm, treating edges as directed.
+// It spans the nodes reachable from root.
+func (g *Graph) MinimumArborescence(root Node) SpanningTree {
+	idx := g.buildIndex()
+
+	src, ok := idx.id(root)
+	if !ok {
+		return g.spanningTree(idx, nil, nil)
+	}
+
+	// Collecting the nodes reachable from root
+	var (
+		ids       = make([]int, len(idx.nodes)) // node id -> reachable node id
+		reachable = []int{src}
+	)
+
+	for i := range ids {
+		ids[i] = -1
+	}
+
+	ids[src] = 0
+
+	for i := 0; i < len(reachable); i++ {
+		for _, e := range idx.out(reachable[i]) {
+			if ids[idx.to[e]] == -1 {
+				ids[idx.to[e]] = len(reachable)
+				reachable = append(reachable, idx.to[e])
+			}
+		}
+	}
+
+	var (
+		arcs      []arc
+		originals []int // edge index by arc index
+	)
+
+	for e := range g.Edges {
+		if ids[idx.from[e]] != -1 {
+			arcs = append(arcs, arc{from: ids[idx.from[e]], to: ids[idx.to[e]], weight: idx.weights[e]})
+			originals = append(originals, e)
+		}
+	}
+
+	chosen := edmonds(len(reachable), 0, arcs)
+
+	edges := make([]int, len(chosen))
+
+	for i, a := range chosen {
+		edges[i] = originals[a]
+	}
+
+	sort.Ints(edges)
+
+	return g.spanningTree(idx, reachable, edges)
+}
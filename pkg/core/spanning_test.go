@@ -0,0 +1,229 @@
+package core
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// randomGraph returns a random graph with n nodes, each possible edge present with probability p,
+// and integer weights between minWeight and maxWeight.
+func randomGraph(r *rand.Rand, n int, p float64, minWeight, maxWeight int) Graph {
+	var graph Graph
+
+	for i := 0; i < n; i++ {
+		graph.Nodes = append(graph.Nodes, Node{Name: fmt.Sprint(i)})
+	}
+
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			if i != j && r.Float64() < p {
+				graph.Edges = append(graph.Edges, Edge{
+					Nodes:  [2]Node{graph.Nodes[i], graph.Nodes[j]},
+					Weight: float64(minWeight + r.Intn(maxWeight-minWeight+1)),
+				})
+			}
+		}
+	}
+
+	return graph
+}
+
+// bruteForceArborescence returns the weight of the minimum arborescence of g rooted at root,
+// trying every choice of incoming edges for the nodes reachable from root.
+func bruteForceArborescence(g *Graph, root Node) float64 {
+	var (
+		idx       = g.buildIndex()
+		src, _    = idx.id(root)
+		reachable = []int{src}
+		seen      = map[int]bool{src: true}
+		best      = math.Inf(1)
+		chosen    = make(map[int]int)
+		try       func(i int)
+	)
+
+	for i := 0; i < len(reachable); i++ {
+		for _, e := range idx.out(reachable[i]) {
+			if !seen[idx.to[e]] {
+				seen[idx.to[e]] = true
+				reachable = append(reachable, idx.to[e])
+			}
+		}
+	}
+
+	try = func(i int) {
+		if i == len(reachable) {
+			var weight float64
+
+			// Every node has to lead back to the root
+			for _, v := range reachable[1:] {
+				node := v
+
+				for steps := 0; node != src; steps++ {
+					if steps > len(reachable) {
+						return
+					}
+
+					node = idx.from[chosen[node]]
+				}
+
+				weight += g.Edges[chosen[v]].Weight
+			}
+
+			best = math.Min(best, weight)
+
+			return
+		}
+
+		for _, e := range idx.in(reachable[i]) {
+			if seen[idx.from[e]] {
+				chosen[reachable[i]] = e
+				try(i + 1)
+			}
+		}
+	}
+
+	try(1)
+
+	return best
+}
+
+func TestMinimumSpanningForest(t *testing.T) {
+	t.Parallel()
+
+	nodeA := Node{Name: "A"}
+	nodeB := Node{Name: "B"}
+	nodeC := Node{Name: "C"}
+	nodeD := Node{Name: "D"}
+	nodeE := Node{Name: "E"}
+	nodeF := Node{Name: "F"}
+	nodeG := Node{Name: "G"}
+
+	graph := Graph{
+		Nodes: []Node{nodeA, nodeB, nodeC, nodeD, nodeE, nodeF, nodeG},
+		Edges: []Edge{
+			{Nodes: [2]Node{nodeA, nodeB}, Weight: 5},
+			{Nodes: [2]Node{nodeB, nodeC}, Weight: 4},
+			{Nodes: [2]Node{nodeC, nodeD}, Weight: 8},
+			{Nodes: [2]Node{nodeD, nodeC}, Weight: 8},
+			{Nodes: [2]Node{nodeD, nodeE}, Weight: 6},
+			{Nodes: [2]Node{nodeA, nodeD}, Weight: 5},
+			{Nodes: [2]Node{nodeC, nodeE}, Weight: 2},
+			{Nodes: [2]Node{nodeE, nodeB}, Weight: 3},
+			{Nodes: [2]Node{nodeA, nodeE}, Weight: 7},
+			{Nodes: [2]Node{nodeG, nodeF}, Weight: 1},
+		},
+	}
+
+	for _, forest := range []SpanningTree{graph.MinimumSpanningForestKruskal(), graph.MinimumSpanningForestPrim()} {
+		if forest.Weight != 16 {
+			t.Errorf("MinimumSpanningForest did not work. Got %v instead of %v", forest.Weight, 16)
+		}
+
+		if len(forest.Subgraph.Nodes) != 7 {
+			t.Errorf("MinimumSpanningForest did not work. Got %v instead of %v", len(forest.Subgraph.Nodes), 7)
+		}
+
+		// Two components of 5 and 2 nodes
+		if len(forest.Subgraph.Edges) != 5 {
+			t.Errorf("MinimumSpanningForest did not work. Got %v instead of %v", len(forest.Subgraph.Edges), 5)
+		}
+	}
+
+	r := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 50; i++ {
+		graph := randomGraph(r, 8, 0.3, 1, 10)
+
+		kruskal := graph.MinimumSpanningForestKruskal()
+		prim := graph.MinimumSpanningForestPrim()
+
+		if kruskal.Weight != prim.Weight {
+			t.Errorf("MinimumSpanningForest did not work. Got %v instead of %v", prim.Weight, kruskal.Weight)
+		}
+	}
+}
+
+func TestMinimumArborescence(t *testing.T) {
+	t.Parallel()
+
+	nodeA := Node{Name: "A"}
+	nodeB := Node{Name: "B"}
+	nodeC := Node{Name: "C"}
+	nodeD := Node{Name: "D"}
+
+	edgeAB := Edge{
+		Nodes:  [2]Node{nodeA, nodeB},
+		Weight: 10,
+	}
+
+	edgeAC := Edge{
+		Nodes:  [2]Node{nodeA, nodeC},
+		Weight: 2,
+	}
+
+	edgeCB := Edge{
+		Nodes:  [2]Node{nodeC, nodeB},
+		Weight: 3,
+	}
+
+	edgeBD := Edge{
+		Nodes:  [2]Node{nodeB, nodeD},
+		Weight: 1,
+	}
+
+	edgeDC := Edge{
+		Nodes:  [2]Node{nodeD, nodeC},
+		Weight: 1,
+	}
+
+	graph := Graph{
+		Nodes: []Node{nodeA, nodeB, nodeC, nodeD},
+		Edges: []Edge{edgeAB, edgeAC, edgeCB, edgeBD, edgeDC},
+	}
+
+	// Case 1: the cycle B->D->C->B is broken at its cheapest entry
+	tree := graph.MinimumArborescence(nodeA)
+
+	edges := []Edge{edgeAC, edgeCB, edgeBD}
+
+	if tree.Weight != 6 {
+		t.Errorf("MinimumArborescence did not work. Got %v instead of %v", tree.Weight, 6)
+	}
+
+	if len(tree.Subgraph.Edges) != len(edges) {
+		t.Errorf("MinimumArborescence did not work. Got %v instead of %v", len(tree.Subgraph.Edges), len(edges))
+	} else {
+		for i, tr := range tree.Subgraph.Edges {
+			if !tr.Equals(&edges[i]) {
+				t.Errorf("MinimumArborescence did not work. Got %v instead of %v", tr, edges[i])
+			}
+		}
+	}
+
+	// Case 2: only reachable nodes are spanned
+	tree = graph.MinimumArborescence(nodeB)
+
+	if len(tree.Subgraph.Nodes) != 3 {
+		t.Errorf("MinimumArborescence did not work. Got %v instead of %v", len(tree.Subgraph.Nodes), 3)
+	}
+
+	// Case 3: same weight as brute force on random graphs
+	r := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 50; i++ {
+		graph := randomGraph(r, 6, 0.4, -3, 10)
+
+		tree := graph.MinimumArborescence(graph.Nodes[0])
+
+		if expected := bruteForceArborescence(&graph, graph.Nodes[0]); tree.Weight != expected {
+			t.Errorf("MinimumArborescence did not work. Got %v instead of %v", tree.Weight, expected)
+		}
+
+		if len(tree.Subgraph.Edges) != len(tree.Subgraph.Nodes)-1 {
+			t.Errorf("MinimumArborescence did not work. Got %v edges for %v nodes",
+				len(tree.Subgraph.Edges), len(tree.Subgraph.Nodes))
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package core
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Rule represents an invariant of graphs.
+type Rule string
+
+// Invariants of graphs checked by Validate.
+const (
+	RuleSelfLoop        Rule = "self_loop"         // the nodes of an edge cannot be identical
+	RuleDuplicateEdge   Rule = "duplicate_edge"    // directed edges are unique, whatever their weights
+	RuleDanglingNode    Rule = "dangling_node"     // the nodes of edges are listed in Nodes
+	RuleDuplicateNode   Rule = "duplicate_node"    // node names are unique
+	RuleNonFiniteWeight Rule = "non_finite_weight" // weights are neither NaN nor infinite
+)
+
+// ValidationMode tells which violations of the invariants are errors.
+type ValidationMode string
+
+// Validation modes.
+const (
+	// ValidationStrict makes every violation an error.
+	ValidationStrict ValidationMode = "strict"
+	// ValidationLenient only makes NaN and infinite weights errors, which no algorithm can handle.
+	// Nodes only appearing in edges are nodes of the graph, and the first of duplicate nodes is used.
+	ValidationLenient ValidationMode = "lenient"
+)
+
+// Violation represents a violation of an invariant of a graph.
+type Violation struct {
+	Rule    Rule   `json:"rule"`
+	Message string `json:"message"`
+	Node    string `json:"node,omitempty"`  // name of the node violating the rule, if any
+	Edges   []int  `json:"edges,omitempty"` // indices of the edges violating the rule, if any
+}
+
+// ValidationError is returned by Validate with every violation of the invariants of a graph.
+type ValidationError struct {
+	Violations []Violation
+}
+
+// Error returns the messages of the violations.
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Violations))
+
+	for i, v := range e.Violations {
+		messages[i] = v.Message
+	}
+
+	return "invalid graph: " + strings.Join(messages, "; ")
+}
+
+// ParseValidationMode returns the validation mode named s.
+// It returns an *InvalidParameterError if s is not the name of a mode.
+func ParseValidationMode(s string) (ValidationMode, error) {
+	switch mode := ValidationMode(s); mode {
+	case ValidationStrict, ValidationLenient:
+		return mode, nil
+	default:
+		return "", &InvalidParameterError{Field: "validation", Message: "has to be strict or lenient"}
+	}
+}
+
+// edgeKey identifies the directed edges from a node to another.
+type edgeKey struct {
+	from, to string
+}
+
+// Violations returns every violation of the invariants of g,
+// ordered by the node or edge where they are first found.
+func (g *Graph) Violations() []Violation {
+	var (
+		violations []Violation
+		nodes      = make(map[string]bool, len(g.Nodes))
+		duplicates = make(map[string]bool)
+		dangling   = make(map[string]int) // index of the violation by node name
+		edges      = make(map[edgeKey]int, len(g.Edges))
+	)
+
+	for _, n := range g.Nodes {
+		if !nodes[n.Name] {
+			nodes[n.Name] = true
+			continue
+		}
+
+		if !duplicates[n.Name] {
+			duplicates[n.Name] = true
+
+			violations = append(violations, Violation{
+				Rule:    RuleDuplicateNode,
+				Message: fmt.Sprintf("node %q is listed more than once", n.Name),
+				Node:    n.Name,
+			})
+		}
+	}
+
+	for i, e := range g.Edges {
+		if e.Nodes[0].Equals(e.Nodes[1]) {
+			violations = append(violations, Violation{
+				Rule:    RuleSelfLoop,
+				Message: fmt.Sprintf("edge %v is a loop on node %q", i, e.Nodes[0].Name),
+				Node:    e.Nodes[0].Name,
+				Edges:   []int{i},
+			})
+		}
+
+		key := edgeKey{from: e.Nodes[0].Name, to: e.Nodes[1].Name}
+
+		if j, ok := edges[key]; ok {
+			violations = append(violations, Violation{
+				Rule:    RuleDuplicateEdge,
+				Message: fmt.Sprintf("edge %v from %q to %q duplicates edge %v", i, e.Nodes[0].Name, e.Nodes[1].Name, j),
+				Edges:   []int{j, i},
+			})
+		} else {
+			edges[key] = i
+		}
+
+		for _, n := range e.Nodes {
+			if nodes[n.Name] {
+				continue
+			}
+
+			if v, ok := dangling[n.Name]; ok {
+				if indices := violations[v].Edges; indices[len(indices)-1] != i {
+					violations[v].Edges = append(indices, i)
+				}
+
+				continue
+			}
+
+			dangling[n.Name] = len(violations)
+
+			violations = append(violations, Violation{
+				Rule:    RuleDanglingNode,
+				Message: fmt.Sprintf("node %q of edge %v is not listed in the nodes", n.Name, i),
+				Node:    n.Name,
+				Edges:   []int{i},
+			})
+		}
+
+		if math.IsNaN(e.Weight) || math.IsInf(e.Weight, 0) {
+			violations = append(violations, Violation{
+				Rule:    RuleNonFiniteWeight,
+				Message: fmt.Sprintf("edge %v from %q to %q has weight %v", i, e.Nodes[0].Name, e.Nodes[1].Name, e.Weight),
+				Edges:   []int{i},
+			})
+		}
+	}
+
+	return violations
+}
+
+// Validate checks the invariants of g.
+// It returns a *ValidationError with every violation which is an error in mode, if there is any.
+func (g *Graph) Validate(mode ValidationMode) error {
+	var errs []Violation
+
+	for _, v := range g.Violations() {
+		if mode == ValidationStrict || v.Rule == RuleNonFiniteWeight {
+			errs = append(errs, v)
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return &ValidationError{Violations: errs}
+}
@@ -0,0 +1,84 @@
+package core
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	nodeA := Node{Name: "A"}
+	nodeB := Node{Name: "B"}
+	nodeC := Node{Name: "C"}
+
+	graph := Graph{
+		Nodes: []Node{nodeA, nodeB, nodeA},
+		Edges: []Edge{
+			{Nodes: [2]Node{nodeA, nodeB}, Weight: 1},
+			{Nodes: [2]Node{nodeB, nodeB}, Weight: 1},
+			{Nodes: [2]Node{nodeA, nodeB}, Weight: 1},
+			{Nodes: [2]Node{nodeB, nodeC}, Weight: math.Inf(1)},
+			{Nodes: [2]Node{nodeC, nodeA}, Weight: 2},
+		},
+	}
+
+	// Every violation is reported at once
+	expected := []Violation{
+		{Rule: RuleDuplicateNode, Node: "A"},
+		{Rule: RuleSelfLoop, Node: "B", Edges: []int{1}},
+		{Rule: RuleDuplicateEdge, Edges: []int{0, 2}},
+		{Rule: RuleDanglingNode, Node: "C", Edges: []int{3, 4}},
+		{Rule: RuleNonFiniteWeight, Edges: []int{3}},
+	}
+
+	violations := graph.Violations()
+
+	for i := range violations {
+		violations[i].Message = ""
+	}
+
+	if !reflect.DeepEqual(violations, expected) {
+		t.Errorf("Violations did not work. Got %v instead of %v", violations, expected)
+	}
+
+	var validationErr *ValidationError
+
+	if err := graph.Validate(ValidationStrict); !errors.As(err, &validationErr) || len(validationErr.Violations) != len(expected) {
+		t.Errorf("Validate did not work. Got error %v instead of %v violations", err, len(expected))
+	}
+
+	// Only non-finite weights are errors in lenient mode
+	if err := graph.Validate(ValidationLenient); !errors.As(err, &validationErr) || len(validationErr.Violations) != 1 || validationErr.Violations[0].Rule != RuleNonFiniteWeight {
+		t.Errorf("Validate did not work. Got error %v instead of the non-finite weight only", err)
+	}
+
+	graph.Edges[3].Weight = 3
+
+	if err := graph.Validate(ValidationLenient); err != nil {
+		t.Errorf("Validate did not work. Got error %v", err)
+	}
+
+	valid := Graph{
+		Nodes: []Node{nodeA, nodeB},
+		Edges: []Edge{{Nodes: [2]Node{nodeA, nodeB}, Weight: 1}},
+	}
+
+	if err := valid.Validate(ValidationStrict); err != nil {
+		t.Errorf("Validate did not work. Got error %v", err)
+	}
+
+	// Parallel edges are duplicates even if their weights differ
+	valid.Edges = append(valid.Edges, Edge{Nodes: [2]Node{nodeA, nodeB}, Weight: 2})
+
+	if err := valid.Validate(ValidationStrict); !errors.As(err, &validationErr) || len(validationErr.Violations) != 1 ||
+		validationErr.Violations[0].Rule != RuleDuplicateEdge {
+		t.Errorf("Validate did not work. Got error %v instead of a duplicate edge", err)
+	}
+
+	if _, err := ParseValidationMode("loose"); err == nil {
+		t.Errorf("ParseValidationMode did not work. Got no error for an unknown mode")
+	}
+}
@@ -0,0 +1,337 @@
+package core
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// workersKey is the context key of the number of workers of path enumerations.
+type workersKey struct{}
+
+// WithWorkers returns a copy of ctx carrying the number of workers of path enumerations.
+// The paths without edge repetition, with max. steps and with max. weight run with the returned context
+// are searched by up to workers goroutines, each walking on the paths starting with different edges.
+// Paths are found in the same order as by a single worker, which is the default,
+// and searches truncated by the budget of paths (see WithBudget) find the same paths.
+func WithWorkers(ctx context.Context, workers int) context.Context {
+	return context.WithValue(ctx, workersKey{}, workers)
+}
+
+// WorkersFromContext returns the number of workers of path enumerations carried by ctx, at least 1.
+func WorkersFromContext(ctx context.Context) int {
+	if workers, ok := ctx.Value(workersKey{}).(int); ok && workers > 1 {
+		return workers
+	}
+
+	return 1
+}
+
+// walkFrame is a node on the currently walked path.
+type walkFrame struct {
+	node   int
+	next   int     // position of the next edge to walk on in the outgoing edges of node
+	weight float64 // weight of the path until node
+}
+
+// pathWalker enumerates the paths of a graph ending with dst depth first, without recursion.
+// Nodes are not repeated within a path, dst cannot be passed through.
+// A walker is used by one goroutine, and its buffers are reused from walk to walk.
+type pathWalker struct {
+	g         *Graph
+	idx       *index
+	search    *searchState
+	dst       int
+	maxLength int     // max. number of nodes a path is walked further from
+	maxWeight float64 // max. weight of paths, -1 if unlimited
+	found     func(edges []int, weight float64) bool
+
+	onPath []bool      // nodes on the path by id
+	frames []walkFrame // nodes on the path
+	edges  []int       // edges of the path
+}
+
+// newPathWalker returns a walker of the paths of g ending with dst.
+// found is given the edges and the weight of every path, it returns false if the walk has to stop.
+// The edges are only valid during the call.
+func (g *Graph) newPathWalker(idx *index, search *searchState, dst, maxLength int, maxWeight float64,
+	found func(edges []int, weight float64) bool) *pathWalker {
+	return &pathWalker{
+		g:         g,
+		idx:       idx,
+		search:    search,
+		dst:       dst,
+		maxLength: maxLength,
+		maxWeight: maxWeight,
+		found:     found,
+		onPath:    make([]bool, len(idx.nodes)),
+	}
+}
+
+// within reports whether weight is within the max. weight of paths.
+func (w *pathWalker) within(weight float64) bool {
+	return w.maxWeight == -1 || weight <= w.maxWeight
+}
+
+// visit explores node, reached with a path of weight: it gives the paths ending with an edge from node to dst to found.
+// It returns true if the path is walked further from node, pushing a frame of it.
+func (w *pathWalker) visit(node int, weight float64) bool {
+	if !w.search.explore() {
+		return false
+	}
+
+	for _, e := range w.idx.out(node) {
+		if w.idx.to[e] != w.dst || !w.within(weight+w.idx.weights[e]) {
+			continue
+		}
+
+		if !w.found(append(w.edges, e), weight+w.idx.weights[e]) {
+			w.search.stop()
+			return false
+		}
+	}
+
+	if !w.within(weight) || len(w.edges)+1 >= w.maxLength {
+		return false
+	}
+
+	w.onPath[node] = true
+	w.frames = append(w.frames, walkFrame{node: node, weight: weight})
+
+	return true
+}
+
+// walk walks on every path from the frames pushed by visit, until every frame is left or the search stops.
+// Leaving a frame removes the edge leading to it from the path.
+func (w *pathWalker) walk() {
+	for len(w.frames) > 0 {
+		top := &w.frames[len(w.frames)-1]
+
+		if top.next == len(w.idx.out(top.node)) || w.search.stopped() {
+			// Every edge from the node is walked on: leaving it
+			w.onPath[top.node] = false
+			w.frames = w.frames[:len(w.frames)-1]
+
+			if len(w.edges) > 0 {
+				w.edges = w.edges[:len(w.edges)-1]
+			}
+
+			continue
+		}
+
+		e := w.idx.out(top.node)[top.next]
+		top.next++
+
+		if to := w.idx.to[e]; to != w.dst && !w.onPath[to] {
+			w.edges = append(w.edges, e)
+
+			if !w.visit(to, top.weight+w.idx.weights[e]) {
+				w.edges = w.edges[:len(w.edges)-1]
+			}
+		}
+	}
+}
+
+// walkFrom walks on every path starting with the edge e from src, which is on the path.
+func (w *pathWalker) walkFrom(src, e int) {
+	w.onPath[src] = true
+	w.edges = append(w.edges[:0], e)
+
+	if !w.visit(w.idx.to[e], w.idx.weights[e]) {
+		w.edges = w.edges[:0]
+	}
+
+	w.walk()
+
+	w.onPath[src] = false
+}
+
+// orderedPaths gives the paths found by concurrent walks to emit in the order of the walks,
+// each walk being a task. The paths of the first unfinished task are given at once,
+// the paths of the following ones are kept until every previous task is finished.
+// Paths are counted against the budget of paths of the search as they are given,
+// so that the paths given are the ones a single walk would give within the budget.
+type orderedPaths struct {
+	mu        sync.Mutex
+	search    *searchState
+	emit      PathFunc
+	err       error    // error of emit, if any
+	exhausted bool     // true once a path beyond the budget of paths is released
+	current   int      // first unfinished task
+	pending   [][]Path // paths kept by task
+	done      []bool   // finished tasks
+}
+
+// release gives path to emit, unless it is beyond the budget of paths.
+// It returns false if the path is beyond the budget or emit failed.
+func (o *orderedPaths) release(path Path) bool {
+	if !o.search.found() {
+		o.exhausted = true
+		return false
+	}
+
+	o.err = o.emit(path)
+
+	return o.err == nil
+}
+
+// add gives path, found by task, to emit in order.
+// It returns false if emit failed or the budget of paths is exhausted.
+func (o *orderedPaths) add(task int, path Path) bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.err != nil || o.exhausted {
+		return false
+	}
+
+	if task != o.current {
+		// Paths after the first MaxPaths+1 of a task are beyond the budget whatever the previous tasks give
+		if maxPaths := o.search.budget.MaxPaths; maxPaths == 0 || len(o.pending[task]) <= maxPaths {
+			o.pending[task] = append(o.pending[task], path)
+		}
+
+		return true
+	}
+
+	return o.release(path)
+}
+
+// finish marks task finished, giving the paths kept for the following tasks to emit.
+// It returns false if emit failed or the budget of paths is exhausted.
+func (o *orderedPaths) finish(task int) bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.done[task] = true
+
+	for o.err == nil && !o.exhausted && o.current < len(o.done) {
+		for _, path := range o.pending[o.current] {
+			if !o.release(path) {
+				break
+			}
+		}
+
+		o.pending[o.current] = nil
+
+		if !o.done[o.current] {
+			break
+		}
+
+		o.current++
+	}
+
+	return o.err == nil && !o.exhausted
+}
+
+// streamPaths walks from node1 to node2 without node repetition
+// and gives the paths accepted by filter to emit, in depth first order.
+// Paths are walked on further from their first maxLength-1 nodes only,
+// and while they are not heavier than maxWeight unless it is -1.
+// The walk is split among the workers carried by ctx by the edges from node1 (see WithWorkers).
+// It returns the error of emit if it stopped the search, otherwise the result of the search.
+func (g *Graph) streamPaths(ctx context.Context, idx *index, node1, node2 Node, maxLength int, maxWeight float64,
+	filter func(edges []int, weight float64) bool, emit PathFunc) error {
+	var (
+		search  = newSearch(ctx)
+		emitErr error
+	)
+
+	src, ok1 := idx.id(node1)
+	dst, ok2 := idx.id(node2)
+
+	if !ok1 || !ok2 {
+		return nil
+	}
+
+	// found returns the function of walkers giving the paths accepted by filter to give
+	found := func(give func(edges []int) bool) func(edges []int, weight float64) bool {
+		return func(edges []int, weight float64) bool {
+			return !filter(edges, weight) || give(edges)
+		}
+	}
+
+	// A single walker counts the paths against the budget as it finds them
+	walker := g.newPathWalker(idx, search, dst, maxLength, maxWeight, found(func(edges []int) bool {
+		if !search.found() {
+			return false
+		}
+
+		emitErr = emit(g.pathFromEdges(edges))
+
+		return emitErr == nil
+	}))
+
+	if walker.visit(src, 0) {
+		if workers := WorkersFromContext(ctx); workers > 1 {
+			// Parallel walkers leave counting to the ordered paths, so that the paths within the budget are the same
+			emitErr = g.walkParallel(idx, search, src, dst, workers, func(task *int, ordered *orderedPaths) *pathWalker {
+				return g.newPathWalker(idx, search.fork(), dst, maxLength, maxWeight, found(func(edges []int) bool {
+					return ordered.add(*task, g.pathFromEdges(edges))
+				}))
+			}, emit)
+		} else {
+			walker.walk()
+		}
+	}
+
+	if emitErr != nil {
+		return emitErr
+	}
+
+	return search.result()
+}
+
+// walkParallel walks on the paths from src, except the ones ending with dst at once, with workers goroutines.
+// Each walk on the paths starting with an edge from src is a task, the paths are given to emit in the order of the tasks.
+// newWalker returns the walker of a worker, giving the paths of the task it points to to ordered.
+// It returns the error of emit if it stopped the search.
+func (g *Graph) walkParallel(idx *index, search *searchState, src, dst, workers int,
+	newWalker func(task *int, ordered *orderedPaths) *pathWalker, emit PathFunc) error {
+	var (
+		tasks []int // first edges
+		next  int64 // next task, accessed atomically
+		wG    sync.WaitGroup
+	)
+
+	for _, e := range idx.out(src) {
+		if to := idx.to[e]; to != dst && to != src {
+			tasks = append(tasks, e)
+		}
+	}
+
+	ordered := &orderedPaths{
+		search:  search,
+		emit:    emit,
+		pending: make([][]Path, len(tasks)),
+		done:    make([]bool, len(tasks)),
+	}
+
+	if workers > len(tasks) {
+		workers = len(tasks)
+	}
+
+	for i := 0; i < workers; i++ {
+		wG.Add(1)
+
+		go func() {
+			defer wG.Done()
+
+			var task int
+
+			walker := newWalker(&task, ordered)
+
+			for task = int(atomic.AddInt64(&next, 1) - 1); task < len(tasks); task = int(atomic.AddInt64(&next, 1) - 1) {
+				walker.walkFrom(src, tasks[task])
+
+				if !ordered.finish(task) {
+					search.stop()
+				}
+			}
+		}()
+	}
+
+	wG.Wait()
+
+	return ordered.err
+}
@@ -0,0 +1,144 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+// countPaths counts the paths of g from node1 to node2 with no more than maxEdges edges
+// and no repeated node, recursively.
+func countPaths(g *Graph, node1, node2 Node, onPath map[string]bool, maxEdges int) int {
+	var count int
+
+	onPath[node1.Name] = true
+
+	for _, e := range g.Edges {
+		switch {
+		case !e.Nodes[0].Equals(node1):
+		case e.Nodes[1].Equals(node2):
+			count++
+		case !onPath[e.Nodes[1].Name] && maxEdges > 1:
+			count += countPaths(g, e.Nodes[1], node2, onPath, maxEdges-1)
+		}
+	}
+
+	onPath[node1.Name] = false
+
+	return count
+}
+
+func TestParallelPaths(t *testing.T) {
+	t.Parallel()
+
+	r := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 5; i++ {
+		var (
+			graph        = randomGraph(r, 60, 0.1, 1, 5)
+			node1, node2 = graph.Nodes[0], graph.Nodes[1]
+			parallel     = WithWorkers(context.Background(), 4)
+		)
+
+		// Case 1: both walks find every path in the same order
+		paths, err := graph.GeneratePathsWithMaxSteps(context.Background(), node1, node2, 7, false)
+		if want := countPaths(&graph, node1, node2, map[string]bool{}, 7); err != nil || len(paths) != want {
+			t.Errorf("GeneratePathsWithMaxSteps did not work. Got %v paths and error %v instead of %v paths", len(paths), err, want)
+		}
+
+		parallelPaths, err := graph.GeneratePathsWithMaxSteps(parallel, node1, node2, 7, false)
+		if err != nil || !reflect.DeepEqual(parallelPaths, paths) {
+			t.Errorf("GeneratePathsWithMaxSteps did not work with workers. Got %v paths and error %v instead of %v paths",
+				len(parallelPaths), err, len(paths))
+		}
+
+		// Case 2: paths are limited across workers, to the paths found by a single walk within the budget
+		for _, maxPaths := range []int{1, 5, 10} {
+			budget := Budget{MaxPaths: maxPaths}
+
+			paths, err = graph.GeneratePathsWithMaxWeight(WithBudget(context.Background(), budget), node1, node2, 15, false)
+			if !errors.Is(err, ErrPathBudgetExhausted) || len(paths) != maxPaths {
+				t.Errorf("GeneratePathsWithMaxWeight did not work. Got %v paths and error %v instead of %v paths and %v",
+					len(paths), err, maxPaths, ErrPathBudgetExhausted)
+			}
+
+			parallelPaths, err = graph.GeneratePathsWithMaxWeight(WithBudget(parallel, budget), node1, node2, 15, false)
+			if !errors.Is(err, ErrPathBudgetExhausted) || !reflect.DeepEqual(parallelPaths, paths) {
+				t.Errorf("GeneratePathsWithMaxWeight did not work with workers. Got %v paths and error %v instead of the %v paths of a single walk and %v",
+					len(parallelPaths), err, len(paths), ErrPathBudgetExhausted)
+			}
+		}
+
+		// Case 3: failing emit stops every worker
+		var (
+			errStop = errors.New("stop")
+			emitted int
+		)
+
+		err = graph.StreamPathsWithMaxSteps(parallel, node1, node2, 6, false, func(path Path) error {
+			if emitted++; emitted == 10 {
+				return errStop
+			}
+
+			return nil
+		})
+
+		if err != errStop || emitted != 10 {
+			t.Errorf("StreamPathsWithMaxSteps did not work with workers. Got %v paths and error %v instead of 10 paths and %v",
+				emitted, err, errStop)
+		}
+	}
+}
+
+func TestParallelPathsBudget(t *testing.T) {
+	t.Parallel()
+
+	var (
+		src    = Node{Name: "src"}
+		dst    = Node{Name: "dst"}
+		nodeA  = Node{Name: "A"}
+		nodeB  = Node{Name: "B"}
+		nodeX  = Node{Name: "X"}
+		clique []Node
+		graph  Graph
+	)
+
+	// The first walk, from A, goes through every path of a clique before reaching dst through X,
+	// the second walk, from B, reaches dst at once
+	for i := 0; i < 10; i++ {
+		clique = append(clique, Node{Name: "clique" + strconv.Itoa(i)})
+	}
+
+	graph.Edges = append(graph.Edges,
+		Edge{Nodes: [2]Node{src, nodeA}, Weight: 1},
+		Edge{Nodes: [2]Node{src, nodeB}, Weight: 1},
+		Edge{Nodes: [2]Node{nodeA, clique[0]}, Weight: 1},
+		Edge{Nodes: [2]Node{nodeA, nodeX}, Weight: 1},
+		Edge{Nodes: [2]Node{nodeX, dst}, Weight: 1},
+		Edge{Nodes: [2]Node{nodeB, dst}, Weight: 1},
+	)
+
+	for _, from := range clique {
+		for _, to := range clique {
+			if from != to {
+				graph.Edges = append(graph.Edges, Edge{Nodes: [2]Node{from, to}, Weight: 1})
+			}
+		}
+	}
+
+	ctx := WithBudget(context.Background(), Budget{MaxPaths: 1})
+
+	paths, err := graph.GeneratePathsWithMaxSteps(ctx, src, dst, 12, false)
+	if !errors.Is(err, ErrPathBudgetExhausted) || len(paths) != 1 || len(paths[0].Subgraph.Edges) != 3 {
+		t.Fatalf("GeneratePathsWithMaxSteps did not work. Got %v and error %v instead of the path through X", paths, err)
+	}
+
+	// The path found first by the second worker is beyond the budget of a single walk
+	parallelPaths, err := graph.GeneratePathsWithMaxSteps(WithWorkers(ctx, 2), src, dst, 12, false)
+	if !errors.Is(err, ErrPathBudgetExhausted) || !reflect.DeepEqual(parallelPaths, paths) {
+		t.Errorf("GeneratePathsWithMaxSteps did not work with workers. Got %v and error %v instead of %v", parallelPaths, err, paths)
+	}
+}
@@ -0,0 +1,157 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"math"
+)
+
+// yenCandidate is a candidate path of Yen's algorithm.
+type yenCandidate struct {
+	edges  []int
+	weight float64
+}
+
+// GenerateKShortestPaths finds the k lowest weighted loopless paths from node1 to node2
+// using Yen's algorithm. If node1 equals node2, the k lowest weighted cycles through node1
+// are searched for.
+// It returns the paths sorted by Path.Weight, lowest first.
+// Edge weights must not be negative, otherwise an *InvalidWeightError is returned.
+// It returns an *UnknownNodeError if node1 or node2 is not a node of g,
+// and an *InvalidParameterError if k is not positive.
+// If ctx is done or its budget is exhausted, it returns the lowest weighted paths found until then
+// with a *TruncatedError. Every path found is an explored state.
+func (g *Graph) GenerateKShortestPaths(ctx context.Context, node1, node2 Node, k int) ([]Path, error) {
+	if k <= 0 {
+		return nil, &InvalidParameterError{Field: "k", Message: "has to be a positive integer"}
+	}
+
+	if err := g.checkNonNegativeWeights(); err != nil {
+		return nil, err
+	}
+
+	var (
+		idx    = g.buildIndex()
+		search = newSearch(ctx)
+	)
+
+	src, ok1 := idx.id(node1)
+	dst, ok2 := idx.id(node2)
+
+	if !ok1 || !ok2 {
+		return nil, g.checkNodes(node1, node2)
+	}
+
+	distances, preds := idx.dijkstra(search, src, idx.weight)
+	if search.stopped() {
+		return []Path{}, search.result()
+	}
+
+	first := idx.lowestWeightSequence(distances, preds, src, dst, idx.weight)
+	if first == nil {
+		return []Path{}, nil
+	}
+
+	if !search.found() {
+		return []Path{}, search.result()
+	}
+
+	var (
+		found      = [][]int{first}
+		candidates []yenCandidate
+		seen       = map[string]bool{fmt.Sprint(first): true}
+	)
+
+	for len(found) < k && search.explore() {
+		previous := found[len(found)-1]
+
+		// Every node of the previous path but the last one is a spur node
+		for i := range previous {
+			var (
+				spur         = idx.from[previous[i]]
+				root         = previous[:i]
+				removedEdges = make(map[int]bool)
+				removedNodes = make([]bool, len(idx.nodes))
+			)
+
+			// Removing the next edge of paths sharing the root path
+			for _, path := range found {
+				if len(path) > i && equalSequences(path[:i], root) {
+					removedEdges[path[i]] = true
+				}
+			}
+
+			// Removing the nodes of the root path, so that the result is loopless
+			for _, e := range root {
+				removedNodes[idx.from[e]] = true
+			}
+
+			weight := func(e int) float64 {
+				if removedEdges[e] || removedNodes[idx.from[e]] || (removedNodes[idx.to[e]] && idx.to[e] != dst) {
+					return math.Inf(1)
+				}
+
+				return idx.weights[e]
+			}
+
+			spurDistances, spurPreds := idx.dijkstra(search, spur, weight)
+			if search.stopped() {
+				return g.pathsFromSequences(found), search.result()
+			}
+
+			spurPath := idx.lowestWeightSequence(spurDistances, spurPreds, spur, dst, weight)
+			if spurPath == nil {
+				continue
+			}
+
+			candidate := append(append([]int{}, root...), spurPath...)
+
+			if key := fmt.Sprint(candidate); !seen[key] {
+				seen[key] = true
+
+				candidates = append(candidates, yenCandidate{
+					edges:  candidate,
+					weight: g.pathFromEdges(candidate).Weight,
+				})
+			}
+		}
+
+		if len(candidates) == 0 {
+			break
+		}
+
+		// Moving the lowest weighted candidate to the found paths
+		best := 0
+
+		for i := range candidates {
+			if candidates[i].weight < candidates[best].weight ||
+				(candidates[i].weight == candidates[best].weight && len(candidates[i].edges) < len(candidates[best].edges)) {
+				best = i
+			}
+		}
+
+		if !search.found() {
+			break
+		}
+
+		found = append(found, candidates[best].edges)
+		candidates = append(candidates[:best], candidates[best+1:]...)
+	}
+
+	return g.pathsFromSequences(found), search.result()
+}
+
+// equalSequences reports whether two edge sequences are identical.
+func equalSequences(s1, s2 []int) bool {
+	if len(s1) != len(s2) {
+		return false
+	}
+
+	for i := range s1 {
+		if s1[i] != s2[i] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,162 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestGenerateKShortestPaths(t *testing.T) {
+	t.Parallel()
+
+	nodeC := Node{Name: "C"}
+	nodeD := Node{Name: "D"}
+	nodeE := Node{Name: "E"}
+	nodeF := Node{Name: "F"}
+	nodeG := Node{Name: "G"}
+	nodeH := Node{Name: "H"}
+
+	edgeCD := Edge{
+		Nodes:  [2]Node{nodeC, nodeD},
+		Weight: 3,
+	}
+
+	edgeCE := Edge{
+		Nodes:  [2]Node{nodeC, nodeE},
+		Weight: 2,
+	}
+
+	edgeDF := Edge{
+		Nodes:  [2]Node{nodeD, nodeF},
+		Weight: 4,
+	}
+
+	edgeED := Edge{
+		Nodes:  [2]Node{nodeE, nodeD},
+		Weight: 1,
+	}
+
+	edgeEF := Edge{
+		Nodes:  [2]Node{nodeE, nodeF},
+		Weight: 2,
+	}
+
+	edgeEG := Edge{
+		Nodes:  [2]Node{nodeE, nodeG},
+		Weight: 3,
+	}
+
+	edgeFG := Edge{
+		Nodes:  [2]Node{nodeF, nodeG},
+		Weight: 2,
+	}
+
+	edgeFH := Edge{
+		Nodes:  [2]Node{nodeF, nodeH},
+		Weight: 1,
+	}
+
+	edgeGH := Edge{
+		Nodes:  [2]Node{nodeG, nodeH},
+		Weight: 2,
+	}
+
+	graph := Graph{
+		Nodes: []Node{nodeC, nodeD, nodeE, nodeF, nodeG, nodeH},
+		Edges: []Edge{edgeCD, edgeCE, edgeDF, edgeED, edgeEF, edgeEG, edgeFG, edgeFH, edgeGH},
+	}
+
+	// Case 1: k lowest weighted paths in order
+	paths, err := graph.GenerateKShortestPaths(context.Background(), nodeC, nodeH, 3)
+
+	if err != nil {
+		t.Errorf("GenerateKShortestPaths did not work. Got error %v", err)
+	}
+
+	weights := []float64{5, 7, 8}
+
+	if len(paths) != len(weights) {
+		t.Errorf("GenerateKShortestPaths did not work. Got %v instead of %v", len(paths), len(weights))
+	} else {
+		for i, p := range paths {
+			if p.Weight != weights[i] {
+				t.Errorf("GenerateKShortestPaths did not work. Got %v instead of %v", p.Weight, weights[i])
+			}
+		}
+
+		subgraph := paths[0].Subgraph
+
+		nodes := []Node{nodeC, nodeE, nodeF, nodeH}
+		edges := []Edge{edgeCE, edgeEF, edgeFH}
+
+		if len(subgraph.Nodes) != len(nodes) {
+			t.Errorf("GenerateKShortestPaths did not work. Got %v instead of %v", len(subgraph.Nodes), len(nodes))
+		} else {
+			for i, n := range subgraph.Nodes {
+				if n != nodes[i] {
+					t.Errorf("GenerateKShortestPaths did not work. Got %v instead of %v", n, nodes[i])
+				}
+			}
+		}
+
+		if len(subgraph.Edges) != len(edges) {
+			t.Errorf("GenerateKShortestPaths did not work. Got %v instead of %v", len(subgraph.Edges), len(edges))
+		} else {
+			for i, tr := range subgraph.Edges {
+				if !tr.Equals(&edges[i]) {
+					t.Errorf("GenerateKShortestPaths did not work. Got %v instead of %v", tr, edges[i])
+				}
+			}
+		}
+	}
+
+	// Case 2: fewer paths than k
+	paths, _ = graph.GenerateKShortestPaths(context.Background(), nodeC, nodeH, 100)
+	allPaths, _ := graph.GeneratePathsWithoutEdgeRepetition(context.Background(), nodeC, nodeH)
+
+	if len(paths) != len(allPaths) {
+		t.Errorf("GenerateKShortestPaths did not work. Got %v instead of %v", len(paths), len(allPaths))
+	}
+
+	for i := 1; i < len(paths); i++ {
+		if paths[i].Weight < paths[i-1].Weight {
+			t.Errorf("GenerateKShortestPaths did not work. Got %v after %v", paths[i].Weight, paths[i-1].Weight)
+		}
+	}
+
+	// Case 3: no path found
+	paths, _ = graph.GenerateKShortestPaths(context.Background(), nodeH, nodeC, 3)
+
+	if len(paths) != 0 {
+		t.Errorf("GenerateKShortestPaths did not work. Got %v instead of %v", len(paths), 0)
+	}
+
+	// Case 4: lowest weighted cycles
+	graph.Edges = append(graph.Edges, Edge{
+		Nodes:  [2]Node{nodeH, nodeC},
+		Weight: 1,
+	})
+
+	paths, _ = graph.GenerateKShortestPaths(context.Background(), nodeC, nodeC, 2)
+
+	weights = []float64{6, 8}
+
+	if len(paths) != len(weights) {
+		t.Errorf("GenerateKShortestPaths did not work. Got %v instead of %v", len(paths), len(weights))
+	} else {
+		for i, p := range paths {
+			if p.Weight != weights[i] {
+				t.Errorf("GenerateKShortestPaths did not work. Got %v instead of %v", p.Weight, weights[i])
+			}
+		}
+	}
+
+	// Case 5: negative weights
+	graph.Edges[0].Weight = -1
+
+	_, err = graph.GenerateKShortestPaths(context.Background(), nodeC, nodeH, 3)
+
+	if !errors.Is(err, ErrNegativeWeight) {
+		t.Errorf("GenerateKShortestPaths did not work. Got error %v instead of %v", err, ErrNegativeWeight)
+	}
+}
@@ -0,0 +1,275 @@
+package dot
+
+import (
+	"strings"
+	"unicode"
+)
+
+// tokenKind represents the kind of a DOT token.
+type tokenKind int
+
+const (
+	tokenEOF         tokenKind = iota
+	tokenID                    // identifier, numeral, quoted or HTML string
+	tokenDirected              // "->"
+	tokenUndirected            // "--"
+	tokenPunctuation           // one of "{}[]=;,:"
+)
+
+// token represents a DOT token.
+type token struct {
+	kind   tokenKind
+	text   string // value of IDs, the token itself otherwise
+	quoted bool   // true, if the ID was a quoted or HTML string, so it cannot be a keyword
+	line   int
+}
+
+// is checks whether t is the punctuation or the unquoted keyword s.
+// Keywords are case-insensitive.
+func (t token) is(s string) bool {
+	switch t.kind {
+	case tokenPunctuation:
+		return t.text == s
+	case tokenID:
+		return !t.quoted && strings.EqualFold(t.text, s)
+	}
+
+	return false
+}
+
+// lexer splits a DOT document into tokens.
+type lexer struct {
+	input []rune
+	pos   int
+	line  int
+}
+
+// newLexer returns a lexer reading input.
+func newLexer(input string) *lexer {
+	return &lexer{
+		input: []rune(input),
+		line:  1,
+	}
+}
+
+// peek returns the rune at offset from the current position, or 0 at the end of input.
+func (l *lexer) peek(offset int) rune {
+	if l.pos+offset >= len(l.input) {
+		return 0
+	}
+
+	return l.input[l.pos+offset]
+}
+
+// advance moves the current position by one rune, counting lines.
+func (l *lexer) advance() {
+	if l.input[l.pos] == '\n' {
+		l.line++
+	}
+
+	l.pos++
+}
+
+// skipSpace skips whitespace and comments.
+func (l *lexer) skipSpace() error {
+	for l.pos < len(l.input) {
+		switch r := l.peek(0); {
+		case unicode.IsSpace(r):
+			l.advance()
+		case r == '#' || r == '/' && l.peek(1) == '/':
+			for l.pos < len(l.input) && l.peek(0) != '\n' {
+				l.advance()
+			}
+		case r == '/' && l.peek(1) == '*':
+			line := l.line
+
+			l.advance()
+			l.advance()
+
+			for l.peek(0) != '*' || l.peek(1) != '/' {
+				if l.pos >= len(l.input) {
+					return syntaxError(line, "unterminated comment")
+				}
+
+				l.advance()
+			}
+
+			l.advance()
+			l.advance()
+		default:
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// next returns the next token of the input.
+func (l *lexer) next() (token, error) {
+	if err := l.skipSpace(); err != nil {
+		return token{}, err
+	}
+
+	t := token{line: l.line}
+
+	switch r := l.peek(0); {
+	case l.pos >= len(l.input):
+		t.kind = tokenEOF
+	case r == '-' && l.peek(1) == '>':
+		l.pos += 2
+		t.kind, t.text = tokenDirected, "->"
+	case r == '-' && l.peek(1) == '-':
+		l.pos += 2
+		t.kind, t.text = tokenUndirected, "--"
+	case strings.ContainsRune("{}[]=;,:", r):
+		l.pos++
+		t.kind, t.text = tokenPunctuation, string(r)
+	case r == '"':
+		return l.quoted()
+	case r == '<':
+		return l.html()
+	case r == '-' || r == '.' || unicode.IsDigit(r):
+		return l.numeral()
+	case r == '_' || unicode.IsLetter(r):
+		start := l.pos
+
+		for r := l.peek(0); r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r); r = l.peek(0) {
+			l.pos++
+		}
+
+		t.kind, t.text = tokenID, string(l.input[start:l.pos])
+	default:
+		return t, syntaxError(l.line, "unexpected character %q", r)
+	}
+
+	return t, nil
+}
+
+// numeral reads a numeral ID: [-]?(.[0-9]+ | [0-9]+(.[0-9]*)?)
+func (l *lexer) numeral() (token, error) {
+	var (
+		start  = l.pos
+		digits int
+		dot    bool
+	)
+
+	if l.peek(0) == '-' {
+		l.pos++
+	}
+
+	for r := l.peek(0); unicode.IsDigit(r) || r == '.' && !dot; r = l.peek(0) {
+		if r == '.' {
+			dot = true
+		} else {
+			digits++
+		}
+
+		l.pos++
+	}
+
+	if digits == 0 {
+		return token{}, syntaxError(l.line, "malformed numeral %q", string(l.input[start:l.pos]))
+	}
+
+	return token{kind: tokenID, text: string(l.input[start:l.pos]), line: l.line}, nil
+}
+
+// quoted reads a double-quoted string ID, in which \" and \\ stand for a quote and a backslash.
+// Quoted strings concatenated by "+" form a single ID.
+func (l *lexer) quoted() (token, error) {
+	var (
+		t    = token{kind: tokenID, quoted: true, line: l.line}
+		text strings.Builder
+	)
+
+	for {
+		// Skipping the opening quote
+		l.advance()
+
+		for l.peek(0) != '"' {
+			if l.pos >= len(l.input) {
+				return t, syntaxError(t.line, "unterminated string")
+			}
+
+			switch {
+			case l.peek(0) == '\\' && (l.peek(1) == '"' || l.peek(1) == '\\'):
+				text.WriteRune(l.peek(1))
+				l.advance()
+			case l.peek(0) == '\\' && l.peek(1) == '\n':
+				// Line continuation
+				l.advance()
+			default:
+				text.WriteRune(l.peek(0))
+			}
+
+			l.advance()
+		}
+
+		// Skipping the closing quote
+		l.advance()
+
+		// Looking for concatenation
+		pos, line := l.pos, l.line
+
+		if err := l.skipSpace(); err != nil {
+			return t, err
+		}
+
+		if l.peek(0) != '+' {
+			l.pos, l.line = pos, line
+			break
+		}
+
+		l.advance()
+
+		if err := l.skipSpace(); err != nil {
+			return t, err
+		}
+
+		if l.peek(0) != '"' {
+			return t, syntaxError(l.line, "expected string after '+'")
+		}
+	}
+
+	t.text = text.String()
+
+	return t, nil
+}
+
+// html reads an HTML string ID, delimited by balanced angle brackets.
+func (l *lexer) html() (token, error) {
+	var (
+		t     = token{kind: tokenID, quoted: true, line: l.line}
+		depth = 1
+	)
+
+	l.advance()
+
+	start := l.pos
+
+	for {
+		if l.pos >= len(l.input) {
+			return t, syntaxError(t.line, "unterminated HTML string")
+		}
+
+		switch l.peek(0) {
+		case '<':
+			depth++
+		case '>':
+			depth--
+		}
+
+		if depth == 0 {
+			break
+		}
+
+		l.advance()
+	}
+
+	t.text = string(l.input[start:l.pos])
+
+	// Skipping the closing bracket
+	l.advance()
+
+	return t, nil
+}
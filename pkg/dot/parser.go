@@ -0,0 +1,554 @@
+package dot
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/ellescotz/graph_backend/pkg/core"
+)
+
+// MediaType is the media type of DOT documents.
+const MediaType string = "text/vnd.graphviz"
+
+// defaultWeight is the weight of edges having neither a weight nor a numeric label.
+const defaultWeight float64 = 1
+
+// maxDepth is the max. nesting depth of subgraphs, so that parsing cannot exhaust the stack.
+const maxDepth int = 100
+
+// SyntaxError represents a malformed DOT document.
+type SyntaxError struct {
+	Line    int
+	Message string
+}
+
+// Error returns the message of the error with the line it happened on.
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("dot: line %v: %v", e.Line, e.Message)
+}
+
+// syntaxError returns a SyntaxError on line with the formatted message.
+func syntaxError(line int, format string, args ...interface{}) *SyntaxError {
+	return &SyntaxError{
+		Line:    line,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+// attributes represents the attributes of a DOT statement in order of appearance.
+type attributes []attribute
+
+// attribute represents a key-value attribute of a DOT statement.
+type attribute struct {
+	key, value string
+}
+
+// scope holds the default node and edge attributes of a graph or subgraph.
+type scope struct {
+	node, edge attributes
+}
+
+// parser builds a core.Graph from DOT tokens.
+type parser struct {
+	lexer    *lexer
+	token    token
+	directed bool
+	depth    int // nesting depth of the current subgraph
+	graph    core.Graph
+	nodes    map[string]int   // index of nodes in graph.Nodes by name
+	edges    map[edgeKey]bool // edges of graph
+}
+
+// edgeKey identifies the edges equal to each other: between the same nodes, with the same weight.
+type edgeKey struct {
+	from, to string
+	weight   float64
+}
+
+// keyOf returns the key of e.
+func keyOf(e *core.Edge) edgeKey {
+	return edgeKey{from: e.Nodes[0].Name, to: e.Nodes[1].Name, weight: e.Weight}
+}
+
+// Parse reads the first graph of a DOT document from r.
+// Both digraphs and graphs are supported, edges of undirected graphs become edges in both directions.
+// Edge weights are taken from the "weight" attribute, or from the "label" attribute if it is a number;
+// edges having neither of them weigh 1. Other edge attributes are kept as edge attributes.
+// The "pos" node attribute is taken as the position of the node,
+// other node attributes are kept as node attributes. Graph attributes are ignored.
+// Repeated edges are merged, and ports are ignored.
+// Subgraphs can be nested up to maxDepth levels.
+// It returns a *SyntaxError if the document is malformed.
+func Parse(r io.Reader) (core.Graph, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return core.Graph{}, err
+	}
+
+	p := &parser{
+		lexer: newLexer(string(data)),
+		graph: core.Graph{
+			Nodes: []core.Node{},
+			Edges: []core.Edge{},
+		},
+		nodes: make(map[string]int),
+		edges: make(map[edgeKey]bool),
+	}
+
+	if err = p.next(); err != nil {
+		return core.Graph{}, err
+	}
+
+	if err = p.parseGraph(); err != nil {
+		return core.Graph{}, err
+	}
+
+	return p.graph, nil
+}
+
+// next reads the next token.
+func (p *parser) next() error {
+	t, err := p.lexer.next()
+	if err != nil {
+		return err
+	}
+
+	p.token = t
+
+	return nil
+}
+
+// expect checks that the current token is the punctuation s, and reads the next token.
+func (p *parser) expect(s string) error {
+	if !p.token.is(s) {
+		return p.unexpected(fmt.Sprintf("%q", s))
+	}
+
+	return p.next()
+}
+
+// unexpected returns a SyntaxError for the current token when expected was expected.
+func (p *parser) unexpected(expected string) error {
+	if p.token.kind == tokenEOF {
+		return syntaxError(p.token.line, "unexpected end of input, expected %v", expected)
+	}
+
+	return syntaxError(p.token.line, "unexpected %q, expected %v", p.token.text, expected)
+}
+
+// id reads an ID.
+func (p *parser) id() (string, error) {
+	if p.token.kind != tokenID {
+		return "", p.unexpected("ID")
+	}
+
+	text := p.token.text
+
+	return text, p.next()
+}
+
+// parseGraph parses: [strict] (graph | digraph) [ID] '{' stmt_list '}'
+func (p *parser) parseGraph() error {
+	if p.token.is("strict") {
+		if err := p.next(); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case p.token.is("digraph"):
+		p.directed = true
+	case p.token.is("graph"):
+		p.directed = false
+	default:
+		return p.unexpected(`"graph" or "digraph"`)
+	}
+
+	if err := p.next(); err != nil {
+		return err
+	}
+
+	if p.token.kind == tokenID {
+		if err := p.next(); err != nil {
+			return err
+		}
+	}
+
+	if err := p.expect("{"); err != nil {
+		return err
+	}
+
+	if _, err := p.parseStatements(&scope{}); err != nil {
+		return err
+	}
+
+	return p.expect("}")
+}
+
+// parseStatements parses the statements of a graph or subgraph until the closing brace.
+// It returns the names of the nodes used in the statements.
+func (p *parser) parseStatements(s *scope) ([]string, error) {
+	var members []string
+
+	for !p.token.is("}") && p.token.kind != tokenEOF {
+		nodes, err := p.parseStatement(s)
+		if err != nil {
+			return nil, err
+		}
+
+		members = append(members, nodes...)
+
+		if p.token.is(";") {
+			if err = p.next(); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return members, nil
+}
+
+// parseStatement parses a node, edge, attribute or subgraph statement, or an ID '=' ID assignment.
+// It returns the names of the nodes used in the statement.
+func (p *parser) parseStatement(s *scope) ([]string, error) {
+	// Default attributes
+	for _, kind := range []string{"graph", "node", "edge"} {
+		if !p.token.is(kind) {
+			continue
+		}
+
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+
+		attrs, err := p.parseAttributes()
+		if err != nil {
+			return nil, err
+		}
+
+		switch kind {
+		case "node":
+			s.node = append(s.node, attrs...)
+		case "edge":
+			s.edge = append(s.edge, attrs...)
+		}
+
+		return nil, nil
+	}
+
+	line := p.token.line
+
+	nodes, isSubgraph, err := p.parseOperand(s)
+	if err != nil {
+		return nil, err
+	}
+
+	// Graph attribute assignment
+	if !isSubgraph && p.token.is("=") {
+		if err = p.next(); err != nil {
+			return nil, err
+		}
+
+		_, err = p.id()
+
+		return nil, err
+	}
+
+	// Node or subgraph statement
+	if p.token.kind != tokenDirected && p.token.kind != tokenUndirected {
+		if isSubgraph {
+			return nodes, nil
+		}
+
+		attrs, err := p.parseAttributes()
+		if err != nil {
+			return nil, err
+		}
+
+		return nodes, p.node(nodes[0], s, attrs, line)
+	}
+
+	// Edge statement
+	operands := [][]string{nodes}
+	members := nodes
+
+	for p.token.kind == tokenDirected || p.token.kind == tokenUndirected {
+		if p.directed && p.token.kind == tokenUndirected {
+			return nil, syntaxError(p.token.line, `"--" used in digraph`)
+		}
+
+		if !p.directed && p.token.kind == tokenDirected {
+			return nil, syntaxError(p.token.line, `"->" used in graph`)
+		}
+
+		if err = p.next(); err != nil {
+			return nil, err
+		}
+
+		if nodes, _, err = p.parseOperand(s); err != nil {
+			return nil, err
+		}
+
+		operands = append(operands, nodes)
+		members = append(members, nodes...)
+	}
+
+	attrs, err := p.parseAttributes()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 1; i < len(operands); i++ {
+		for _, from := range operands[i-1] {
+			for _, to := range operands[i] {
+				if err = p.edge(from, to, s, attrs, line); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	return members, nil
+}
+
+// parseOperand parses a node ID with an optional port, or a subgraph.
+// It returns the names of the nodes of the operand, and whether it was a subgraph.
+func (p *parser) parseOperand(s *scope) ([]string, bool, error) {
+	if p.token.is("subgraph") || p.token.is("{") {
+		nodes, err := p.parseSubgraph(s)
+
+		return nodes, true, err
+	}
+
+	line := p.token.line
+
+	name, err := p.id()
+	if err != nil {
+		return nil, false, err
+	}
+
+	// Ignoring ports
+	for i := 0; i < 2 && p.token.is(":"); i++ {
+		if err = p.next(); err != nil {
+			return nil, false, err
+		}
+
+		if _, err = p.id(); err != nil {
+			return nil, false, err
+		}
+	}
+
+	// Node IDs followed by '=' are graph attributes, not nodes
+	if p.token.is("=") {
+		return nil, false, nil
+	}
+
+	if err = p.node(name, s, nil, line); err != nil {
+		return nil, false, err
+	}
+
+	return []string{name}, false, nil
+}
+
+// parseSubgraph parses: [subgraph [ID]] '{' stmt_list '}'
+// Default attributes set in the subgraph do not apply outside of it.
+func (p *parser) parseSubgraph(s *scope) ([]string, error) {
+	if p.token.is("subgraph") {
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+
+		if p.token.kind == tokenID {
+			if err := p.next(); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if p.depth == maxDepth {
+		return nil, syntaxError(p.token.line, "subgraphs nested deeper than %v", maxDepth)
+	}
+
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+
+	p.depth++
+	defer func() { p.depth-- }()
+
+	inner := &scope{
+		node: append(attributes{}, s.node...),
+		edge: append(attributes{}, s.edge...),
+	}
+
+	nodes, err := p.parseStatements(inner)
+	if err != nil {
+		return nil, err
+	}
+
+	return nodes, p.expect("}")
+}
+
+// parseAttributes parses any number of attribute lists: ('[' [a_list] ']')*
+func (p *parser) parseAttributes() (attributes, error) {
+	var attrs attributes
+
+	for p.token.is("[") {
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+
+		for !p.token.is("]") {
+			key, err := p.id()
+			if err != nil {
+				return nil, err
+			}
+
+			// Attributes without value are set to true
+			value := "true"
+
+			if p.token.is("=") {
+				if err = p.next(); err != nil {
+					return nil, err
+				}
+
+				if value, err = p.id(); err != nil {
+					return nil, err
+				}
+			}
+
+			attrs = append(attrs, attribute{key: key, value: value})
+
+			if p.token.is(",") || p.token.is(";") {
+				if err = p.next(); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+	}
+
+	return attrs, nil
+}
+
+// node adds the node called name to the graph with the default attributes of s if it is new,
+// and sets attrs on it.
+func (p *parser) node(name string, s *scope, attrs attributes, line int) error {
+	i, ok := p.nodes[name]
+	if !ok {
+		i = len(p.graph.Nodes)
+		p.nodes[name] = i
+		p.graph.Nodes = append(p.graph.Nodes, core.Node{Name: name})
+
+		attrs = append(append(attributes{}, s.node...), attrs...)
+	}
+
+	n := &p.graph.Nodes[i]
+
+	for _, attr := range attrs {
+		if attr.key != "pos" {
+			n.SetAttribute(attr.key, attr.value)
+			continue
+		}
+
+		position, err := parsePosition(attr.value)
+		if err != nil {
+			return syntaxError(line, "malformed pos %q of node %q", attr.value, name)
+		}
+
+		n.Position = &position
+	}
+
+	return nil
+}
+
+// edge adds the edge from the node called from to the node called to with the default attributes of s
+// and attrs, and the edge in reverse direction as well for undirected graphs.
+func (p *parser) edge(from, to string, s *scope, attrs attributes, line int) error {
+	var (
+		e      = core.Edge{Weight: defaultWeight}
+		weight = false
+	)
+
+	e.Nodes[0].Name = from
+	e.Nodes[1].Name = to
+
+	for _, attr := range append(append(attributes{}, s.edge...), attrs...) {
+		switch attr.key {
+		case "weight":
+			w, err := strconv.ParseFloat(attr.value, 64)
+			if err != nil {
+				return syntaxError(line, "malformed weight %q of edge from %q to %q", attr.value, from, to)
+			}
+
+			e.Weight, weight = w, true
+		case "label":
+			// Numeric labels are taken for weights
+			if w, err := strconv.ParseFloat(attr.value, 64); err == nil {
+				if !weight {
+					e.Weight = w
+				}
+
+				continue
+			}
+
+			e.SetAttribute(attr.key, attr.value)
+		default:
+			e.SetAttribute(attr.key, attr.value)
+		}
+	}
+
+	edges := []core.Edge{e}
+
+	if !p.directed {
+		reverse := e
+
+		reverse.Nodes[0], reverse.Nodes[1] = e.Nodes[1], e.Nodes[0]
+		edges = append(edges, reverse)
+	}
+
+	if from == to {
+		return syntaxError(line, "self-loop on node %q is not supported", from)
+	}
+
+	for _, e := range edges {
+		key := keyOf(&e)
+
+		if !p.edges[key] {
+			p.edges[key] = true
+			p.graph.Edges = append(p.graph.Edges, e)
+		}
+	}
+
+	return nil
+}
+
+// parsePosition parses a Graphviz position: "x,y" with an optional trailing "!".
+func parsePosition(value string) (core.Position, error) {
+	var position core.Position
+
+	coordinates := strings.Split(strings.TrimSuffix(value, "!"), ",")
+	if len(coordinates) < 2 {
+		return position, errors.New("missing coordinate")
+	}
+
+	x, err := strconv.ParseFloat(strings.TrimSpace(coordinates[0]), 64)
+	if err != nil {
+		return position, err
+	}
+
+	y, err := strconv.ParseFloat(strings.TrimSpace(coordinates[1]), 64)
+	if err != nil {
+		return position, err
+	}
+
+	position.X, position.Y = x, y
+
+	return position, nil
+}
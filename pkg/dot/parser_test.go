@@ -0,0 +1,130 @@
+package dot
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ellescotz/graph_backend/pkg/core"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	nodeA := core.Node{Name: "A"}
+	nodeB := core.Node{Name: "B"}
+	nodeC := core.Node{Name: "C"}
+	nodeZ := core.Node{Name: "Zürich"}
+
+	edgeAB := core.Edge{
+		Nodes:  [2]core.Node{nodeA, nodeB},
+		Weight: 2.5,
+	}
+
+	edgeBC := core.Edge{
+		Nodes:  [2]core.Node{nodeB, nodeC},
+		Weight: 3,
+	}
+
+	edgeAZ := core.Edge{
+		Nodes:  [2]core.Node{nodeA, nodeZ},
+		Weight: 1,
+	}
+
+	edgeZA := core.Edge{
+		Nodes:  [2]core.Node{nodeZ, nodeA},
+		Weight: 1,
+	}
+
+	// Case 1: digraph with weights, labels, attributes and comments
+	graph, err := Parse(strings.NewReader(`
+		/* routes */
+		strict digraph "routes" {
+			rankdir=LR
+			node [shape=box]
+			A [pos="1,2!", color=blue] // start
+			A -> B [weight=2.5, color="green"];
+			B -> C [label=3]
+			A -> "Zürich"
+			A -> B [weight=2.5]
+		}`))
+	if err != nil {
+		t.Fatalf("Parse did not work. Got error %v", err)
+	}
+
+	if len(graph.Nodes) != 4 {
+		t.Errorf("Parse did not work. Got %v instead of %v", len(graph.Nodes), 4)
+	}
+
+	expectedEdges := []core.Edge{edgeAB, edgeBC, edgeAZ}
+
+	if len(graph.Edges) != len(expectedEdges) {
+		t.Fatalf("Parse did not work. Got %v instead of %v", graph.Edges, expectedEdges)
+	}
+
+	for i := range expectedEdges {
+		if !graph.Edges[i].Equals(&expectedEdges[i]) {
+			t.Errorf("Parse did not work. Got %v instead of %v", graph.Edges[i], expectedEdges[i])
+		}
+	}
+
+	if color, _ := graph.Edges[0].Attribute("color"); color != "green" {
+		t.Errorf("Parse did not work. Got %v instead of %v", color, "green")
+	}
+
+	if _, ok := graph.Edges[1].Attribute("label"); ok {
+		t.Errorf("Parse did not work. Numeric label kept as attribute")
+	}
+
+	if position := graph.Nodes[0].Position; position == nil || *position != (core.Position{X: 1, Y: 2}) {
+		t.Errorf("Parse did not work. Got %v instead of %v", position, core.Position{X: 1, Y: 2})
+	}
+
+	if shape, _ := graph.Nodes[2].Attribute("shape"); shape != "box" {
+		t.Errorf("Parse did not work. Got %v instead of %v", shape, "box")
+	}
+
+	// Case 2: undirected graph with subgraphs
+	graph, err = Parse(strings.NewReader(`graph { A -- { "Zürich" } }`))
+	if err != nil {
+		t.Fatalf("Parse did not work. Got error %v", err)
+	}
+
+	expectedEdges = []core.Edge{edgeAZ, edgeZA}
+
+	if len(graph.Edges) != len(expectedEdges) {
+		t.Fatalf("Parse did not work. Got %v instead of %v", graph.Edges, expectedEdges)
+	}
+
+	for i := range expectedEdges {
+		if !graph.Edges[i].Equals(&expectedEdges[i]) {
+			t.Errorf("Parse did not work. Got %v instead of %v", graph.Edges[i], expectedEdges[i])
+		}
+	}
+
+	// Case 3: subgraphs nested up to the max. depth
+	if _, err = Parse(strings.NewReader("digraph {" + strings.Repeat("{", maxDepth) + strings.Repeat("}", maxDepth+1))); err != nil {
+		t.Errorf("Parse did not work. Got error %v", err)
+	}
+
+	// Case 4: malformed documents
+	for document, line := range map[string]int{
+		"digraph {\n A -- B }":                          2,
+		"graph { A -> B }":                              1,
+		"digraph { A -> B [weight=x] }":                 1,
+		"digraph { A -> A }":                            1,
+		"digraph {\n A -> \n":                           3,
+		"digraph { A [pos=\"1\"] }":                     1,
+		"digraph { \"A }":                               1,
+		"tree { }":                                      1,
+		"digraph {\n\n A -> B [label=3 }":               3,
+		"digraph {\n" + strings.Repeat("{", maxDepth+1): 2,
+	} {
+		var syntaxErr *SyntaxError
+
+		_, err = Parse(strings.NewReader(document))
+		if !errors.As(err, &syntaxErr) || syntaxErr.Line != line {
+			t.Errorf("Parse did not work for %q. Got error %v instead of error on line %v", document, err, line)
+		}
+	}
+}
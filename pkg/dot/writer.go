@@ -0,0 +1,206 @@
+package dot
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ellescotz/graph_backend/pkg/core"
+)
+
+// highlightColor is the color of the nodes and edges of highlighted paths.
+const highlightColor string = "red"
+
+// Limits of the paths written by WritePaths, as each path is written with the whole graph.
+const (
+	MaxPaths      int = 100     // paths written at most
+	maxStatements int = 1 << 20 // node and edge statements written at most, for at least one path
+)
+
+// Write writes g to w as a DOT digraph.
+// Node positions are written as "pos" attributes. Edge weights are written as "label" attributes,
+// so that Graphviz displays them, or as "weight" attributes for edges having a label attribute already.
+func Write(w io.Writer, g core.Graph) error {
+	buffer := bufio.NewWriter(w)
+
+	writeGraph(buffer, "G", nil, g, nil)
+
+	return buffer.Flush()
+}
+
+// PathLimit returns how many paths found in g WritePaths writes at most:
+// MaxPaths, or fewer if writing g that many times would exceed maxStatements statements.
+// At least one path is written.
+func PathLimit(g core.Graph) int {
+	statements := len(g.AllNodes()) + len(g.Edges)
+
+	if statements == 0 || maxStatements/statements >= MaxPaths {
+		return MaxPaths
+	}
+
+	if limit := maxStatements / statements; limit > 1 {
+		return limit
+	}
+
+	return 1
+}
+
+// WritePaths writes paths found in g to w as DOT digraphs, one for each path.
+// Each digraph is the whole g with the nodes and edges of the path highlighted,
+// and labelled with the weight of the path. g is written without highlighting if paths is empty.
+// Only the first PathLimit(g) paths are written.
+func WritePaths(w io.Writer, g core.Graph, paths []core.Path) error {
+	buffer := bufio.NewWriter(w)
+
+	if len(paths) == 0 {
+		writeGraph(buffer, "G", nil, g, nil)
+	}
+
+	if limit := PathLimit(g); len(paths) > limit {
+		paths = paths[:limit]
+	}
+
+	for i := range paths {
+		attrs := attributes{{key: "label", value: "weight: " + formatWeight(paths[i].Weight)}}
+
+		writeGraph(buffer, "path"+strconv.Itoa(i+1), attrs, g, newHighlight(&paths[i].Subgraph))
+	}
+
+	return buffer.Flush()
+}
+
+// highlight holds the nodes, by name, and the edges of a highlighted subgraph.
+type highlight struct {
+	nodes map[string]bool
+	edges map[edgeKey]bool
+}
+
+// newHighlight returns the highlight of the nodes and edges of g.
+func newHighlight(g *core.Graph) *highlight {
+	h := &highlight{
+		nodes: make(map[string]bool, len(g.Nodes)),
+		edges: make(map[edgeKey]bool, len(g.Edges)),
+	}
+
+	for _, n := range g.AllNodes() {
+		h.nodes[n.Name] = true
+	}
+
+	for i := range g.Edges {
+		h.edges[keyOf(&g.Edges[i])] = true
+	}
+
+	return h
+}
+
+// writeGraph writes g as a DOT digraph called name with graph attributes attrs.
+// The nodes and edges of highlighted are highlighted if it is not nil.
+func writeGraph(w *bufio.Writer, name string, attrs attributes, g core.Graph, highlighted *highlight) {
+	fmt.Fprintf(w, "digraph %v {\n", quote(name))
+
+	for _, attr := range attrs {
+		fmt.Fprintf(w, "\t%v=%v;\n", quote(attr.key), quote(attr.value))
+	}
+
+	for _, n := range g.AllNodes() {
+		attrs := nodeAttributes(n)
+
+		if highlighted != nil && highlighted.nodes[n.Name] {
+			attrs = append(attrs, attribute{key: "color", value: highlightColor})
+		}
+
+		fmt.Fprintf(w, "\t%v%v;\n", quote(n.Name), formatAttributes(attrs))
+	}
+
+	for i := range g.Edges {
+		e := &g.Edges[i]
+		attrs := edgeAttributes(e)
+
+		if highlighted != nil && highlighted.edges[keyOf(e)] {
+			attrs = append(attrs,
+				attribute{key: "color", value: highlightColor},
+				attribute{key: "penwidth", value: "2"},
+			)
+		}
+
+		fmt.Fprintf(w, "\t%v -> %v%v;\n", quote(e.Nodes[0].Name), quote(e.Nodes[1].Name), formatAttributes(attrs))
+	}
+
+	fmt.Fprint(w, "}\n")
+}
+
+// nodeAttributes returns the DOT attributes of n.
+func nodeAttributes(n core.Node) attributes {
+	attrs := sortedAttributes(n.Attributes)
+
+	if n.Position != nil {
+		attrs = append(attrs, attribute{
+			key:   "pos",
+			value: formatWeight(n.Position.X) + "," + formatWeight(n.Position.Y),
+		})
+	}
+
+	return attrs
+}
+
+// edgeAttributes returns the DOT attributes of e.
+func edgeAttributes(e *core.Edge) attributes {
+	attrs := sortedAttributes(e.Attributes)
+	key := "label"
+
+	if _, ok := e.Attribute("label"); ok {
+		key = "weight"
+	}
+
+	return append(attrs, attribute{key: key, value: formatWeight(e.Weight)})
+}
+
+// sortedAttributes returns attrs sorted by key.
+func sortedAttributes(attrs *core.Attributes) attributes {
+	if attrs == nil {
+		return nil
+	}
+
+	sorted := make(attributes, 0, len(*attrs))
+
+	for key, value := range *attrs {
+		sorted = append(sorted, attribute{key: key, value: value})
+	}
+
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].key < sorted[j].key
+	})
+
+	return sorted
+}
+
+// formatAttributes returns the DOT attribute list of attrs, or nothing if attrs is empty.
+func formatAttributes(attrs attributes) string {
+	if len(attrs) == 0 {
+		return ""
+	}
+
+	list := make([]string, len(attrs))
+
+	for i, attr := range attrs {
+		list[i] = quote(attr.key) + "=" + quote(attr.value)
+	}
+
+	return " [" + strings.Join(list, ", ") + "]"
+}
+
+// formatWeight returns the shortest representation of weight.
+func formatWeight(weight float64) string {
+	return strconv.FormatFloat(weight, 'g', -1, 64)
+}
+
+// quoteReplacer escapes the backslashes and quotes of DOT strings.
+var quoteReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+// quote returns s as a quoted DOT string.
+func quote(s string) string {
+	return `"` + quoteReplacer.Replace(s) + `"`
+}
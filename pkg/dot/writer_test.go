@@ -0,0 +1,200 @@
+package dot
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/ellescotz/graph_backend/pkg/core"
+)
+
+func TestWrite(t *testing.T) {
+	t.Parallel()
+
+	nodeA := core.Node{Name: "A", Position: &core.Position{X: 1, Y: 2}}
+	nodeB := core.Node{Name: "B"}
+	nodeC := core.Node{Name: `C "quoted"`}
+
+	nodeA.SetAttribute("color", "blue")
+
+	edgeAB := core.Edge{
+		Nodes:  [2]core.Node{nodeA, nodeB},
+		Weight: 2.5,
+	}
+
+	edgeBC := core.Edge{
+		Nodes:  [2]core.Node{nodeB, nodeC},
+		Weight: -1,
+	}
+
+	edgeBC.SetAttribute("label", "ferry")
+
+	graph := core.Graph{
+		Nodes: []core.Node{nodeA, nodeB},
+		Edges: []core.Edge{edgeAB, edgeBC},
+	}
+
+	var buffer bytes.Buffer
+
+	if err := Write(&buffer, graph); err != nil {
+		t.Fatalf("Write did not work. Got error %v", err)
+	}
+
+	expected := `digraph "G" {
+	"A" ["color"="blue", "pos"="1,2"];
+	"B";
+	"C \"quoted\"";
+	"A" -> "B" ["label"="2.5"];
+	"B" -> "C \"quoted\"" ["label"="ferry", "weight"="-1"];
+}
+`
+
+	if buffer.String() != expected {
+		t.Errorf("Write did not work. Got %v instead of %v", buffer.String(), expected)
+	}
+
+	// Writing and parsing gives back the graph
+	parsed, err := Parse(&buffer)
+	if err != nil {
+		t.Fatalf("Parse did not work. Got error %v", err)
+	}
+
+	if len(parsed.Nodes) != 3 || len(parsed.Edges) != 2 || !parsed.Edges[0].Equals(&edgeAB) || !parsed.Edges[1].Equals(&edgeBC) {
+		t.Errorf("Write did not work. Got %v instead of %v", parsed, graph)
+	}
+
+	if label, _ := parsed.Edges[1].Attribute("label"); label != "ferry" {
+		t.Errorf("Write did not work. Got %v instead of %v", label, "ferry")
+	}
+}
+
+func TestWriteEscapes(t *testing.T) {
+	t.Parallel()
+
+	// Names with backslashes, even before quotes or at the end, are written and parsed back unchanged
+	names := []string{`a\`, `\\`, `b\"c`, `d\n"`}
+
+	var graph core.Graph
+
+	for i := 1; i < len(names); i++ {
+		graph.Edges = append(graph.Edges, core.Edge{
+			Nodes:  [2]core.Node{{Name: names[i-1]}, {Name: names[i]}},
+			Weight: 1,
+		})
+	}
+
+	var buffer bytes.Buffer
+
+	if err := Write(&buffer, graph); err != nil {
+		t.Fatalf("Write did not work. Got error %v", err)
+	}
+
+	parsed, err := Parse(&buffer)
+	if err != nil {
+		t.Fatalf("Parse did not work. Got error %v", err)
+	}
+
+	for i, n := range parsed.AllNodes() {
+		if i >= len(names) || n.Name != names[i] {
+			t.Errorf("Write did not work. Got %v instead of %v", parsed.AllNodes(), names)
+			break
+		}
+	}
+}
+
+func TestWritePaths(t *testing.T) {
+	t.Parallel()
+
+	nodeA := core.Node{Name: "A"}
+	nodeB := core.Node{Name: "B"}
+	nodeC := core.Node{Name: "C"}
+
+	edgeAB := core.Edge{
+		Nodes:  [2]core.Node{nodeA, nodeB},
+		Weight: 1,
+	}
+
+	edgeBC := core.Edge{
+		Nodes:  [2]core.Node{nodeB, nodeC},
+		Weight: 2,
+	}
+
+	graph := core.Graph{
+		Nodes: []core.Node{nodeA, nodeB, nodeC},
+		Edges: []core.Edge{edgeAB, edgeBC},
+	}
+
+	path := core.Path{
+		Subgraph: core.Graph{
+			Nodes: []core.Node{nodeA, nodeB},
+			Edges: []core.Edge{edgeAB},
+		},
+		Weight: 1,
+	}
+
+	var buffer bytes.Buffer
+
+	if err := WritePaths(&buffer, graph, []core.Path{path, path}); err != nil {
+		t.Fatalf("WritePaths did not work. Got error %v", err)
+	}
+
+	output := buffer.String()
+
+	if count := strings.Count(output, "digraph"); count != 2 {
+		t.Errorf("WritePaths did not work. Got %v instead of %v", count, 2)
+	}
+
+	if !strings.Contains(output, `"A" -> "B" ["label"="1", "color"="red", "penwidth"="2"];`) ||
+		!strings.Contains(output, `"B" -> "C" ["label"="2"];`) ||
+		!strings.Contains(output, `"C";`) {
+		t.Errorf("WritePaths did not work. Got %v", output)
+	}
+
+	// Without paths only the graph is written
+	buffer.Reset()
+
+	if err := WritePaths(&buffer, graph, nil); err != nil || strings.Count(buffer.String(), "digraph") != 1 {
+		t.Errorf("WritePaths did not work. Got %v and error %v", buffer.String(), err)
+	}
+
+	// Only the first MaxPaths paths are written
+	buffer.Reset()
+
+	paths := make([]core.Path, MaxPaths+1)
+
+	for i := range paths {
+		paths[i] = path
+	}
+
+	if err := WritePaths(&buffer, graph, paths); err != nil || strings.Count(buffer.String(), "digraph") != MaxPaths {
+		t.Errorf("WritePaths did not work. Got %v digraphs and error %v instead of %v", strings.Count(buffer.String(), "digraph"), err, MaxPaths)
+	}
+}
+
+func TestPathLimit(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		nodes int
+		limit int
+	}{
+		{0, MaxPaths},
+		{3, MaxPaths},
+		{maxStatements / MaxPaths, MaxPaths},
+		{maxStatements / 3, 3},
+		{maxStatements/2 + 1, 1},
+	}
+
+	for _, test := range tests {
+		graph := core.Graph{Nodes: make([]core.Node, test.nodes)}
+
+		for i := range graph.Nodes {
+			graph.Nodes[i].Name = strconv.Itoa(i)
+		}
+
+		if limit := PathLimit(graph); limit != test.limit {
+			t.Errorf("PathLimit did not work. Got %v instead of %v for %v nodes", limit, test.limit, test.nodes)
+		}
+	}
+}
@@ -0,0 +1,248 @@
+package interchange
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/ellescotz/graph_backend/pkg/core"
+)
+
+// Media types of delimiter-separated documents.
+const (
+	CSVMediaType string = "text/csv"
+	TSVMediaType string = "text/tab-separated-values"
+)
+
+// Separators of delimiter-separated documents.
+const (
+	CSVSeparator rune = ','
+	TSVSeparator rune = '\t'
+)
+
+// byteOrderMark starts documents saved by some spreadsheet applications.
+const byteOrderMark rune = '\uFEFF'
+
+// CSVError represents a malformed value of a delimiter-separated document.
+// Rows and columns are numbered from 1.
+type CSVError struct {
+	Row     int
+	Column  int
+	Message string
+}
+
+// Error returns the message of the error with the row and column of the value.
+func (e *CSVError) Error() string {
+	return fmt.Sprintf("csv: row %v, column %v: %v", e.Row, e.Column, e.Message)
+}
+
+// csvError returns a CSVError at row and column with the formatted message.
+func csvError(row, column int, format string, args ...interface{}) *CSVError {
+	return &CSVError{
+		Row:     row,
+		Column:  column,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+// wrongColumn returns the column to report for a row of columns values, instead of at most maxColumns:
+// the first extra column if the row has too many values, otherwise the first missing one.
+func wrongColumn(columns, maxColumns int) int {
+	if columns > maxColumns {
+		return maxColumns + 1
+	}
+
+	return columns + 1
+}
+
+// csvRecord represents a record of a delimiter-separated document.
+type csvRecord struct {
+	row    int // line of the record
+	fields []string
+}
+
+// readRecords reads every record of a delimiter-separated document from r,
+// with leading and trailing spaces of the values removed.
+func readRecords(r io.Reader, separator rune) ([]csvRecord, error) {
+	var (
+		buffered = bufio.NewReader(r)
+		records  []csvRecord
+	)
+
+	// Skipping the byte order mark
+	if first, _, err := buffered.ReadRune(); err == nil && first != byteOrderMark {
+		_ = buffered.UnreadRune()
+	}
+
+	reader := csv.NewReader(buffered)
+	reader.Comma = separator
+	reader.FieldsPerRecord = -1
+
+	for {
+		fields, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return records, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		for i := range fields {
+			fields[i] = strings.TrimSpace(fields[i])
+		}
+
+		row, _ := reader.FieldPos(0)
+
+		records = append(records, csvRecord{row: row, fields: fields})
+	}
+}
+
+// isEdgeListHeader reports whether record is the header of an edge list:
+// its weight is not a number, or its names are "source" and "target".
+func isEdgeListHeader(record []string) bool {
+	if len(record) == 3 {
+		if _, err := strconv.ParseFloat(record[2], 64); err != nil {
+			return true
+		}
+	}
+
+	return len(record) >= 2 && strings.EqualFold(record[0], "source") && strings.EqualFold(record[1], "target")
+}
+
+// ReadEdgeList reads a graph from a delimiter-separated edge list with the given separator.
+// Every row holds an edge: "source,target,weight", the weight is optional and defaults to 1 if missing or empty.
+// The first row is skipped as a header if its weight is not a number, or it names the columns "source" and "target".
+// Nodes are added in order of appearance. Repeated edges are merged.
+// It returns a *CSVError for malformed rows.
+func ReadEdgeList(r io.Reader, separator rune) (core.Graph, error) {
+	b := newBuilder("csv")
+
+	records, err := readRecords(r, separator)
+	if err != nil {
+		return core.Graph{}, err
+	}
+
+	for i, rec := range records {
+		row, record := rec.row, rec.fields
+
+		if i == 0 && isEdgeListHeader(record) {
+			continue
+		}
+
+		if len(record) != 2 && len(record) != 3 {
+			return core.Graph{}, csvError(row, wrongColumn(len(record), 3), "expected 2 or 3 columns, got %v", len(record))
+		}
+
+		e := core.Edge{Weight: defaultWeight}
+
+		for column := 0; column < 2; column++ {
+			if len(record[column]) == 0 {
+				return core.Graph{}, csvError(row, column+1, "empty node name")
+			}
+
+			e.Nodes[column].Name = record[column]
+
+			if !b.nodes[record[column]] {
+				_ = b.addNode(core.Node{Name: record[column]})
+			}
+		}
+
+		if e.Nodes[0].Equals(e.Nodes[1]) {
+			return core.Graph{}, csvError(row, 2, "self-loop on node %q is not supported", record[0])
+		}
+
+		if len(record) == 3 && len(record[2]) != 0 {
+			if e.Weight, err = strconv.ParseFloat(record[2], 64); err != nil {
+				return core.Graph{}, csvError(row, 3, "malformed weight %q", record[2])
+			}
+		}
+
+		if err = b.addEdge(e, true); err != nil {
+			return core.Graph{}, err
+		}
+	}
+
+	return b.graph, nil
+}
+
+// ReadAdjacencyMatrix reads a graph from a delimiter-separated weighted adjacency matrix with the given separator.
+// The first row holds the names of the nodes after an ignored first cell, every other row holds the name of a node
+// in the same order, followed by the weights of the edges from it to the nodes of the columns.
+// Empty and zero cells mean no edge, so the diagonal has to be empty or zero.
+// It returns a *CSVError for malformed rows and cells.
+func ReadAdjacencyMatrix(r io.Reader, separator rune) (core.Graph, error) {
+	b := newBuilder("csv")
+
+	records, err := readRecords(r, separator)
+	if err != nil {
+		return core.Graph{}, err
+	}
+
+	if len(records) == 0 {
+		return b.graph, nil
+	}
+
+	header := records[0]
+	names := header.fields[1:]
+
+	for i, name := range names {
+		if len(name) == 0 {
+			return core.Graph{}, csvError(header.row, i+2, "empty node name")
+		}
+
+		if err = b.addNode(core.Node{Name: name}); err != nil {
+			return core.Graph{}, csvError(header.row, i+2, "duplicate node %q", name)
+		}
+	}
+
+	if last := records[len(records)-1]; len(records)-1 != len(names) {
+		return core.Graph{}, csvError(last.row, 1, "expected %v rows of nodes, got %v", len(names), len(records)-1)
+	}
+
+	for i, rec := range records[1:] {
+		row, record := rec.row, rec.fields
+
+		if len(record) != len(names)+1 {
+			return core.Graph{}, csvError(row, wrongColumn(len(record), len(names)+1), "expected %v columns, got %v", len(names)+1, len(record))
+		}
+
+		if record[0] != names[i] {
+			return core.Graph{}, csvError(row, 1, "expected node %q, got %q", names[i], record[0])
+		}
+
+		for j, cell := range record[1:] {
+			column := j + 2
+
+			if len(cell) == 0 {
+				continue
+			}
+
+			weight, err := strconv.ParseFloat(cell, 64)
+			if err != nil {
+				return core.Graph{}, csvError(row, column, "malformed weight %q", cell)
+			}
+
+			if weight == 0 {
+				continue
+			}
+
+			if i == j {
+				return core.Graph{}, csvError(row, column, "self-loop on node %q is not supported", names[i])
+			}
+
+			e := core.Edge{Weight: weight}
+
+			e.Nodes[0].Name = names[i]
+			e.Nodes[1].Name = names[j]
+
+			if err = b.addEdge(e, true); err != nil {
+				return core.Graph{}, err
+			}
+		}
+	}
+
+	return b.graph, nil
+}
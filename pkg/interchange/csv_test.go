@@ -0,0 +1,152 @@
+package interchange
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ellescotz/graph_backend/pkg/core"
+)
+
+func TestReadEdgeList(t *testing.T) {
+	t.Parallel()
+
+	nodeA := core.Node{Name: "A"}
+	nodeB := core.Node{Name: "B"}
+	nodeC := core.Node{Name: "Zürich"}
+
+	edgeAB := core.Edge{
+		Nodes:  [2]core.Node{nodeA, nodeB},
+		Weight: 2.5,
+	}
+
+	edgeBC := core.Edge{
+		Nodes:  [2]core.Node{nodeB, nodeC},
+		Weight: 1,
+	}
+
+	edgeCA := core.Edge{
+		Nodes:  [2]core.Node{nodeC, nodeA},
+		Weight: -3,
+	}
+
+	expectedEdges := []core.Edge{edgeAB, edgeBC, edgeCA}
+
+	// Case 1: CSV with header and byte order mark, TSV without header
+	for _, document := range []struct {
+		text      string
+		separator rune
+	}{
+		{"\uFEFFsource,target,weight\nA,B,2.5\nB,Zürich,\n\"Zürich\", A ,-3\nA,B,2.5\n", CSVSeparator},
+		{"A\tB\t2.5\nB\tZürich\t1\nZürich\tA\t-3\n", TSVSeparator},
+	} {
+		graph, err := ReadEdgeList(strings.NewReader(document.text), document.separator)
+		if err != nil {
+			t.Fatalf("ReadEdgeList did not work. Got error %v", err)
+		}
+
+		if len(graph.Nodes) != 3 || graph.Nodes[2] != nodeC {
+			t.Errorf("ReadEdgeList did not work. Got %v", graph.Nodes)
+		}
+
+		if len(graph.Edges) != len(expectedEdges) {
+			t.Fatalf("ReadEdgeList did not work. Got %v instead of %v", graph.Edges, expectedEdges)
+		}
+
+		for i := range expectedEdges {
+			if !graph.Edges[i].Equals(&expectedEdges[i]) {
+				t.Errorf("ReadEdgeList did not work. Got %v instead of %v", graph.Edges[i], expectedEdges[i])
+			}
+		}
+	}
+
+	// Case 2: malformed rows
+	for document, expected := range map[string]CSVError{
+		"A,B,1\n\nB,C,x\n": {Row: 3, Column: 3},
+		"A,B\nB,B\n":       {Row: 2, Column: 2},
+		"A,B\n,C\n":        {Row: 2, Column: 1},
+		"A,B,1,2\n":        {Row: 1, Column: 4},
+		"A,B,1,2,3\n":      {Row: 1, Column: 4},
+		"A,B\nC\n":         {Row: 2, Column: 2},
+	} {
+		var csvErr *CSVError
+
+		_, err := ReadEdgeList(strings.NewReader(document), CSVSeparator)
+		if !errors.As(err, &csvErr) || csvErr.Row != expected.Row || csvErr.Column != expected.Column {
+			t.Errorf("ReadEdgeList did not work for %q. Got error %v instead of error at row %v, column %v",
+				document, err, expected.Row, expected.Column)
+		}
+	}
+}
+
+func TestReadAdjacencyMatrix(t *testing.T) {
+	t.Parallel()
+
+	nodeA := core.Node{Name: "A"}
+	nodeB := core.Node{Name: "B"}
+	nodeC := core.Node{Name: "C"}
+
+	edgeAB := core.Edge{
+		Nodes:  [2]core.Node{nodeA, nodeB},
+		Weight: 2,
+	}
+
+	edgeBC := core.Edge{
+		Nodes:  [2]core.Node{nodeB, nodeC},
+		Weight: 1.5,
+	}
+
+	edgeCA := core.Edge{
+		Nodes:  [2]core.Node{nodeC, nodeA},
+		Weight: -1,
+	}
+
+	expectedEdges := []core.Edge{edgeAB, edgeBC, edgeCA}
+
+	// Case 1: CSV with zeros, TSV with empty cells
+	for _, document := range []struct {
+		text      string
+		separator rune
+	}{
+		{",A,B,C\nA,0,2,0\nB,0,0,1.5\nC,-1,0,0\n", CSVSeparator},
+		{"\tA\tB\tC\nA\t\t2\t\nB\t\t\t1.5\nC\t-1\t\t\n", TSVSeparator},
+	} {
+		graph, err := ReadAdjacencyMatrix(strings.NewReader(document.text), document.separator)
+		if err != nil {
+			t.Fatalf("ReadAdjacencyMatrix did not work. Got error %v", err)
+		}
+
+		if len(graph.Nodes) != 3 {
+			t.Errorf("ReadAdjacencyMatrix did not work. Got %v", graph.Nodes)
+		}
+
+		if len(graph.Edges) != len(expectedEdges) {
+			t.Fatalf("ReadAdjacencyMatrix did not work. Got %v instead of %v", graph.Edges, expectedEdges)
+		}
+
+		for i := range expectedEdges {
+			if !graph.Edges[i].Equals(&expectedEdges[i]) {
+				t.Errorf("ReadAdjacencyMatrix did not work. Got %v instead of %v", graph.Edges[i], expectedEdges[i])
+			}
+		}
+	}
+
+	// Case 2: malformed rows and cells
+	for document, expected := range map[string]CSVError{
+		",A,B\nA,0,x\nB,0,0\n":     {Row: 2, Column: 3},
+		",A,B\nA,1,0\nB,0,0\n":     {Row: 2, Column: 2},
+		",A,A\nA,0,0\nA,0,0\n":     {Row: 1, Column: 3},
+		",A,B\nB,0,0\nA,0,0\n":     {Row: 2, Column: 1},
+		",A,B\nA,0,0\n":            {Row: 2, Column: 1},
+		",A,B\nA,0\nB,0,0\n":       {Row: 2, Column: 3},
+		",A,B\nA,0,0,1,2\nB,0,0\n": {Row: 2, Column: 4},
+	} {
+		var csvErr *CSVError
+
+		_, err := ReadAdjacencyMatrix(strings.NewReader(document), CSVSeparator)
+		if !errors.As(err, &csvErr) || csvErr.Row != expected.Row || csvErr.Column != expected.Column {
+			t.Errorf("ReadAdjacencyMatrix did not work for %q. Got error %v instead of error at row %v, column %v",
+				document, err, expected.Row, expected.Column)
+		}
+	}
+}
@@ -0,0 +1,302 @@
+package interchange
+
+import (
+	"encoding/xml"
+	"io"
+	"strconv"
+
+	"github.com/ellescotz/graph_backend/pkg/core"
+)
+
+// GEXFMediaType is the media type of GEXF documents.
+const GEXFMediaType string = "application/gexf+xml"
+
+// XML namespaces of GEXF 1.3.
+const (
+	gexfNamespace    string = "http://gexf.net/1.3"
+	gexfVizNamespace string = "http://gexf.net/1.3/viz"
+)
+
+// gexfLabel is the name of the attribute holding node and edge labels.
+const gexfLabel string = "label"
+
+// gexfDocument represents a GEXF document.
+type gexfDocument struct {
+	XMLName xml.Name  `xml:"gexf"`
+	Xmlns   string    `xml:"xmlns,attr,omitempty"`
+	Viz     string    `xml:"xmlns:viz,attr,omitempty"`
+	Version string    `xml:"version,attr,omitempty"`
+	Graph   gexfGraph `xml:"graph"`
+}
+
+// gexfGraph represents a GEXF graph.
+type gexfGraph struct {
+	DefaultEdgeType string           `xml:"defaultedgetype,attr,omitempty"` // directed, undirected or mutual
+	Attributes      []gexfAttributes `xml:"attributes"`
+	Nodes           []gexfNode       `xml:"nodes>node"`
+	Edges           []gexfEdge       `xml:"edges>edge"`
+}
+
+// gexfAttributes represents the attribute declarations of a class of elements.
+type gexfAttributes struct {
+	Class      string          `xml:"class,attr"` // node or edge
+	Attributes []gexfAttribute `xml:"attribute"`
+}
+
+// gexfAttribute represents the declaration of a GEXF attribute.
+type gexfAttribute struct {
+	ID      string  `xml:"id,attr"`
+	Title   string  `xml:"title,attr"`
+	Type    string  `xml:"type,attr"`
+	Default *string `xml:"default"`
+}
+
+// gexfNode represents a GEXF node.
+type gexfNode struct {
+	ID        string         `xml:"id,attr"`
+	Label     string         `xml:"label,attr,omitempty"`
+	AttValues *gexfAttValues `xml:"attvalues"`
+	Position  *gexfPosition  `xml:"position"`
+}
+
+// gexfPosition represents the position of a GEXF node.
+// XMLName is set when writing, so that the element is written in the viz namespace.
+type gexfPosition struct {
+	XMLName xml.Name
+	X       float64 `xml:"x,attr"`
+	Y       float64 `xml:"y,attr"`
+}
+
+// gexfEdge represents a GEXF edge.
+type gexfEdge struct {
+	ID        string         `xml:"id,attr"`
+	Source    string         `xml:"source,attr"`
+	Target    string         `xml:"target,attr"`
+	Type      string         `xml:"type,attr,omitempty"` // overrides the defaultedgetype of the graph
+	Weight    *string        `xml:"weight,attr"`
+	Label     string         `xml:"label,attr,omitempty"`
+	AttValues *gexfAttValues `xml:"attvalues"`
+}
+
+// gexfAttValues represents the attribute values of a GEXF node or edge.
+type gexfAttValues struct {
+	Values []gexfAttValue `xml:"attvalue"`
+}
+
+// gexfAttValue represents the value of a GEXF attribute.
+type gexfAttValue struct {
+	For   string `xml:"for,attr"`
+	Value string `xml:"value,attr"`
+}
+
+// ReadGEXF reads the graph of a GEXF document from r.
+// Node IDs become node names, node labels different from the IDs and edge labels become "label" attributes.
+// Declared attributes become node and edge attributes named by their titles, with their default values applied.
+// The weight of edges is taken as the edge weight, edges without weight weigh 1.
+// The viz:position of nodes is taken as their position.
+// Undirected and mutual edges become edges in both directions. Repeated edges are merged.
+func ReadGEXF(r io.Reader) (core.Graph, error) {
+	var (
+		document gexfDocument
+		b        = newBuilder("gexf")
+	)
+
+	if err := xml.NewDecoder(r).Decode(&document); err != nil {
+		return core.Graph{}, b.errorf("%v", err)
+	}
+
+	graph := document.Graph
+
+	// Attribute declarations by class and ID
+	declarations := map[string]map[string]gexfAttribute{
+		"node": {},
+		"edge": {},
+	}
+
+	for _, attributes := range graph.Attributes {
+		if declared, ok := declarations[attributes.Class]; ok {
+			for _, attribute := range attributes.Attributes {
+				declared[attribute.ID] = attribute
+			}
+		}
+	}
+
+	// values returns the attribute values of attValues with defaults of the declarations of class.
+	values := func(attValues *gexfAttValues, class string) map[string]string {
+		values := make(map[string]string)
+
+		for _, attribute := range declarations[class] {
+			if attribute.Default != nil {
+				values[attribute.Title] = *attribute.Default
+			}
+		}
+
+		if attValues == nil {
+			return values
+		}
+
+		for _, v := range attValues.Values {
+			title := v.For
+
+			if attribute, ok := declarations[class][v.For]; ok && len(attribute.Title) != 0 {
+				title = attribute.Title
+			}
+
+			values[title] = v.Value
+		}
+
+		return values
+	}
+
+	for _, gn := range graph.Nodes {
+		n := core.Node{Name: gn.ID}
+
+		for name, value := range values(gn.AttValues, "node") {
+			n.SetAttribute(name, value)
+		}
+
+		if len(gn.Label) != 0 && gn.Label != gn.ID {
+			n.SetAttribute(gexfLabel, gn.Label)
+		}
+
+		if gn.Position != nil {
+			n.Position = &core.Position{X: gn.Position.X, Y: gn.Position.Y}
+		}
+
+		if err := b.addNode(n); err != nil {
+			return core.Graph{}, err
+		}
+	}
+
+	for _, ge := range graph.Edges {
+		e := core.Edge{Weight: defaultWeight}
+
+		e.Nodes[0].Name = ge.Source
+		e.Nodes[1].Name = ge.Target
+
+		for name, value := range values(ge.AttValues, "edge") {
+			e.SetAttribute(name, value)
+		}
+
+		if len(ge.Label) != 0 {
+			e.SetAttribute(gexfLabel, ge.Label)
+		}
+
+		if ge.Weight != nil {
+			weight, err := b.parseWeight(*ge.Weight)
+			if err != nil {
+				return core.Graph{}, err
+			}
+
+			e.Weight = weight
+		}
+
+		edgeType := graph.DefaultEdgeType
+
+		if len(ge.Type) != 0 {
+			edgeType = ge.Type
+		}
+
+		if err := b.addEdge(e, edgeType != "undirected" && edgeType != "mutual"); err != nil {
+			return core.Graph{}, err
+		}
+	}
+
+	return b.graph, nil
+}
+
+// WriteGEXF writes g to w as a directed GEXF 1.3 graph.
+// Node and edge attributes are declared as string attributes,
+// and node positions are written as viz:position elements.
+func WriteGEXF(w io.Writer, g core.Graph) error {
+	document := gexfDocument{
+		Xmlns:   gexfNamespace,
+		Viz:     gexfVizNamespace,
+		Version: "1.3",
+		Graph: gexfGraph{
+			DefaultEdgeType: "directed",
+			Nodes:           []gexfNode{},
+			Edges:           []gexfEdge{},
+		},
+	}
+
+	graph := &document.Graph
+
+	// Declaring attributes
+	nodeNames, edgeNames, _ := attributeNames(g)
+
+	nodeAttributes, nodeIDs := declareGEXFAttributes("node", nodeNames)
+	edgeAttributes, edgeIDs := declareGEXFAttributes("edge", edgeNames)
+
+	for _, attributes := range []gexfAttributes{nodeAttributes, edgeAttributes} {
+		if len(attributes.Attributes) != 0 {
+			graph.Attributes = append(graph.Attributes, attributes)
+		}
+	}
+
+	for _, n := range g.AllNodes() {
+		gn := gexfNode{ID: n.Name, Label: n.Name}
+
+		gn.AttValues = gexfValues(n.Attributes, nodeIDs)
+
+		if n.Position != nil {
+			gn.Position = &gexfPosition{
+				XMLName: xml.Name{Local: "viz:position"},
+				X:       n.Position.X,
+				Y:       n.Position.Y,
+			}
+		}
+
+		graph.Nodes = append(graph.Nodes, gn)
+	}
+
+	for i, e := range g.Edges {
+		weight := formatFloat(e.Weight)
+
+		ge := gexfEdge{
+			ID:     strconv.Itoa(i),
+			Source: e.Nodes[0].Name,
+			Target: e.Nodes[1].Name,
+			Weight: &weight,
+		}
+
+		ge.AttValues = gexfValues(e.Attributes, edgeIDs)
+
+		graph.Edges = append(graph.Edges, ge)
+	}
+
+	return writeXML(w, document)
+}
+
+// gexfValues returns the values of attrs for the attributes declared with ids,
+// or nil if attrs is empty.
+func gexfValues(attrs *core.Attributes, ids map[string]string) *gexfAttValues {
+	names := sortedKeys(attrs)
+	if len(names) == 0 {
+		return nil
+	}
+
+	values := &gexfAttValues{}
+
+	for _, name := range names {
+		values.Values = append(values.Values, gexfAttValue{For: ids[name], Value: (*attrs)[name]})
+	}
+
+	return values
+}
+
+// declareGEXFAttributes declares the string attributes of class with names.
+// It returns the declarations and the IDs of the attributes by name.
+func declareGEXFAttributes(class string, names []string) (gexfAttributes, map[string]string) {
+	var (
+		attributes = gexfAttributes{Class: class}
+		ids        = make(map[string]string)
+	)
+
+	for i, name := range names {
+		ids[name] = strconv.Itoa(i)
+		attributes.Attributes = append(attributes.Attributes, gexfAttribute{ID: ids[name], Title: name, Type: "string"})
+	}
+
+	return attributes, ids
+}
@@ -0,0 +1,158 @@
+package interchange
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/ellescotz/graph_backend/pkg/core"
+)
+
+func TestReadGEXF(t *testing.T) {
+	t.Parallel()
+
+	nodeA := core.Node{Name: "A"}
+	nodeB := core.Node{Name: "B"}
+	nodeC := core.Node{Name: "C"}
+
+	edgeAB := core.Edge{
+		Nodes:  [2]core.Node{nodeA, nodeB},
+		Weight: 2.5,
+	}
+
+	edgeBC := core.Edge{
+		Nodes:  [2]core.Node{nodeB, nodeC},
+		Weight: 1,
+	}
+
+	edgeCB := core.Edge{
+		Nodes:  [2]core.Node{nodeC, nodeB},
+		Weight: 1,
+	}
+
+	// Case 1: attributes, defaults, labels, positions and mutual edges
+	graph, err := ReadGEXF(strings.NewReader(`<?xml version="1.0" encoding="UTF-8"?>
+		<gexf xmlns="http://gexf.net/1.2draft" xmlns:viz="http://gexf.net/1.2draft/viz" version="1.2">
+			<graph defaultedgetype="directed">
+				<attributes class="node">
+					<attribute id="0" title="color" type="string"><default>yellow</default></attribute>
+				</attributes>
+				<nodes>
+					<node id="A" label="Start"><attvalues><attvalue for="0" value="blue"/></attvalues><viz:position x="1" y="2" z="0"/></node>
+					<node id="B" label="B"/>
+					<node id="C"/>
+				</nodes>
+				<edges>
+					<edge id="0" source="A" target="B" weight="2.5" label="M1"/>
+					<edge id="1" source="B" target="C" type="mutual"/>
+				</edges>
+			</graph>
+		</gexf>`))
+	if err != nil {
+		t.Fatalf("ReadGEXF did not work. Got error %v", err)
+	}
+
+	if len(graph.Nodes) != 3 {
+		t.Errorf("ReadGEXF did not work. Got %v instead of %v", len(graph.Nodes), 3)
+	}
+
+	expectedEdges := []core.Edge{edgeAB, edgeBC, edgeCB}
+
+	if len(graph.Edges) != len(expectedEdges) {
+		t.Fatalf("ReadGEXF did not work. Got %v instead of %v", graph.Edges, expectedEdges)
+	}
+
+	for i := range expectedEdges {
+		if !graph.Edges[i].Equals(&expectedEdges[i]) {
+			t.Errorf("ReadGEXF did not work. Got %v instead of %v", graph.Edges[i], expectedEdges[i])
+		}
+	}
+
+	if color, _ := graph.Nodes[0].Attribute("color"); color != "blue" {
+		t.Errorf("ReadGEXF did not work. Got %v instead of %v", color, "blue")
+	}
+
+	if color, _ := graph.Nodes[2].Attribute("color"); color != "yellow" {
+		t.Errorf("ReadGEXF did not work. Got %v instead of %v", color, "yellow")
+	}
+
+	if label, _ := graph.Nodes[0].Attribute("label"); label != "Start" {
+		t.Errorf("ReadGEXF did not work. Got %v instead of %v", label, "Start")
+	}
+
+	if _, ok := graph.Nodes[1].Attribute("label"); ok {
+		t.Errorf("ReadGEXF did not work. Label equal to the id kept as attribute")
+	}
+
+	if label, _ := graph.Edges[0].Attribute("label"); label != "M1" {
+		t.Errorf("ReadGEXF did not work. Got %v instead of %v", label, "M1")
+	}
+
+	if position := graph.Nodes[0].Position; position == nil || *position != (core.Position{X: 1, Y: 2}) {
+		t.Errorf("ReadGEXF did not work. Got %v instead of %v", position, core.Position{X: 1, Y: 2})
+	}
+
+	// Case 2: malformed documents
+	for _, document := range []string{
+		`<gexf><graph>`,
+		`<gexf><graph><nodes><node id="A"/></nodes><edges><edge id="0" source="A" target="B"/></edges></graph></gexf>`,
+		`<gexf><graph><nodes><node id="A"/><node id="B"/></nodes><edges><edge id="0" source="A" target="B" weight="x"/></edges></graph></gexf>`,
+	} {
+		if _, err = ReadGEXF(strings.NewReader(document)); err == nil {
+			t.Errorf("ReadGEXF did not work for %v. Got no error", document)
+		}
+	}
+}
+
+func TestWriteGEXF(t *testing.T) {
+	t.Parallel()
+
+	nodeA := core.Node{Name: "A", Position: &core.Position{X: 1, Y: 2}}
+	nodeB := core.Node{Name: "B"}
+
+	nodeA.SetAttribute("color", "blue")
+
+	edgeAB := core.Edge{
+		Nodes:  [2]core.Node{nodeA, nodeB},
+		Weight: -2.5,
+	}
+
+	edgeAB.SetAttribute("color", "red")
+
+	graph := core.Graph{
+		Nodes: []core.Node{nodeA, nodeB},
+		Edges: []core.Edge{edgeAB},
+	}
+
+	var buffer bytes.Buffer
+
+	if err := WriteGEXF(&buffer, graph); err != nil {
+		t.Fatalf("WriteGEXF did not work. Got error %v", err)
+	}
+
+	if !strings.Contains(buffer.String(), `<viz:position x="1" y="2"></viz:position>`) {
+		t.Errorf("WriteGEXF did not work. Got %v", buffer.String())
+	}
+
+	// Writing and reading gives back the graph
+	read, err := ReadGEXF(&buffer)
+	if err != nil {
+		t.Fatalf("ReadGEXF did not work. Got error %v", err)
+	}
+
+	if len(read.Nodes) != 2 || len(read.Edges) != 1 || !read.Edges[0].Equals(&edgeAB) {
+		t.Fatalf("WriteGEXF did not work. Got %v instead of %v", read, graph)
+	}
+
+	if position := read.Nodes[0].Position; position == nil || *position != *nodeA.Position {
+		t.Errorf("WriteGEXF did not work. Got %v instead of %v", position, nodeA.Position)
+	}
+
+	if color, _ := read.Nodes[0].Attribute("color"); color != "blue" {
+		t.Errorf("WriteGEXF did not work. Got %v instead of %v", color, "blue")
+	}
+
+	if color, _ := read.Edges[0].Attribute("color"); color != "red" {
+		t.Errorf("WriteGEXF did not work. Got %v instead of %v", color, "red")
+	}
+}
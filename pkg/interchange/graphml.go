@@ -0,0 +1,348 @@
+package interchange
+
+import (
+	"encoding/xml"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ellescotz/graph_backend/pkg/core"
+)
+
+// GraphMLMediaType is the media type of GraphML documents.
+const GraphMLMediaType string = "application/graphml+xml"
+
+// graphMLNamespace is the XML namespace of GraphML.
+const graphMLNamespace string = "http://graphml.graphdrawing.org/xmlns"
+
+// GraphML attribute names with special meaning.
+const (
+	graphMLWeight string = "weight" // edge weight
+	graphMLX      string = "x"      // node position
+	graphMLY      string = "y"      // node position
+)
+
+// graphMLDocument represents a GraphML document.
+type graphMLDocument struct {
+	XMLName xml.Name       `xml:"graphml"`
+	Xmlns   string         `xml:"xmlns,attr,omitempty"`
+	Keys    []graphMLKey   `xml:"key"`
+	Graphs  []graphMLGraph `xml:"graph"`
+}
+
+// graphMLKey represents the declaration of a GraphML attribute.
+type graphMLKey struct {
+	ID      string  `xml:"id,attr"`
+	For     string  `xml:"for,attr"` // node, edge, graph or all
+	Name    string  `xml:"attr.name,attr,omitempty"`
+	Type    string  `xml:"attr.type,attr,omitempty"`
+	Default *string `xml:"default"`
+}
+
+// graphMLGraph represents a GraphML graph.
+type graphMLGraph struct {
+	ID          string        `xml:"id,attr,omitempty"`
+	EdgeDefault string        `xml:"edgedefault,attr"` // directed or undirected
+	Nodes       []graphMLNode `xml:"node"`
+	Edges       []graphMLEdge `xml:"edge"`
+}
+
+// graphMLNode represents a GraphML node.
+type graphMLNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphMLData `xml:"data"`
+}
+
+// graphMLEdge represents a GraphML edge.
+type graphMLEdge struct {
+	ID       string        `xml:"id,attr,omitempty"`
+	Source   string        `xml:"source,attr"`
+	Target   string        `xml:"target,attr"`
+	Directed string        `xml:"directed,attr,omitempty"` // overrides the edgedefault of the graph
+	Data     []graphMLData `xml:"data"`
+}
+
+// graphMLData represents the value of a GraphML attribute.
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// ReadGraphML reads the first graph of a GraphML document from r.
+// Node IDs become node names. Declared attributes become node and edge attributes named by their attr.name,
+// with their default values applied. The "weight" edge attribute is taken as the edge weight, edges without it weigh 1.
+// The "x" and "y" node attributes are taken as the position of the node.
+// Edges of undirected graphs become edges in both directions. Repeated edges are merged.
+func ReadGraphML(r io.Reader) (core.Graph, error) {
+	var (
+		document graphMLDocument
+		b        = newBuilder("graphml")
+	)
+
+	if err := xml.NewDecoder(r).Decode(&document); err != nil {
+		return core.Graph{}, b.errorf("%v", err)
+	}
+
+	if len(document.Graphs) == 0 {
+		return core.Graph{}, b.errorf("no graph")
+	}
+
+	graph := document.Graphs[0]
+
+	// Keys by ID, and the keys applying to nodes and to edges
+	var (
+		keys     = make(map[string]graphMLKey)
+		nodeKeys []graphMLKey
+		edgeKeys []graphMLKey
+		keyName  = func(key graphMLKey) string {
+			if len(key.Name) == 0 {
+				return key.ID
+			}
+
+			return key.Name
+		}
+	)
+
+	for _, key := range document.Keys {
+		keys[key.ID] = key
+
+		switch key.For {
+		case "node":
+			nodeKeys = append(nodeKeys, key)
+		case "edge":
+			edgeKeys = append(edgeKeys, key)
+		case "all":
+			nodeKeys = append(nodeKeys, key)
+			edgeKeys = append(edgeKeys, key)
+		}
+	}
+
+	// values returns the attribute values of data with defaults of declared keys.
+	values := func(data []graphMLData, declared []graphMLKey) map[string]string {
+		values := make(map[string]string)
+
+		for _, key := range declared {
+			if key.Default != nil {
+				values[keyName(key)] = *key.Default
+			}
+		}
+
+		for _, d := range data {
+			key, ok := keys[d.Key]
+			if !ok {
+				key = graphMLKey{ID: d.Key}
+			}
+
+			values[keyName(key)] = d.Value
+		}
+
+		return values
+	}
+
+	for _, gn := range graph.Nodes {
+		var (
+			n  = core.Node{Name: gn.ID}
+			xy []float64
+		)
+
+		for name, value := range values(gn.Data, nodeKeys) {
+			n.SetAttribute(name, value)
+		}
+
+		for _, name := range []string{graphMLX, graphMLY} {
+			if value, ok := n.Attribute(name); ok {
+				if coordinate, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+					xy = append(xy, coordinate)
+				}
+			}
+		}
+
+		if len(xy) == 2 {
+			n.Position = &core.Position{X: xy[0], Y: xy[1]}
+			delete(*n.Attributes, graphMLX)
+			delete(*n.Attributes, graphMLY)
+		}
+
+		if n.Attributes != nil && len(*n.Attributes) == 0 {
+			n.Attributes = nil
+		}
+
+		if err := b.addNode(n); err != nil {
+			return core.Graph{}, err
+		}
+	}
+
+	for _, ge := range graph.Edges {
+		e := core.Edge{Weight: defaultWeight}
+
+		e.Nodes[0].Name = ge.Source
+		e.Nodes[1].Name = ge.Target
+
+		for name, value := range values(ge.Data, edgeKeys) {
+			if name != graphMLWeight {
+				e.SetAttribute(name, value)
+				continue
+			}
+
+			weight, err := b.parseWeight(value)
+			if err != nil {
+				return core.Graph{}, err
+			}
+
+			e.Weight = weight
+		}
+
+		directed := graph.EdgeDefault != "undirected"
+
+		if len(ge.Directed) != 0 {
+			directed = ge.Directed == "true"
+		}
+
+		if err := b.addEdge(e, directed); err != nil {
+			return core.Graph{}, err
+		}
+	}
+
+	return b.graph, nil
+}
+
+// WriteGraphML writes g to w as a directed GraphML graph.
+// Node and edge attributes are declared as string attributes, edge weights as the "weight" double attribute,
+// and node positions as the "x" and "y" double attributes.
+func WriteGraphML(w io.Writer, g core.Graph) error {
+	document := graphMLDocument{
+		Xmlns: graphMLNamespace,
+		Keys: []graphMLKey{
+			{ID: graphMLWeight, For: "edge", Name: graphMLWeight, Type: "double"},
+		},
+		Graphs: []graphMLGraph{{
+			ID:          "G",
+			EdgeDefault: "directed",
+		}},
+	}
+
+	graph := &document.Graphs[0]
+
+	// Declaring attributes, node and edge attributes get separate IDs as their names can collide
+	var (
+		nodeNames, edgeNames, positions = attributeNames(g)
+		nodeKeys                        = make(map[string]string)
+		edgeKeys                        = make(map[string]string)
+	)
+
+	if positions {
+		document.Keys = append(document.Keys,
+			graphMLKey{ID: graphMLX, For: "node", Name: graphMLX, Type: "double"},
+			graphMLKey{ID: graphMLY, For: "node", Name: graphMLY, Type: "double"},
+		)
+	}
+
+	for i, name := range nodeNames {
+		nodeKeys[name] = "n" + strconv.Itoa(i)
+		document.Keys = append(document.Keys, graphMLKey{ID: nodeKeys[name], For: "node", Name: name, Type: "string"})
+	}
+
+	for i, name := range edgeNames {
+		edgeKeys[name] = "e" + strconv.Itoa(i)
+		document.Keys = append(document.Keys, graphMLKey{ID: edgeKeys[name], For: "edge", Name: name, Type: "string"})
+	}
+
+	for _, n := range g.AllNodes() {
+		gn := graphMLNode{ID: n.Name}
+
+		if n.Position != nil {
+			gn.Data = append(gn.Data,
+				graphMLData{Key: graphMLX, Value: formatFloat(n.Position.X)},
+				graphMLData{Key: graphMLY, Value: formatFloat(n.Position.Y)},
+			)
+		}
+
+		for _, name := range sortedKeys(n.Attributes) {
+			gn.Data = append(gn.Data, graphMLData{Key: nodeKeys[name], Value: (*n.Attributes)[name]})
+		}
+
+		graph.Nodes = append(graph.Nodes, gn)
+	}
+
+	for _, e := range g.Edges {
+		ge := graphMLEdge{
+			Source: e.Nodes[0].Name,
+			Target: e.Nodes[1].Name,
+			Data:   []graphMLData{{Key: graphMLWeight, Value: formatFloat(e.Weight)}},
+		}
+
+		for _, name := range sortedKeys(e.Attributes) {
+			ge.Data = append(ge.Data, graphMLData{Key: edgeKeys[name], Value: (*e.Attributes)[name]})
+		}
+
+		graph.Edges = append(graph.Edges, ge)
+	}
+
+	return writeXML(w, document)
+}
+
+// writeXML writes document to w as an indented XML document.
+func writeXML(w io.Writer, document interface{}) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+
+	if err := encoder.Encode(document); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+
+	return err
+}
+
+// attributeNames returns the sorted names of the node and edge attributes of g,
+// and whether any node of g has a position.
+func attributeNames(g core.Graph) (nodeNames, edgeNames []string, positions bool) {
+	var (
+		nodeSet = core.Attributes{}
+		edgeSet = core.Attributes{}
+	)
+
+	for _, n := range g.Nodes {
+		positions = positions || n.Position != nil
+
+		for _, name := range sortedKeys(n.Attributes) {
+			nodeSet[name] = ""
+		}
+	}
+
+	for _, e := range g.Edges {
+		for _, name := range sortedKeys(e.Attributes) {
+			edgeSet[name] = ""
+		}
+	}
+
+	return sortedKeys(&nodeSet), sortedKeys(&edgeSet), positions
+}
+
+// sortedKeys returns the sorted keys of attrs.
+func sortedKeys(attrs *core.Attributes) []string {
+	if attrs == nil {
+		return nil
+	}
+
+	keys := make([]string, 0, len(*attrs))
+
+	for key := range *attrs {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// formatFloat returns the shortest representation of f.
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
@@ -0,0 +1,152 @@
+package interchange
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/ellescotz/graph_backend/pkg/core"
+)
+
+func TestReadGraphML(t *testing.T) {
+	t.Parallel()
+
+	nodeA := core.Node{Name: "A"}
+	nodeB := core.Node{Name: "B"}
+	nodeC := core.Node{Name: "C"}
+
+	edgeAB := core.Edge{
+		Nodes:  [2]core.Node{nodeA, nodeB},
+		Weight: 2.5,
+	}
+
+	edgeBC := core.Edge{
+		Nodes:  [2]core.Node{nodeB, nodeC},
+		Weight: 1,
+	}
+
+	edgeCB := core.Edge{
+		Nodes:  [2]core.Node{nodeC, nodeB},
+		Weight: 1,
+	}
+
+	// Case 1: attributes, defaults, positions and undirected edges
+	graph, err := ReadGraphML(strings.NewReader(`<?xml version="1.0" encoding="UTF-8"?>
+		<graphml xmlns="http://graphml.graphdrawing.org/xmlns">
+			<key id="d0" for="node" attr.name="color" attr.type="string"><default>yellow</default></key>
+			<key id="d1" for="edge" attr.name="weight" attr.type="double"><default>1</default></key>
+			<key id="d2" for="node" attr.name="x" attr.type="double"/>
+			<key id="d3" for="node" attr.name="y" attr.type="double"/>
+			<key id="d4" for="edge" attr.name="road" attr.type="string"/>
+			<graph id="G" edgedefault="directed">
+				<node id="A"><data key="d0">blue</data><data key="d2">1</data><data key="d3">2</data></node>
+				<node id="B"/>
+				<node id="C"/>
+				<edge source="A" target="B"><data key="d1">2.5</data><data key="d4">M1</data></edge>
+				<edge source="B" target="C" directed="false"/>
+			</graph>
+		</graphml>`))
+	if err != nil {
+		t.Fatalf("ReadGraphML did not work. Got error %v", err)
+	}
+
+	if len(graph.Nodes) != 3 {
+		t.Errorf("ReadGraphML did not work. Got %v instead of %v", len(graph.Nodes), 3)
+	}
+
+	expectedEdges := []core.Edge{edgeAB, edgeBC, edgeCB}
+
+	if len(graph.Edges) != len(expectedEdges) {
+		t.Fatalf("ReadGraphML did not work. Got %v instead of %v", graph.Edges, expectedEdges)
+	}
+
+	for i := range expectedEdges {
+		if !graph.Edges[i].Equals(&expectedEdges[i]) {
+			t.Errorf("ReadGraphML did not work. Got %v instead of %v", graph.Edges[i], expectedEdges[i])
+		}
+	}
+
+	if color, _ := graph.Nodes[0].Attribute("color"); color != "blue" {
+		t.Errorf("ReadGraphML did not work. Got %v instead of %v", color, "blue")
+	}
+
+	if color, _ := graph.Nodes[1].Attribute("color"); color != "yellow" {
+		t.Errorf("ReadGraphML did not work. Got %v instead of %v", color, "yellow")
+	}
+
+	if position := graph.Nodes[0].Position; position == nil || *position != (core.Position{X: 1, Y: 2}) {
+		t.Errorf("ReadGraphML did not work. Got %v instead of %v", position, core.Position{X: 1, Y: 2})
+	}
+
+	if road, _ := graph.Edges[0].Attribute("road"); road != "M1" {
+		t.Errorf("ReadGraphML did not work. Got %v instead of %v", road, "M1")
+	}
+
+	// Case 2: malformed documents
+	for _, document := range []string{
+		`<graphml><graph>`,
+		`<graphml></graphml>`,
+		`<graphml><graph><node id="A"/><edge source="A" target="B"/></graph></graphml>`,
+		`<graphml><graph><node id="A"/><edge source="A" target="A"/></graph></graphml>`,
+		`<graphml><graph><node id="A"/><node id="A"/></graph></graphml>`,
+		`<graphml><key id="w" for="edge" attr.name="weight"/><graph><node id="A"/><node id="B"/>` +
+			`<edge source="A" target="B"><data key="w">heavy</data></edge></graph></graphml>`,
+	} {
+		if _, err = ReadGraphML(strings.NewReader(document)); err == nil {
+			t.Errorf("ReadGraphML did not work for %v. Got no error", document)
+		}
+	}
+}
+
+func TestWriteGraphML(t *testing.T) {
+	t.Parallel()
+
+	nodeA := core.Node{Name: "A", Position: &core.Position{X: 1, Y: 2}}
+	nodeB := core.Node{Name: "B"}
+
+	nodeA.SetAttribute("color", "blue")
+
+	edgeAB := core.Edge{
+		Nodes:  [2]core.Node{nodeA, nodeB},
+		Weight: -2.5,
+	}
+
+	edgeAB.SetAttribute("color", "red")
+
+	graph := core.Graph{
+		Nodes: []core.Node{nodeA, nodeB},
+		Edges: []core.Edge{edgeAB},
+	}
+
+	var buffer bytes.Buffer
+
+	if err := WriteGraphML(&buffer, graph); err != nil {
+		t.Fatalf("WriteGraphML did not work. Got error %v", err)
+	}
+
+	// Writing and reading gives back the graph
+	read, err := ReadGraphML(&buffer)
+	if err != nil {
+		t.Fatalf("ReadGraphML did not work. Got error %v", err)
+	}
+
+	if len(read.Nodes) != 2 || len(read.Edges) != 1 || !read.Edges[0].Equals(&edgeAB) {
+		t.Fatalf("WriteGraphML did not work. Got %v instead of %v", read, graph)
+	}
+
+	if position := read.Nodes[0].Position; position == nil || *position != *nodeA.Position {
+		t.Errorf("WriteGraphML did not work. Got %v instead of %v", position, nodeA.Position)
+	}
+
+	if color, _ := read.Nodes[0].Attribute("color"); color != "blue" {
+		t.Errorf("WriteGraphML did not work. Got %v instead of %v", color, "blue")
+	}
+
+	if color, _ := read.Edges[0].Attribute("color"); color != "red" {
+		t.Errorf("WriteGraphML did not work. Got %v instead of %v", color, "red")
+	}
+
+	if read.Nodes[1].Attributes != nil {
+		t.Errorf("WriteGraphML did not work. Got %v instead of %v", *read.Nodes[1].Attributes, nil)
+	}
+}
@@ -0,0 +1,269 @@
+// Package interchange reads and writes graphs in the GraphML and GEXF interchange formats,
+// and turns analysis results into graphs which can be written in any of them.
+package interchange
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ellescotz/graph_backend/pkg/core"
+)
+
+// Attributes annotating the results of analyses.
+const (
+	PathsAttribute           string = "paths"           // numbers of the paths containing a node or an edge
+	StrongComponentAttribute string = "strongComponent" // number of the strongly connected component of a node
+	WeakComponentAttribute   string = "weakComponent"   // number of the weakly connected component of a node
+	FlowAttribute            string = "flow"            // flow of an edge
+	MinCutAttribute          string = "minCut"          // "true" for edges of the minimum cut
+	NextHopAttribute         string = "nextHop"         // next hop on the lowest weighted path
+)
+
+// defaultWeight is the weight of edges without weight.
+const defaultWeight float64 = 1
+
+// edgeKey identifies the edges equal to each other: between the same nodes, with the same weight.
+type edgeKey struct {
+	from, to string
+	weight   float64
+}
+
+// keyOf returns the key of e.
+func keyOf(e *core.Edge) edgeKey {
+	return edgeKey{from: e.Nodes[0].Name, to: e.Nodes[1].Name, weight: e.Weight}
+}
+
+// builder builds a core.Graph from the nodes and edges of a document.
+type builder struct {
+	format string
+	graph  core.Graph
+	nodes  map[string]bool
+	edges  map[edgeKey]bool
+}
+
+// newBuilder returns a builder of an empty graph, reporting errors with the name of format.
+func newBuilder(format string) *builder {
+	return &builder{
+		format: format,
+		graph: core.Graph{
+			Nodes: []core.Node{},
+			Edges: []core.Edge{},
+		},
+		nodes: make(map[string]bool),
+		edges: make(map[edgeKey]bool),
+	}
+}
+
+// errorf returns an error with the formatted message, prefixed by the name of the format.
+func (b *builder) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf(b.format+": "+format, args...)
+}
+
+// addNode adds n to the graph.
+// It returns an error if the graph has a node with the same name already.
+func (b *builder) addNode(n core.Node) error {
+	if len(n.Name) == 0 {
+		return b.errorf("node without id")
+	}
+
+	if b.nodes[n.Name] {
+		return b.errorf("duplicate node %q", n.Name)
+	}
+
+	b.nodes[n.Name] = true
+	b.graph.Nodes = append(b.graph.Nodes, n)
+
+	return nil
+}
+
+// addEdge adds e to the graph, and the edge in reverse direction as well if e is undirected.
+// Repeated edges are merged.
+// It returns an error if e is a self-loop or any of its nodes is unknown.
+func (b *builder) addEdge(e core.Edge, directed bool) error {
+	for _, n := range e.Nodes {
+		if !b.nodes[n.Name] {
+			return b.errorf("edge from %q to %q: unknown node %q", e.Nodes[0].Name, e.Nodes[1].Name, n.Name)
+		}
+	}
+
+	if e.Nodes[0].Equals(e.Nodes[1]) {
+		return b.errorf("self-loop on node %q is not supported", e.Nodes[0].Name)
+	}
+
+	edges := []core.Edge{e}
+
+	if !directed {
+		reverse := e
+
+		reverse.Nodes[0], reverse.Nodes[1] = e.Nodes[1], e.Nodes[0]
+		edges = append(edges, reverse)
+	}
+
+	for _, e := range edges {
+		key := keyOf(&e)
+
+		if !b.edges[key] {
+			b.edges[key] = true
+			b.graph.Edges = append(b.graph.Edges, e)
+		}
+	}
+
+	return nil
+}
+
+// parseWeight parses the weight of an edge.
+func (b *builder) parseWeight(value string) (float64, error) {
+	weight, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return 0, b.errorf("malformed weight %q", value)
+	}
+
+	return weight, nil
+}
+
+// withAttribute returns a copy of attrs with the attribute key set to value.
+// attrs itself is left untouched, as it can be shared between copies of a graph.
+func withAttribute(attrs *core.Attributes, key, value string) *core.Attributes {
+	copied := core.Attributes{}
+
+	if attrs != nil {
+		for k, v := range *attrs {
+			copied[k] = v
+		}
+	}
+
+	copied[key] = value
+
+	return &copied
+}
+
+// AnnotatePaths returns a copy of g whose nodes and edges on any of paths have the PathsAttribute attribute,
+// listing the numbers of the paths containing them, starting from 1 and separated by spaces.
+func AnnotatePaths(g core.Graph, paths []core.Path) core.Graph {
+	var (
+		annotated = g.Copy()
+		nodes     = make(map[string][]string)  // numbers of the paths by node name
+		edges     = make(map[edgeKey][]string) // numbers of the paths by edge
+	)
+
+	for p := range paths {
+		var (
+			number = strconv.Itoa(p + 1)
+			seen   = make(map[edgeKey]bool)
+		)
+
+		for _, n := range paths[p].Subgraph.AllNodes() {
+			nodes[n.Name] = append(nodes[n.Name], number)
+		}
+
+		for j := range paths[p].Subgraph.Edges {
+			if key := keyOf(&paths[p].Subgraph.Edges[j]); !seen[key] {
+				seen[key] = true
+				edges[key] = append(edges[key], number)
+			}
+		}
+	}
+
+	for i := range annotated.Nodes {
+		if numbers := nodes[annotated.Nodes[i].Name]; len(numbers) != 0 {
+			annotated.Nodes[i].Attributes = withAttribute(annotated.Nodes[i].Attributes, PathsAttribute, strings.Join(numbers, " "))
+		}
+	}
+
+	for i := range annotated.Edges {
+		if numbers := edges[keyOf(&annotated.Edges[i])]; len(numbers) != 0 {
+			annotated.Edges[i].Attributes = withAttribute(annotated.Edges[i].Attributes, PathsAttribute, strings.Join(numbers, " "))
+		}
+	}
+
+	return annotated
+}
+
+// AnnotateComponents returns a copy of g whose nodes have the StrongComponentAttribute and WeakComponentAttribute
+// attributes, holding the indices of their components in components.
+func AnnotateComponents(g core.Graph, components core.Components) core.Graph {
+	var (
+		annotated = g.Copy()
+		strong    = make(map[string]int)
+		weak      = make(map[string]int)
+	)
+
+	for i, component := range components.Strong {
+		for _, n := range component {
+			strong[n.Name] = i
+		}
+	}
+
+	for i, component := range components.Weak {
+		for _, n := range component {
+			weak[n.Name] = i
+		}
+	}
+
+	for i := range annotated.Nodes {
+		n := &annotated.Nodes[i]
+
+		n.Attributes = withAttribute(n.Attributes, StrongComponentAttribute, strconv.Itoa(strong[n.Name]))
+		n.Attributes = withAttribute(n.Attributes, WeakComponentAttribute, strconv.Itoa(weak[n.Name]))
+	}
+
+	return annotated
+}
+
+// AnnotateFlow returns a copy of g whose edges have the FlowAttribute attribute holding their flow,
+// and whose edges of the minimum cut have the MinCutAttribute attribute set to "true".
+// flow is the flow found in g by g.MaxFlow: its edges are in the order of the edges of g.
+func AnnotateFlow(g core.Graph, flow core.Flow) core.Graph {
+	var (
+		annotated = g.Copy()
+		minCut    = make(map[edgeKey]bool, len(flow.MinCut.Edges))
+	)
+
+	for i := range flow.MinCut.Edges {
+		minCut[keyOf(&flow.MinCut.Edges[i])] = true
+	}
+
+	for i := range annotated.Edges {
+		e := &annotated.Edges[i]
+
+		if i < len(flow.Edges) {
+			e.Attributes = withAttribute(e.Attributes, FlowAttribute, strconv.FormatFloat(flow.Edges[i].Flow, 'g', -1, 64))
+		}
+
+		if minCut[keyOf(e)] {
+			e.Attributes = withAttribute(e.Attributes, MinCutAttribute, "true")
+		}
+	}
+
+	return annotated
+}
+
+// AllPairsGraph returns the graph of the lowest weights in allPairs:
+// it has an edge weighing the lowest summed weight from every node to every other node reachable from it,
+// with the NextHopAttribute attribute holding the next hop on the corresponding path.
+func AllPairsGraph(allPairs core.AllPairsPaths) core.Graph {
+	graph := core.Graph{
+		Nodes: append([]core.Node{}, allPairs.Nodes...),
+		Edges: []core.Edge{},
+	}
+
+	for i := range allPairs.Nodes {
+		for j := range allPairs.Nodes {
+			if i == j || allPairs.NextHops[i][j] < 0 {
+				continue
+			}
+
+			e := core.Edge{
+				Nodes:  [2]core.Node{{Name: allPairs.Nodes[i].Name}, {Name: allPairs.Nodes[j].Name}},
+				Weight: allPairs.Distances[i][j],
+			}
+
+			e.SetAttribute(NextHopAttribute, allPairs.Nodes[allPairs.NextHops[i][j]].Name)
+
+			graph.Edges = append(graph.Edges, e)
+		}
+	}
+
+	return graph
+}
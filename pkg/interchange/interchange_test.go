@@ -0,0 +1,190 @@
+package interchange
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ellescotz/graph_backend/pkg/core"
+)
+
+func TestAnnotatePaths(t *testing.T) {
+	t.Parallel()
+
+	nodeA := core.Node{Name: "A"}
+	nodeB := core.Node{Name: "B"}
+	nodeC := core.Node{Name: "C"}
+
+	edgeAB := core.Edge{
+		Nodes:  [2]core.Node{nodeA, nodeB},
+		Weight: 1,
+	}
+
+	edgeBC := core.Edge{
+		Nodes:  [2]core.Node{nodeB, nodeC},
+		Weight: 2,
+	}
+
+	edgeAC := core.Edge{
+		Nodes:  [2]core.Node{nodeA, nodeC},
+		Weight: 3,
+	}
+
+	nodeA.SetAttribute("color", "blue")
+
+	graph := core.Graph{
+		Nodes: []core.Node{nodeA, nodeB, nodeC},
+		Edges: []core.Edge{edgeAB, edgeBC, edgeAC},
+	}
+
+	paths := []core.Path{
+		{Subgraph: core.Graph{Nodes: []core.Node{nodeA, nodeB, nodeC}, Edges: []core.Edge{edgeAB, edgeBC}}, Weight: 3},
+		{Subgraph: core.Graph{Nodes: []core.Node{nodeA, nodeC}, Edges: []core.Edge{edgeAC}}, Weight: 3},
+	}
+
+	annotated := AnnotatePaths(graph, paths)
+
+	expectedNodes := []string{"1 2", "1", "1 2"}
+
+	for i, expected := range expectedNodes {
+		if value, _ := annotated.Nodes[i].Attribute(PathsAttribute); value != expected {
+			t.Errorf("AnnotatePaths did not work. Got %v instead of %v", value, expected)
+		}
+	}
+
+	expectedEdges := []string{"1", "1", "2"}
+
+	for i, expected := range expectedEdges {
+		if value, _ := annotated.Edges[i].Attribute(PathsAttribute); value != expected {
+			t.Errorf("AnnotatePaths did not work. Got %v instead of %v", value, expected)
+		}
+	}
+
+	// The original graph is left untouched
+	if _, ok := graph.Nodes[0].Attribute(PathsAttribute); ok || graph.Edges[0].Attributes != nil {
+		t.Errorf("AnnotatePaths did not work. Original graph modified")
+	}
+
+	if color, _ := annotated.Nodes[0].Attribute("color"); color != "blue" {
+		t.Errorf("AnnotatePaths did not work. Got %v instead of %v", color, "blue")
+	}
+}
+
+func TestAnnotateComponentsAndFlow(t *testing.T) {
+	t.Parallel()
+
+	nodeA := core.Node{Name: "A"}
+	nodeB := core.Node{Name: "B"}
+	nodeC := core.Node{Name: "C"}
+
+	edgeAB := core.Edge{
+		Nodes:  [2]core.Node{nodeA, nodeB},
+		Weight: 1,
+	}
+
+	edgeBA := core.Edge{
+		Nodes:  [2]core.Node{nodeB, nodeA},
+		Weight: 1,
+	}
+
+	edgeBC := core.Edge{
+		Nodes:  [2]core.Node{nodeB, nodeC},
+		Weight: 2,
+	}
+
+	graph := core.Graph{
+		Nodes: []core.Node{nodeA, nodeB, nodeC},
+		Edges: []core.Edge{edgeAB, edgeBA, edgeBC},
+	}
+
+	// Components
+	components := graph.Components()
+	annotated := AnnotateComponents(graph, components)
+
+	strongA, _ := annotated.Nodes[0].Attribute(StrongComponentAttribute)
+	strongB, _ := annotated.Nodes[1].Attribute(StrongComponentAttribute)
+	strongC, _ := annotated.Nodes[2].Attribute(StrongComponentAttribute)
+
+	if strongA != strongB || strongA == strongC {
+		t.Errorf("AnnotateComponents did not work. Got %v, %v, %v", strongA, strongB, strongC)
+	}
+
+	if weak, _ := annotated.Nodes[2].Attribute(WeakComponentAttribute); weak != "0" {
+		t.Errorf("AnnotateComponents did not work. Got %v instead of %v", weak, "0")
+	}
+
+	// Flow
+	flow, err := graph.MaxFlow(nodeA, nodeC)
+	if err != nil {
+		t.Fatalf("MaxFlow did not work. Got error %v", err)
+	}
+
+	annotated = AnnotateFlow(graph, flow)
+
+	if value, _ := annotated.Edges[0].Attribute(FlowAttribute); value != "1" {
+		t.Errorf("AnnotateFlow did not work. Got %v instead of %v", value, "1")
+	}
+
+	if cut, _ := annotated.Edges[0].Attribute(MinCutAttribute); cut != "true" {
+		t.Errorf("AnnotateFlow did not work. Got %v instead of %v", cut, "true")
+	}
+
+	if _, ok := annotated.Edges[2].Attribute(MinCutAttribute); ok {
+		t.Errorf("AnnotateFlow did not work. Edge %v in minimum cut", annotated.Edges[2])
+	}
+
+	// Equal parallel edges have their own flows
+	parallel := core.Graph{
+		Nodes: []core.Node{nodeA, nodeB, nodeC},
+		Edges: []core.Edge{edgeAB, edgeAB, {Nodes: [2]core.Node{nodeB, nodeC}, Weight: 1}},
+	}
+
+	flow, err = parallel.MaxFlow(nodeA, nodeC)
+	if err != nil {
+		t.Fatalf("MaxFlow did not work. Got error %v", err)
+	}
+
+	annotated = AnnotateFlow(parallel, flow)
+
+	first, _ := annotated.Edges[0].Attribute(FlowAttribute)
+	second, _ := annotated.Edges[1].Attribute(FlowAttribute)
+
+	if first+second != "10" && first+second != "01" {
+		t.Errorf("AnnotateFlow did not work. Got %v and %v instead of 1 and 0", first, second)
+	}
+}
+
+func TestAllPairsGraph(t *testing.T) {
+	t.Parallel()
+
+	nodeA := core.Node{Name: "A"}
+	nodeB := core.Node{Name: "B"}
+	nodeC := core.Node{Name: "C"}
+
+	graph := core.Graph{
+		Nodes: []core.Node{nodeA, nodeB, nodeC},
+		Edges: []core.Edge{
+			{Nodes: [2]core.Node{nodeA, nodeB}, Weight: 1},
+			{Nodes: [2]core.Node{nodeB, nodeC}, Weight: 2},
+		},
+	}
+
+	allPairs, err := graph.GenerateAllPairsPaths(context.Background())
+	if err != nil {
+		t.Fatalf("GenerateAllPairsPaths did not work. Got error %v", err)
+	}
+
+	distances := AllPairsGraph(allPairs)
+
+	// A->B, A->C, B->C
+	if len(distances.Edges) != 3 {
+		t.Fatalf("AllPairsGraph did not work. Got %v instead of %v", len(distances.Edges), 3)
+	}
+
+	for _, e := range distances.Edges {
+		if e.Nodes[0].Name == "A" && e.Nodes[1].Name == "C" {
+			if hop, _ := e.Attribute(NextHopAttribute); e.Weight != 3 || hop != "B" {
+				t.Errorf("AllPairsGraph did not work. Got %v", e)
+			}
+		}
+	}
+}
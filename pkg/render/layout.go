@@ -0,0 +1,386 @@
+package render
+
+import (
+	"context"
+	"math"
+	"sort"
+
+	"github.com/ellescotz/graph_backend/pkg/core"
+)
+
+// Parameters of the force-directed layout.
+const (
+	forceIterations  int     = 200
+	forceTemperature float64 = 0.1  // largest move of a node in an iteration, shrinking linearly
+	forceMinDistance float64 = 0.01 // keeps nodes at the same place apart
+)
+
+// maxForceNodes is the largest number of nodes laid out by the force-directed layout,
+// whose iterations cost the square of the number of nodes. Larger graphs are laid out on a circle.
+const maxForceNodes int = 1000
+
+// barycenterSweeps is the number of down and up sweeps ordering the nodes of the layered layout.
+const barycenterSweeps int = 4
+
+// layOut returns the positions of nodes, the nodes of g, according to layout.
+// Positions are in arbitrary units, with y growing downwards.
+// It returns ErrUnknownLayout for unknown layouts, and the error of ctx if it is done during the force-directed layout.
+func layOut(ctx context.Context, g core.Graph, nodes []core.Node, layout Layout) ([]point, error) {
+	// Edges by the indices of their nodes in nodes
+	var (
+		ids   = make(map[string]int, len(nodes))
+		edges = make([][2]int, 0, len(g.Edges))
+	)
+
+	for i, n := range nodes {
+		ids[n.Name] = i
+	}
+
+	for _, e := range g.Edges {
+		edges = append(edges, [2]int{ids[e.Nodes[0].Name], ids[e.Nodes[1].Name]})
+	}
+
+	if layout == LayoutAuto {
+		layout = LayoutForce
+
+		if len(nodes) != 0 && allPositioned(nodes) {
+			layout = LayoutPositions
+		}
+	}
+
+	switch layout {
+	case LayoutPositions:
+		return positionLayout(nodes), nil
+	case LayoutForce:
+		if len(nodes) > maxForceNodes {
+			return circularLayout(len(nodes)), nil
+		}
+
+		return forceLayout(ctx, len(nodes), edges)
+	case LayoutCircular:
+		return circularLayout(len(nodes)), nil
+	case LayoutLayered:
+		return layeredLayout(len(nodes), edges), nil
+	default:
+		return nil, ErrUnknownLayout
+	}
+}
+
+// allPositioned reports whether every node of nodes has a position.
+func allPositioned(nodes []core.Node) bool {
+	for _, n := range nodes {
+		if n.Position == nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+// circularLayout returns the positions of n nodes evenly spread on the unit circle in order,
+// starting at the top and running clockwise.
+func circularLayout(n int) []point {
+	positions := make([]point, n)
+
+	for i := range positions {
+		angle := 2*math.Pi*float64(i)/float64(n) - math.Pi/2
+
+		positions[i] = point{x: math.Cos(angle), y: math.Sin(angle)}
+	}
+
+	return positions
+}
+
+// positionLayout returns the positions of nodes, with y flipped as latitudes grow upwards.
+// Nodes without position are placed in order on a circle around the positioned nodes.
+func positionLayout(nodes []core.Node) []point {
+	var (
+		positions              = make([]point, len(nodes))
+		unpositioned           []int
+		minX, minY, maxX, maxY = math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)
+	)
+
+	for i, n := range nodes {
+		if n.Position == nil {
+			unpositioned = append(unpositioned, i)
+			continue
+		}
+
+		positions[i] = point{x: n.Position.X, y: -n.Position.Y}
+
+		minX, maxX = math.Min(minX, positions[i].x), math.Max(maxX, positions[i].x)
+		minY, maxY = math.Min(minY, positions[i].y), math.Max(maxY, positions[i].y)
+	}
+
+	if len(unpositioned) == 0 {
+		return positions
+	}
+
+	center, radius := point{}, 1.0
+
+	if len(unpositioned) != len(nodes) {
+		center = point{x: (minX + maxX) / 2, y: (minY + maxY) / 2}
+		radius = math.Max(math.Hypot(maxX-minX, maxY-minY)/2, 1)
+	}
+
+	for i, p := range circularLayout(len(unpositioned)) {
+		positions[unpositioned[i]] = point{x: center.x + p.x*radius, y: center.y + p.y*radius}
+	}
+
+	return positions
+}
+
+// forceLayout returns the positions of n nodes joined by edges after the force-directed algorithm of
+// Fruchterman and Reingold: nodes repel each other while edges, regardless of their direction, pull their nodes together.
+// Nodes start on a circle, so the layout is deterministic.
+// It returns the error of ctx if it is done, which is checked between iterations.
+func forceLayout(ctx context.Context, n int, edges [][2]int) ([]point, error) {
+	var (
+		positions    = circularLayout(n)
+		displacement = make([]point, n)
+		k            = math.Sqrt(1 / math.Max(float64(n), 1)) // ideal distance of nodes
+	)
+
+	for iteration := 0; iteration < forceIterations; iteration++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		temperature := forceTemperature * (1 - float64(iteration)/float64(forceIterations))
+
+		for i := range displacement {
+			displacement[i] = point{}
+		}
+
+		// Calculating repulsive forces
+		for i := 0; i < n; i++ {
+			for j := i + 1; j < n; j++ {
+				dx, dy := positions[i].x-positions[j].x, positions[i].y-positions[j].y
+				distance := math.Max(math.Hypot(dx, dy), forceMinDistance)
+				force := k * k / distance
+
+				displacement[i].x += dx / distance * force
+				displacement[i].y += dy / distance * force
+				displacement[j].x -= dx / distance * force
+				displacement[j].y -= dy / distance * force
+			}
+		}
+
+		// Calculating attractive forces
+		for _, e := range edges {
+			from, to := e[0], e[1]
+			dx, dy := positions[from].x-positions[to].x, positions[from].y-positions[to].y
+			distance := math.Max(math.Hypot(dx, dy), forceMinDistance)
+			force := distance * distance / k
+
+			displacement[from].x -= dx / distance * force
+			displacement[from].y -= dy / distance * force
+			displacement[to].x += dx / distance * force
+			displacement[to].y += dy / distance * force
+		}
+
+		// Moving nodes, at most by the temperature
+		for i := range positions {
+			length := math.Hypot(displacement[i].x, displacement[i].y)
+			if length == 0 {
+				continue
+			}
+
+			step := math.Min(length, temperature)
+
+			positions[i].x += displacement[i].x / length * step
+			positions[i].y += displacement[i].y / length * step
+		}
+	}
+
+	return positions, nil
+}
+
+// layeredLayout returns the positions of n nodes joined by edges after the layered approach of Sugiyama:
+// cycles are broken by reversing edges, nodes are assigned to layers so that edges point downwards,
+// and nodes of each layer are ordered by the barycenters of their neighbors to reduce crossings.
+func layeredLayout(n int, edges [][2]int) []point {
+	var (
+		acyclic = acyclicEdges(n, edges)
+		layers  = assignLayers(n, acyclic)
+		above   = make([][]int, n) // neighbors in the layers above
+		below   = make([][]int, n) // neighbors in the layers below
+	)
+
+	for _, e := range acyclic {
+		above[e[1]] = append(above[e[1]], e[0])
+		below[e[0]] = append(below[e[0]], e[1])
+	}
+
+	orderByBarycenter(layers, above, below)
+
+	positions := make([]point, n)
+
+	for l, layer := range layers {
+		for i, node := range layer {
+			positions[node] = point{x: float64(i) - float64(len(layer)-1)/2, y: float64(l)}
+		}
+	}
+
+	return positions
+}
+
+// acyclicEdges returns edges with the edges closing cycles reversed, found by depth-first search.
+// Repeated edges and self-loops are removed.
+func acyclicEdges(n int, edges [][2]int) [][2]int {
+	const (
+		unvisited = iota
+		onStack
+		done
+	)
+
+	var (
+		out     = make([][]int, n)
+		state   = make([]int, n)
+		seen    = make(map[[2]int]bool, len(edges))
+		acyclic = make([][2]int, 0, len(edges))
+		visit   func(node int)
+	)
+
+	for _, e := range edges {
+		// Self-loops cannot be reversed, they would keep their node from every layer
+		if e[0] != e[1] {
+			out[e[0]] = append(out[e[0]], e[1])
+		}
+	}
+
+	add := func(from, to int) {
+		if e := [2]int{from, to}; !seen[e] {
+			seen[e] = true
+			acyclic = append(acyclic, e)
+		}
+	}
+
+	visit = func(node int) {
+		state[node] = onStack
+
+		for _, next := range out[node] {
+			switch state[next] {
+			case unvisited:
+				add(node, next)
+				visit(next)
+			case onStack:
+				add(next, node)
+			default:
+				add(node, next)
+			}
+		}
+
+		state[node] = done
+	}
+
+	for node := 0; node < n; node++ {
+		if state[node] == unvisited {
+			visit(node)
+		}
+	}
+
+	return acyclic
+}
+
+// assignLayers returns the nodes of the layers of n nodes joined by acyclic edges.
+// Every node is placed one layer below its lowest predecessor, so sources are in the first layer.
+func assignLayers(n int, acyclic [][2]int) [][]int {
+	var (
+		layer    = make([]int, n)
+		inDegree = make([]int, n)
+		out      = make([][]int, n)
+		queue    []int
+		layers   [][]int
+	)
+
+	for _, e := range acyclic {
+		out[e[0]] = append(out[e[0]], e[1])
+		inDegree[e[1]]++
+	}
+
+	for node := 0; node < n; node++ {
+		if inDegree[node] == 0 {
+			queue = append(queue, node)
+		}
+	}
+
+	// Visiting nodes in topological order
+	for len(queue) != 0 {
+		node := queue[0]
+		queue = queue[1:]
+
+		for len(layers) <= layer[node] {
+			layers = append(layers, nil)
+		}
+
+		layers[layer[node]] = append(layers[layer[node]], node)
+
+		for _, next := range out[node] {
+			if layer[node]+1 > layer[next] {
+				layer[next] = layer[node] + 1
+			}
+
+			if inDegree[next]--; inDegree[next] == 0 {
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	return layers
+}
+
+// orderByBarycenter orders the nodes of each layer by the average position of their neighbors
+// in the other layers, sweeping down along above and up along below in turn.
+// Nodes without neighbors keep their position.
+func orderByBarycenter(layers [][]int, above, below [][]int) {
+	position := make(map[int]float64)
+
+	// place records the relative positions of the nodes of layer.
+	place := func(layer []int) {
+		for i, node := range layer {
+			position[node] = (float64(i) + 0.5) / float64(len(layer))
+		}
+	}
+
+	// sortLayer sorts layer by the barycenters of neighbors.
+	sortLayer := func(layer []int, neighbors [][]int) {
+		barycenters := make(map[int]float64, len(layer))
+
+		for _, node := range layer {
+			barycenters[node] = position[node]
+
+			if len(neighbors[node]) == 0 {
+				continue
+			}
+
+			sum := 0.0
+
+			for _, neighbor := range neighbors[node] {
+				sum += position[neighbor]
+			}
+
+			barycenters[node] = sum / float64(len(neighbors[node]))
+		}
+
+		sort.SliceStable(layer, func(i, j int) bool {
+			return barycenters[layer[i]] < barycenters[layer[j]]
+		})
+
+		place(layer)
+	}
+
+	for _, layer := range layers {
+		place(layer)
+	}
+
+	for sweep := 0; sweep < barycenterSweeps; sweep++ {
+		for l := 1; l < len(layers); l++ {
+			sortLayer(layers[l], above)
+		}
+
+		for l := len(layers) - 2; l >= 0; l-- {
+			sortLayer(layers[l], below)
+		}
+	}
+}
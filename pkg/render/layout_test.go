@@ -0,0 +1,167 @@
+package render
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/ellescotz/graph_backend/pkg/core"
+)
+
+func TestLayOut(t *testing.T) {
+	t.Parallel()
+
+	nodeA := core.Node{Name: "A", Position: &core.Position{X: 10, Y: 50}}
+	nodeB := core.Node{Name: "B", Position: &core.Position{X: 20, Y: 40}}
+	nodeC := core.Node{Name: "C"}
+
+	graph := core.Graph{
+		Nodes: []core.Node{nodeA, nodeB, nodeC},
+		Edges: []core.Edge{
+			{Nodes: [2]core.Node{nodeA, nodeB}, Weight: 1},
+			{Nodes: [2]core.Node{nodeB, nodeC}, Weight: 1},
+		},
+	}
+
+	// Positions are taken with y flipped, the node without position is placed around them
+	positions, err := layOut(context.Background(), graph, graph.AllNodes(), LayoutPositions)
+	if err != nil {
+		t.Fatalf("layOut did not work. Got error %v", err)
+	}
+
+	if positions[0] != (point{x: 10, y: -50}) || positions[1] != (point{x: 20, y: -40}) {
+		t.Errorf("layOut did not work. Got %v instead of the node positions", positions)
+	}
+
+	// Without every position, the automatic layout is force-directed and deterministic
+	automatic, err := layOut(context.Background(), graph, graph.AllNodes(), LayoutAuto)
+	if err != nil {
+		t.Fatalf("layOut did not work. Got error %v", err)
+	}
+
+	force, _ := layOut(context.Background(), graph, graph.AllNodes(), LayoutForce)
+
+	for i := range automatic {
+		if automatic[i] != force[i] {
+			t.Errorf("layOut did not work. Got %v instead of %v", automatic, force)
+			break
+		}
+	}
+
+	if _, err = layOut(context.Background(), graph, graph.AllNodes(), "spiral"); !errors.Is(err, ErrUnknownLayout) {
+		t.Errorf("layOut did not work. Got error %v instead of %v", err, ErrUnknownLayout)
+	}
+}
+
+func TestCircularLayout(t *testing.T) {
+	t.Parallel()
+
+	positions := circularLayout(4)
+
+	expected := []point{{x: 0, y: -1}, {x: 1, y: 0}, {x: 0, y: 1}, {x: -1, y: 0}}
+
+	for i := range expected {
+		if math.Abs(positions[i].x-expected[i].x) > 1e-9 || math.Abs(positions[i].y-expected[i].y) > 1e-9 {
+			t.Errorf("circularLayout did not work. Got %v instead of %v", positions, expected)
+			break
+		}
+	}
+}
+
+func TestForceLayout(t *testing.T) {
+	t.Parallel()
+
+	// A path of 4 nodes: neighbors end up closer than the ends
+	positions, _ := forceLayout(context.Background(), 4, [][2]int{{0, 1}, {1, 2}, {2, 3}})
+
+	distance := func(i, j int) float64 {
+		return math.Hypot(positions[i].x-positions[j].x, positions[i].y-positions[j].y)
+	}
+
+	if distance(0, 1) >= distance(0, 3) || distance(1, 2) >= distance(0, 3) {
+		t.Errorf("forceLayout did not work. Got %v with neighbors further apart than the ends", positions)
+	}
+
+	// Layouts stop once the context is done
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := forceLayout(ctx, 4, [][2]int{{0, 1}}); !errors.Is(err, context.Canceled) {
+		t.Errorf("forceLayout did not work. Got error %v instead of %v", err, context.Canceled)
+	}
+
+	// Large graphs are laid out on a circle
+	var large core.Graph
+
+	for i := 0; i <= maxForceNodes; i++ {
+		large.Nodes = append(large.Nodes, core.Node{Name: fmt.Sprint(i)})
+	}
+
+	circle, err := layOut(ctx, large, large.AllNodes(), LayoutForce)
+	if err != nil || circle[0] != circularLayout(len(large.Nodes))[0] {
+		t.Errorf("layOut did not work. Got error %v instead of the circular layout", err)
+	}
+}
+
+func TestLayeredLayout(t *testing.T) {
+	t.Parallel()
+
+	// 0 -> 1 -> 3, 0 -> 2 -> 3 and the cycle 3 -> 0
+	positions := layeredLayout(4, [][2]int{{0, 1}, {0, 2}, {1, 3}, {2, 3}, {3, 0}})
+
+	expectedLayers := []float64{0, 1, 1, 2}
+
+	for i, layer := range expectedLayers {
+		if positions[i].y != layer {
+			t.Errorf("layeredLayout did not work. Got layer %v instead of %v for node %v", positions[i].y, layer, i)
+		}
+	}
+
+	if positions[1].x == positions[2].x {
+		t.Errorf("layeredLayout did not work. Got nodes of the same layer at the same place: %v", positions)
+	}
+}
+
+func TestLayeredLayoutSelfLoop(t *testing.T) {
+	t.Parallel()
+
+	nodeA := core.Node{Name: "A"}
+	nodeB := core.Node{Name: "B"}
+	nodeC := core.Node{Name: "C"}
+	nodeD := core.Node{Name: "D"}
+
+	graph := core.Graph{
+		Nodes: []core.Node{nodeA, nodeB, nodeC, nodeD},
+		Edges: []core.Edge{
+			{Nodes: [2]core.Node{nodeA, nodeB}, Weight: 1},
+			{Nodes: [2]core.Node{nodeB, nodeB}, Weight: 1},
+			{Nodes: [2]core.Node{nodeB, nodeC}, Weight: 1},
+			{Nodes: [2]core.Node{nodeC, nodeD}, Weight: 1},
+		},
+	}
+
+	// Self-loops are ignored, every node of the chain gets its own layer
+	positions, err := layOut(context.Background(), graph, graph.AllNodes(), LayoutLayered)
+	if err != nil {
+		t.Fatalf("layOut did not work. Got error %v", err)
+	}
+
+	for i, position := range positions {
+		if position != (point{x: 0, y: float64(i)}) {
+			t.Errorf("layOut did not work. Got %v instead of %v for node %v", position, point{x: 0, y: float64(i)}, graph.Nodes[i])
+		}
+	}
+}
+
+func TestLayeredLayoutOrder(t *testing.T) {
+	t.Parallel()
+
+	// 0 -> 3 and 1 -> 2 cross unless the second layer is reordered
+	positions := layeredLayout(4, [][2]int{{0, 3}, {1, 2}})
+
+	if positions[3].x > positions[2].x {
+		t.Errorf("layeredLayout did not work. Got crossing edges: %v", positions)
+	}
+}
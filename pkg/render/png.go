@@ -0,0 +1,143 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"math"
+	"strconv"
+	"unicode/utf8"
+)
+
+// WritePNG writes the drawing to w as a PNG picture.
+// Text is written in a small bitmap font, characters it lacks are written as '?'.
+func (d *Drawing) WritePNG(w io.Writer) error {
+	img := image.NewRGBA(image.Rect(0, 0, d.width, d.height))
+
+	draw.Draw(img, img.Bounds(), &image.Uniform{C: parseColor(backgroundColor)}, image.Point{}, draw.Src)
+
+	// Drawing edges below nodes, and highlighted edges above the others
+	for _, highlighted := range []bool{false, true} {
+		for _, e := range d.edges {
+			if (e.color != edgeColor) != highlighted {
+				continue
+			}
+
+			c := parseColor(e.color)
+
+			dx, dy := e.to.x-e.from.x, e.to.y-e.from.y
+
+			length := math.Hypot(dx, dy)
+			if length == 0 {
+				continue
+			}
+
+			ux, uy := dx/length, dy/length
+			base := point{x: e.to.x - ux*arrowLength, y: e.to.y - uy*arrowLength}
+
+			fillSegment(img, e.from, base, e.width/2, c)
+			fillTriangle(img, e.to,
+				point{x: base.x - uy*arrowWidth/2, y: base.y + ux*arrowWidth/2},
+				point{x: base.x + uy*arrowWidth/2, y: base.y - ux*arrowWidth/2},
+				c)
+
+			fillText(img, labelPosition(e), e.label, c)
+		}
+	}
+
+	for _, n := range d.nodes {
+		fillCircle(img, n.center, nodeRadius, parseColor(n.stroke))
+		fillCircle(img, n.center, nodeRadius-edgeWidth, parseColor(nodeColor))
+		fillText(img, n.center, n.name, parseColor(textColor))
+	}
+
+	return png.Encode(w, img)
+}
+
+// parseColor returns the color written as "#rrggbb".
+func parseColor(s string) color.RGBA {
+	rgb, _ := strconv.ParseUint(s[1:], 16, 32)
+
+	return color.RGBA{R: uint8(rgb >> 16), G: uint8(rgb >> 8), B: uint8(rgb), A: 0xff}
+}
+
+// fill sets the pixels of img within the bounds whose centers are inside according to inside.
+func fill(img *image.RGBA, minX, minY, maxX, maxY float64, inside func(p point) bool, c color.RGBA) {
+	bounds := image.Rect(int(math.Floor(minX)), int(math.Floor(minY)), int(math.Ceil(maxX))+1, int(math.Ceil(maxY))+1).
+		Intersect(img.Bounds())
+
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			if inside(point{x: float64(x) + 0.5, y: float64(y) + 0.5}) {
+				img.SetRGBA(x, y, c)
+			}
+		}
+	}
+}
+
+// fillCircle draws a disc of radius around center.
+func fillCircle(img *image.RGBA, center point, radius float64, c color.RGBA) {
+	fill(img, center.x-radius, center.y-radius, center.x+radius, center.y+radius, func(p point) bool {
+		return math.Hypot(p.x-center.x, p.y-center.y) <= radius
+	}, c)
+}
+
+// fillSegment draws a line from a to b, extending halfWidth on both sides.
+func fillSegment(img *image.RGBA, a, b point, halfWidth float64, c color.RGBA) {
+	dx, dy := b.x-a.x, b.y-a.y
+	lengthSquared := dx*dx + dy*dy
+
+	fill(img, math.Min(a.x, b.x)-halfWidth, math.Min(a.y, b.y)-halfWidth,
+		math.Max(a.x, b.x)+halfWidth, math.Max(a.y, b.y)+halfWidth, func(p point) bool {
+			// Distance to the closest point of the segment
+			t := 0.0
+
+			if lengthSquared != 0 {
+				t = math.Max(0, math.Min(1, ((p.x-a.x)*dx+(p.y-a.y)*dy)/lengthSquared))
+			}
+
+			return math.Hypot(p.x-(a.x+t*dx), p.y-(a.y+t*dy)) <= halfWidth
+		}, c)
+}
+
+// fillTriangle draws the triangle with corners a, b and c.
+func fillTriangle(img *image.RGBA, a, b, c point, col color.RGBA) {
+	// side returns on which side of the line from p1 to p2 the point p is
+	side := func(p, p1, p2 point) float64 {
+		return (p.x-p2.x)*(p1.y-p2.y) - (p1.x-p2.x)*(p.y-p2.y)
+	}
+
+	fill(img, math.Min(a.x, math.Min(b.x, c.x)), math.Min(a.y, math.Min(b.y, c.y)),
+		math.Max(a.x, math.Max(b.x, c.x)), math.Max(a.y, math.Max(b.y, c.y)), func(p point) bool {
+			d1, d2, d3 := side(p, a, b), side(p, b, c), side(p, c, a)
+
+			return !((d1 < 0 || d2 < 0 || d3 < 0) && (d1 > 0 || d2 > 0 || d3 > 0))
+		}, col)
+}
+
+// fillText writes s centered on center in the bitmap font.
+func fillText(img *image.RGBA, center point, s string, c color.RGBA) {
+	var (
+		width = utf8.RuneCountInString(s)*glyphAdvance - 1
+		left  = int(math.Round(center.x - float64(width)/2))
+		top   = int(math.Round(center.y - float64(glyphHeight)/2))
+	)
+
+	for _, r := range s {
+		for row, bits := range glyph(r) {
+			for column := 0; column < glyphWidth; column++ {
+				if bits&(1<<(glyphWidth-1-column)) == 0 {
+					continue
+				}
+
+				if p := (image.Point{X: left + column, Y: top + row}); p.In(img.Bounds()) {
+					img.SetRGBA(p.X, p.Y, c)
+				}
+			}
+		}
+
+		left += glyphAdvance
+	}
+}
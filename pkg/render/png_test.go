@@ -0,0 +1,65 @@
+package render
+
+import (
+	"bytes"
+	"context"
+	"image/png"
+	"testing"
+
+	"github.com/ellescotz/graph_backend/pkg/core"
+)
+
+func TestWritePNG(t *testing.T) {
+	t.Parallel()
+
+	nodeA := core.Node{Name: "A", Position: &core.Position{X: 0, Y: 0}}
+	nodeB := core.Node{Name: "B", Position: &core.Position{X: 1, Y: 0}}
+
+	edgeAB := core.Edge{
+		Nodes:  [2]core.Node{nodeA, nodeB},
+		Weight: 1,
+	}
+
+	graph := core.Graph{
+		Nodes: []core.Node{nodeA, nodeB},
+		Edges: []core.Edge{edgeAB},
+	}
+
+	paths := []core.Path{{
+		Subgraph: core.Graph{Nodes: []core.Node{nodeA, nodeB}, Edges: []core.Edge{edgeAB}},
+		Weight:   1,
+	}}
+
+	drawing, err := NewDrawing(context.Background(), graph, paths, Options{Width: 200, Height: 100})
+	if err != nil {
+		t.Fatalf("NewDrawing did not work. Got error %v", err)
+	}
+
+	var buffer bytes.Buffer
+
+	if err = drawing.WritePNG(&buffer); err != nil {
+		t.Fatalf("WritePNG did not work. Got error %v", err)
+	}
+
+	img, err := png.Decode(&buffer)
+	if err != nil {
+		t.Fatalf("WritePNG did not work. Got undecodable picture: %v", err)
+	}
+
+	if size := img.Bounds().Size(); size.X != 200 || size.Y != 100 {
+		t.Errorf("WritePNG did not work. Got size %v instead of 200x100", size)
+	}
+
+	// The nodes lie on the middle row, the highlighted edge between them
+	expected := parseColor(pathColors[0])
+
+	r, g, b, _ := img.At(100, 50).RGBA()
+	if uint8(r>>8) != expected.R || uint8(g>>8) != expected.G || uint8(b>>8) != expected.B {
+		t.Errorf("WritePNG did not work. Got color %v, %v, %v instead of %v in the middle", r>>8, g>>8, b>>8, expected)
+	}
+
+	r, g, b, _ = img.At(5, 5).RGBA()
+	if r>>8 != 0xff || g>>8 != 0xff || b>>8 != 0xff {
+		t.Errorf("WritePNG did not work. Got color %v, %v, %v instead of white in the corner", r>>8, g>>8, b>>8)
+	}
+}
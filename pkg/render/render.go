@@ -0,0 +1,269 @@
+// Package render lays out graphs and draws them as SVG or PNG pictures,
+// with paths found in them highlighted.
+package render
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strconv"
+
+	"github.com/ellescotz/graph_backend/pkg/core"
+)
+
+// Media types of pictures.
+const (
+	SVGMediaType string = "image/svg+xml"
+	PNGMediaType string = "image/png"
+)
+
+// Layout is the algorithm placing the nodes of a graph.
+type Layout string
+
+// Layouts of graphs.
+const (
+	LayoutAuto      Layout = ""          // node positions if every node has one, force-directed otherwise
+	LayoutPositions Layout = "positions" // node positions, nodes without position are placed on a circle
+	LayoutForce     Layout = "force"     // force-directed (Fruchterman-Reingold), circular beyond 1000 nodes
+	LayoutCircular  Layout = "circular"  // nodes on a circle in order
+	LayoutLayered   Layout = "layered"   // layers along the edges (Sugiyama), for DAGs above all
+)
+
+// Default size of pictures in pixels.
+const (
+	DefaultWidth  int = 800
+	DefaultHeight int = 600
+)
+
+// MaxSize is the largest width and height of pictures in pixels.
+const MaxSize int = 4096
+
+// Sizes of the drawing in pixels.
+const (
+	nodeRadius  float64 = 14
+	margin      float64 = 2 * nodeRadius
+	edgeWidth   float64 = 1.5
+	pathWidth   float64 = 3.5
+	arrowLength float64 = 10
+	arrowWidth  float64 = 7
+	edgeOffset  float64 = 4 // distance of edges running between the same nodes in opposite directions
+)
+
+// Colors of the drawing.
+const (
+	backgroundColor string = "#ffffff"
+	nodeColor       string = "#ffffff"
+	strokeColor     string = "#333333"
+	edgeColor       string = "#999999"
+	textColor       string = "#000000"
+)
+
+// pathColors are the colors of highlighted paths, used in turn.
+var pathColors = []string{"#e53421", "#1f77b4", "#2ca02c", "#ff7f0e", "#9467bd", "#8c564b"}
+
+// Errors of rendering.
+var (
+	ErrUnknownLayout = errors.New("unknown layout")
+	ErrSize          = errors.New("width and height have to be between 1 and 4096 pixels")
+)
+
+// Options represents the options of a drawing.
+type Options struct {
+	Layout Layout
+	Width  int // DefaultWidth if 0
+	Height int // DefaultHeight if 0
+}
+
+// point represents a position in the drawing.
+type point struct {
+	x, y float64
+}
+
+// drawnNode represents a node of a drawing.
+type drawnNode struct {
+	name   string
+	center point
+	stroke string
+}
+
+// drawnEdge represents an edge of a drawing, running between the borders of its nodes.
+type drawnEdge struct {
+	from, to point
+	label    string
+	color    string
+	width    float64
+}
+
+// Drawing represents a graph laid out on a picture.
+type Drawing struct {
+	width, height int
+	nodes         []drawnNode
+	edges         []drawnEdge
+}
+
+// highlightKey identifies equal edges (see core.Edge.Equals).
+type highlightKey struct {
+	from, to string
+	weight   float64
+}
+
+// NewDrawing lays out g according to options.
+// The nodes and edges of paths are highlighted, each path in its own color.
+// It returns ErrUnknownLayout or ErrSize for wrong options, and the error of ctx if it is done while laying out g.
+func NewDrawing(ctx context.Context, g core.Graph, paths []core.Path, options Options) (*Drawing, error) {
+	if options.Width == 0 {
+		options.Width = DefaultWidth
+	}
+
+	if options.Height == 0 {
+		options.Height = DefaultHeight
+	}
+
+	if options.Width < 1 || options.Width > MaxSize || options.Height < 1 || options.Height > MaxSize {
+		return nil, ErrSize
+	}
+
+	nodes := g.AllNodes()
+
+	positions, err := layOut(ctx, g, nodes, options.Layout)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &Drawing{
+		width:  options.Width,
+		height: options.Height,
+	}
+
+	// Colors of the first path through every node and edge
+	var (
+		nodeColors = make(map[string]string)
+		edgeColors = make(map[highlightKey]string)
+	)
+
+	for p := range paths {
+		color := pathColors[p%len(pathColors)]
+
+		for _, n := range paths[p].Subgraph.AllNodes() {
+			if _, ok := nodeColors[n.Name]; !ok {
+				nodeColors[n.Name] = color
+			}
+		}
+
+		for _, e := range paths[p].Subgraph.Edges {
+			key := highlightKey{e.Nodes[0].Name, e.Nodes[1].Name, e.Weight}
+
+			if _, ok := edgeColors[key]; !ok {
+				edgeColors[key] = color
+			}
+		}
+	}
+
+	centers := d.fit(positions)
+	ids := make(map[string]int, len(nodes))
+
+	for i, n := range nodes {
+		ids[n.Name] = i
+
+		d.nodes = append(d.nodes, drawnNode{
+			name:   n.Name,
+			center: centers[i],
+			stroke: strokeColor,
+		})
+
+		if color, ok := nodeColors[n.Name]; ok {
+			d.nodes[i].stroke = color
+		}
+	}
+
+	// Edges running in both directions are drawn side by side
+	reversed := make(map[[2]int]bool, len(g.Edges))
+
+	for _, e := range g.Edges {
+		reversed[[2]int{ids[e.Nodes[1].Name], ids[e.Nodes[0].Name]}] = true
+	}
+
+	for i := range g.Edges {
+		e := &g.Edges[i]
+		from, to := ids[e.Nodes[0].Name], ids[e.Nodes[1].Name]
+
+		edge := drawnEdge{
+			label: strconv.FormatFloat(e.Weight, 'g', 4, 64),
+			color: edgeColor,
+			width: edgeWidth,
+		}
+
+		edge.from, edge.to = between(centers[from], centers[to], reversed[[2]int{from, to}])
+
+		if color, ok := edgeColors[highlightKey{e.Nodes[0].Name, e.Nodes[1].Name, e.Weight}]; ok {
+			edge.color = color
+			edge.width = pathWidth
+		}
+
+		d.edges = append(d.edges, edge)
+	}
+
+	return d, nil
+}
+
+// fit scales positions to the picture uniformly, keeping a margin around the nodes,
+// and centers them.
+func (d *Drawing) fit(positions []point) []point {
+	var (
+		fitted                 = make([]point, len(positions))
+		minX, minY, maxX, maxY = math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)
+	)
+
+	for _, p := range positions {
+		minX, maxX = math.Min(minX, p.x), math.Max(maxX, p.x)
+		minY, maxY = math.Min(minY, p.y), math.Max(maxY, p.y)
+	}
+
+	width := math.Max(float64(d.width)-2*margin, 0)
+	height := math.Max(float64(d.height)-2*margin, 0)
+
+	// Single nodes and lines of nodes are not stretched in the missing direction
+	scale := math.Inf(1)
+
+	if maxX > minX {
+		scale = width / (maxX - minX)
+	}
+
+	if maxY > minY {
+		scale = math.Min(scale, height/(maxY-minY))
+	}
+
+	if math.IsInf(scale, 1) {
+		scale = 0
+	}
+
+	for i, p := range positions {
+		fitted[i] = point{
+			x: float64(d.width)/2 + (p.x-(minX+maxX)/2)*scale,
+			y: float64(d.height)/2 + (p.y-(minY+maxY)/2)*scale,
+		}
+	}
+
+	return fitted
+}
+
+// between returns the ends of an edge from the node centered at from to the node centered at to,
+// on the borders of the nodes. The edge is moved aside if offset is true.
+func between(from, to point, offset bool) (point, point) {
+	dx, dy := to.x-from.x, to.y-from.y
+
+	length := math.Hypot(dx, dy)
+	if length == 0 {
+		return from, to
+	}
+
+	ux, uy := dx/length, dy/length
+
+	if offset {
+		from.x, from.y = from.x-uy*edgeOffset, from.y+ux*edgeOffset
+		to.x, to.y = to.x-uy*edgeOffset, to.y+ux*edgeOffset
+	}
+
+	return point{x: from.x + ux*nodeRadius, y: from.y + uy*nodeRadius},
+		point{x: to.x - ux*nodeRadius, y: to.y - uy*nodeRadius}
+}
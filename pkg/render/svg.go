@@ -0,0 +1,110 @@
+package render
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+// fontSize is the size of the text of SVG pictures in pixels.
+const fontSize float64 = 11
+
+// WriteSVG writes the drawing to w as an SVG picture.
+func (d *Drawing) WriteSVG(w io.Writer) error {
+	buffered := bufio.NewWriter(w)
+
+	fmt.Fprintf(buffered, "%s<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\">\n",
+		xml.Header, d.width, d.height, d.width, d.height)
+
+	// Declaring an arrowhead for every color of edges
+	var colors []string
+
+	for _, e := range d.edges {
+		if !contains(colors, e.color) {
+			colors = append(colors, e.color)
+		}
+	}
+
+	fmt.Fprint(buffered, "  <defs>\n")
+
+	for _, color := range colors {
+		fmt.Fprintf(buffered, "    <marker id=\"%s\" viewBox=\"0 0 %g %g\" refX=\"%g\" refY=\"%g\" markerUnits=\"userSpaceOnUse\" "+
+			"markerWidth=\"%g\" markerHeight=\"%g\" orient=\"auto\"><path d=\"M0,0 L%g,%g L0,%g z\" fill=\"%s\"/></marker>\n",
+			markerID(color), arrowLength, arrowWidth, arrowLength, arrowWidth/2,
+			arrowLength, arrowWidth, arrowLength, arrowWidth/2, arrowWidth, color)
+	}
+
+	fmt.Fprint(buffered, "  </defs>\n")
+	fmt.Fprintf(buffered, "  <rect width=\"100%%\" height=\"100%%\" fill=\"%s\"/>\n", backgroundColor)
+
+	// Drawing edges below nodes, and highlighted edges above the others
+	for _, highlighted := range []bool{false, true} {
+		for _, e := range d.edges {
+			if (e.color != edgeColor) != highlighted {
+				continue
+			}
+
+			fmt.Fprintf(buffered, "  <line x1=\"%.1f\" y1=\"%.1f\" x2=\"%.1f\" y2=\"%.1f\" stroke=\"%s\" stroke-width=\"%g\" marker-end=\"url(#%s)\"/>\n",
+				e.from.x, e.from.y, e.to.x, e.to.y, e.color, e.width, markerID(e.color))
+
+			label := labelPosition(e)
+
+			fmt.Fprintf(buffered, "  <text x=\"%.1f\" y=\"%.1f\" font-family=\"sans-serif\" font-size=\"%g\" text-anchor=\"middle\" fill=\"%s\">%s</text>\n",
+				label.x, label.y, fontSize, e.color, escape(e.label))
+		}
+	}
+
+	for _, n := range d.nodes {
+		fmt.Fprintf(buffered, "  <circle cx=\"%.1f\" cy=\"%.1f\" r=\"%g\" fill=\"%s\" stroke=\"%s\" stroke-width=\"%g\"/>\n",
+			n.center.x, n.center.y, nodeRadius, nodeColor, n.stroke, edgeWidth)
+		fmt.Fprintf(buffered, "  <text x=\"%.1f\" y=\"%.1f\" font-family=\"sans-serif\" font-size=\"%g\" text-anchor=\"middle\" dominant-baseline=\"central\" fill=\"%s\">%s</text>\n",
+			n.center.x, n.center.y, fontSize, textColor, escape(n.name))
+	}
+
+	fmt.Fprint(buffered, "</svg>\n")
+
+	return buffered.Flush()
+}
+
+// labelPosition returns the position of the label of e: next to the middle of the edge, on its left.
+func labelPosition(e drawnEdge) point {
+	dx, dy := e.to.x-e.from.x, e.to.y-e.from.y
+
+	length := math.Hypot(dx, dy)
+	if length == 0 {
+		return e.from
+	}
+
+	return point{
+		x: (e.from.x+e.to.x)/2 + dy/length*fontSize,
+		y: (e.from.y+e.to.y)/2 - dx/length*fontSize,
+	}
+}
+
+// markerID returns the ID of the arrowhead of edges of color.
+func markerID(color string) string {
+	return "arrow-" + strings.TrimPrefix(color, "#")
+}
+
+// escape returns s with the special characters of XML escaped.
+func escape(s string) string {
+	var escaped strings.Builder
+
+	_ = xml.EscapeText(&escaped, []byte(s))
+
+	return escaped.String()
+}
+
+// contains reports whether values contains value.
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
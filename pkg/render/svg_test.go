@@ -0,0 +1,108 @@
+package render
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/ellescotz/graph_backend/pkg/core"
+)
+
+func TestWriteSVG(t *testing.T) {
+	t.Parallel()
+
+	nodeA := core.Node{Name: "A"}
+	nodeB := core.Node{Name: "B & C"}
+	nodeD := core.Node{Name: "D"}
+
+	edgeAB := core.Edge{
+		Nodes:  [2]core.Node{nodeA, nodeB},
+		Weight: 2.5,
+	}
+
+	edgeBD := core.Edge{
+		Nodes:  [2]core.Node{nodeB, nodeD},
+		Weight: 1,
+	}
+
+	graph := core.Graph{
+		Nodes: []core.Node{nodeA, nodeB, nodeD},
+		Edges: []core.Edge{edgeAB, edgeBD},
+	}
+
+	paths := []core.Path{{
+		Subgraph: core.Graph{Nodes: []core.Node{nodeA, nodeB}, Edges: []core.Edge{edgeAB}},
+		Weight:   2.5,
+	}}
+
+	drawing, err := NewDrawing(context.Background(), graph, paths, Options{Layout: LayoutCircular, Width: 300, Height: 200})
+	if err != nil {
+		t.Fatalf("NewDrawing did not work. Got error %v", err)
+	}
+
+	var buffer bytes.Buffer
+
+	if err = drawing.WriteSVG(&buffer); err != nil {
+		t.Fatalf("WriteSVG did not work. Got error %v", err)
+	}
+
+	svg := buffer.String()
+
+	// The picture is well-formed XML
+	decoder := xml.NewDecoder(strings.NewReader(svg))
+
+	for {
+		if _, err = decoder.Token(); err != nil {
+			break
+		}
+	}
+
+	if !errors.Is(err, io.EOF) {
+		t.Errorf("WriteSVG did not work. Got malformed XML: %v", err)
+	}
+
+	for _, expected := range []string{
+		`width="300" height="200"`,
+		`stroke="#e53421" stroke-width="3.5"`, // highlighted edge
+		`stroke="#999999" stroke-width="1.5"`, // other edge
+		`>B &amp; C</text>`,
+		`>2.5</text>`,
+	} {
+		if !strings.Contains(svg, expected) {
+			t.Errorf("WriteSVG did not work. Got %v without %v", svg, expected)
+		}
+	}
+
+	if count := strings.Count(svg, `stroke="#e53421"`); count != 3 {
+		t.Errorf("WriteSVG did not work. Got %v highlighted elements instead of 3", count)
+	}
+}
+
+func TestNewDrawingOptions(t *testing.T) {
+	t.Parallel()
+
+	graph := core.Graph{Nodes: []core.Node{{Name: "A"}}}
+
+	if _, err := NewDrawing(context.Background(), graph, nil, Options{Width: MaxSize + 1}); !errors.Is(err, ErrSize) {
+		t.Errorf("NewDrawing did not work. Got error %v instead of %v", err, ErrSize)
+	}
+
+	if _, err := NewDrawing(context.Background(), graph, nil, Options{Layout: "spiral"}); !errors.Is(err, ErrUnknownLayout) {
+		t.Errorf("NewDrawing did not work. Got error %v instead of %v", err, ErrUnknownLayout)
+	}
+
+	drawing, err := NewDrawing(context.Background(), graph, nil, Options{})
+	if err != nil {
+		t.Fatalf("NewDrawing did not work. Got error %v", err)
+	}
+
+	// A single node is centered on a picture of the default size
+	if drawing.width != DefaultWidth || drawing.height != DefaultHeight ||
+		drawing.nodes[0].center != (point{x: float64(DefaultWidth) / 2, y: float64(DefaultHeight) / 2}) {
+		t.Errorf("NewDrawing did not work. Got %v", drawing)
+	}
+}
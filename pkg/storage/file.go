@@ -0,0 +1,278 @@
+package storage
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ellescotz/graph_backend/pkg/core"
+)
+
+// graphFileExtension is the extension of the files storing graphs.
+const graphFileExtension = ".json"
+
+// FileRepository is a Repository keeping every graph with its revisions in a file of a directory,
+// so that graphs survive restarts without any external service.
+// Files hold one revision per line in JSON, oldest first. New revisions are appended,
+// and files are rewritten with the kept revisions only once they hold 2*MaxRevisions revisions.
+type FileRepository struct {
+	mu  sync.RWMutex
+	dir string
+}
+
+// NewFileRepository returns a FileRepository storing graphs in dir.
+// dir is created if it does not exist.
+func NewFileRepository(dir string) (*FileRepository, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	return &FileRepository{dir: dir}, nil
+}
+
+// path returns the path of the file storing the graph with id.
+func (r *FileRepository) path(id string) string {
+	return filepath.Join(r.dir, id+graphFileExtension)
+}
+
+// read reads the kept revisions of the graph stored with id.
+// rewrite is true if the file is due to be rewritten instead of appended to:
+// because it holds 2*MaxRevisions revisions, or because it ends with a partial revision,
+// left by a crash while appending, which is ignored.
+func (r *FileRepository) read(id string) (h history, rewrite bool, err error) {
+	if !validID(id) {
+		return nil, false, ErrNotFound
+	}
+
+	file, err := os.Open(r.path(id))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, false, ErrNotFound
+	} else if err != nil {
+		return nil, false, err
+	}
+
+	defer file.Close()
+
+	var (
+		reader = bufio.NewReader(file)
+		lines  int
+	)
+
+	for {
+		line, err := reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			rewrite = len(line) != 0
+			break
+		} else if err != nil {
+			return nil, false, err
+		}
+
+		var revision storedRevision
+
+		if err = json.Unmarshal(line, &revision); err != nil {
+			return nil, false, err
+		}
+
+		h = append(h, revision)
+		h.trim()
+		lines++
+	}
+
+	if len(h) == 0 {
+		return nil, false, fmt.Errorf("graph %v has no revision", id)
+	}
+
+	return h, rewrite || lines >= 2*MaxRevisions, nil
+}
+
+// write stores the revisions of h as the graph with id.
+// The file is replaced atomically, so that a crash cannot leave a partial graph behind.
+func (r *FileRepository) write(id string, h history) error {
+	file, err := os.CreateTemp(r.dir, id+".*.tmp")
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(file.Name())
+
+	buffer := bufio.NewWriter(file)
+	encoder := json.NewEncoder(buffer)
+
+	for _, revision := range h {
+		if err = encoder.Encode(revision); err != nil {
+			file.Close()
+			return err
+		}
+	}
+
+	if err = buffer.Flush(); err != nil {
+		file.Close()
+		return err
+	}
+
+	if err = file.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(file.Name(), r.path(id))
+}
+
+// append appends revision to the file of the graph with id.
+func (r *FileRepository) append(id string, revision storedRevision) error {
+	data, err := json.Marshal(revision)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(r.path(id), os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		return err
+	}
+
+	if _, err = file.Write(append(data, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
+// Create stores graph and returns its new ID.
+func (r *FileRepository) Create(graph core.Graph) (string, error) {
+	id, err := newID()
+	if err != nil {
+		return "", err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var h history
+
+	h.add(graph)
+
+	return id, r.write(id, h)
+}
+
+// Get returns the latest revision of the graph stored with id.
+func (r *FileRepository) Get(id string) (core.Graph, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	h, _, err := r.read(id)
+	if err != nil {
+		return core.Graph{}, err
+	}
+
+	return h.latest(), nil
+}
+
+// GetRevision returns the graph stored with id as it was at revision number.
+func (r *FileRepository) GetRevision(id string, number int) (core.Graph, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	h, _, err := r.read(id)
+	if err != nil {
+		return core.Graph{}, err
+	}
+
+	return h.graph(number)
+}
+
+// Revisions returns every kept revision of the graph stored with id, oldest first.
+func (r *FileRepository) Revisions(id string) ([]Revision, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	h, _, err := r.read(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return h.revisions(), nil
+}
+
+// Update replaces the graph stored with id by a new revision.
+func (r *FileRepository) Update(id string, graph core.Graph) error {
+	return r.Modify(id, func(stored *core.Graph) error {
+		*stored = graph
+
+		return nil
+	})
+}
+
+// Modify applies modify to the graph stored with id, and stores the result as a new revision
+// unless modify returns an error.
+func (r *FileRepository) Modify(id string, modify func(graph *core.Graph) error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	h, rewrite, err := r.read(id)
+	if err != nil {
+		return err
+	}
+
+	graph := h.latest()
+
+	if err = modify(&graph); err != nil {
+		return err
+	}
+
+	revision := h.add(graph)
+
+	if rewrite {
+		return r.write(id, h)
+	}
+
+	return r.append(id, revision)
+}
+
+// Delete removes the graph stored with id with all of its revisions.
+func (r *FileRepository) Delete(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !validID(id) {
+		return ErrNotFound
+	}
+
+	err := os.Remove(r.path(id))
+	if errors.Is(err, os.ErrNotExist) {
+		return ErrNotFound
+	}
+
+	return err
+}
+
+// List returns the IDs of every stored graph in ascending order.
+func (r *FileRepository) List() ([]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entries, err := os.ReadDir(r.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := []string{}
+
+	for _, entry := range entries {
+		id := strings.TrimSuffix(entry.Name(), graphFileExtension)
+
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), graphFileExtension) && validID(id) {
+			ids = append(ids, id)
+		}
+	}
+
+	sort.Strings(ids)
+
+	return ids, nil
+}
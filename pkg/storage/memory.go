@@ -0,0 +1,141 @@
+package storage
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/ellescotz/graph_backend/pkg/core"
+)
+
+// MemoryRepository is a Repository keeping graphs in memory.
+// Graphs are lost when the process exits.
+type MemoryRepository struct {
+	mu     sync.RWMutex
+	graphs map[string]history
+}
+
+// NewMemoryRepository returns an empty MemoryRepository.
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{
+		graphs: make(map[string]history),
+	}
+}
+
+// Create stores graph and returns its new ID.
+func (r *MemoryRepository) Create(graph core.Graph) (string, error) {
+	id, err := newID()
+	if err != nil {
+		return "", err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var h history
+
+	h.add(graph)
+	r.graphs[id] = h
+
+	return id, nil
+}
+
+// Get returns the latest revision of the graph stored with id.
+func (r *MemoryRepository) Get(id string) (core.Graph, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	h, ok := r.graphs[id]
+	if !ok {
+		return core.Graph{}, ErrNotFound
+	}
+
+	return h.latest(), nil
+}
+
+// GetRevision returns the graph stored with id as it was at revision number.
+func (r *MemoryRepository) GetRevision(id string, number int) (core.Graph, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	h, ok := r.graphs[id]
+	if !ok {
+		return core.Graph{}, ErrNotFound
+	}
+
+	return h.graph(number)
+}
+
+// Revisions returns every kept revision of the graph stored with id, oldest first.
+func (r *MemoryRepository) Revisions(id string) ([]Revision, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	h, ok := r.graphs[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	return h.revisions(), nil
+}
+
+// Update replaces the graph stored with id by a new revision.
+func (r *MemoryRepository) Update(id string, graph core.Graph) error {
+	return r.Modify(id, func(stored *core.Graph) error {
+		*stored = graph.Copy()
+
+		return nil
+	})
+}
+
+// Modify applies modify to the graph stored with id, and stores the result as a new revision
+// unless modify returns an error.
+func (r *MemoryRepository) Modify(id string, modify func(graph *core.Graph) error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	h, ok := r.graphs[id]
+	if !ok {
+		return ErrNotFound
+	}
+
+	graph := h.latest()
+
+	if err := modify(&graph); err != nil {
+		return err
+	}
+
+	h.add(graph)
+	r.graphs[id] = h
+
+	return nil
+}
+
+// Delete removes the graph stored with id with all of its revisions.
+func (r *MemoryRepository) Delete(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.graphs[id]; !ok {
+		return ErrNotFound
+	}
+
+	delete(r.graphs, id)
+
+	return nil
+}
+
+// List returns the IDs of every stored graph in ascending order.
+func (r *MemoryRepository) List() ([]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ids := make([]string, 0, len(r.graphs))
+
+	for id := range r.graphs {
+		ids = append(ids, id)
+	}
+
+	sort.Strings(ids)
+
+	return ids, nil
+}
@@ -0,0 +1,146 @@
+package storage
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/ellescotz/graph_backend/pkg/core"
+)
+
+// ErrNotFound is returned when no graph is stored with the given ID.
+var ErrNotFound = errors.New("graph not found")
+
+// ErrRevisionNotFound is returned when the stored graph has no revision with the given number.
+var ErrRevisionNotFound = errors.New("revision not found")
+
+// Revision describes a version of a stored graph.
+// Every change of a stored graph creates a new revision, numbered from 1.
+// Only the latest MaxRevisions revisions are kept.
+type Revision struct {
+	Number  int       `json:"number"`
+	Created time.Time `json:"created"`
+}
+
+// Repository stores graphs by ID.
+// Implementations are safe for concurrent use.
+type Repository interface {
+	// Create stores graph and returns its new ID.
+	Create(graph core.Graph) (string, error)
+
+	// Get returns the latest revision of the graph stored with id.
+	Get(id string) (core.Graph, error)
+
+	// GetRevision returns the graph stored with id as it was at revision number.
+	// It returns ErrRevisionNotFound if the revision does not exist or is not kept anymore.
+	GetRevision(id string, number int) (core.Graph, error)
+
+	// Revisions returns every kept revision of the graph stored with id, oldest first.
+	Revisions(id string) ([]Revision, error)
+
+	// Update replaces the graph stored with id by a new revision.
+	Update(id string, graph core.Graph) error
+
+	// Modify applies modify to the graph stored with id, and stores the result as a new revision
+	// unless modify returns an error. No other change can happen in between.
+	Modify(id string, modify func(graph *core.Graph) error) error
+
+	// Delete removes the graph stored with id with all of its revisions.
+	Delete(id string) error
+
+	// List returns the IDs of every stored graph.
+	List() ([]string, error)
+}
+
+// storedRevision is a revision together with the graph it holds.
+type storedRevision struct {
+	Revision
+	Graph core.Graph `json:"graph"`
+}
+
+// MaxRevisions is the number of revisions kept for each stored graph.
+// Older revisions are dropped, the kept ones keep their numbers.
+const MaxRevisions int = 50
+
+// history holds the latest revisions of a stored graph, at most MaxRevisions, oldest first.
+type history []storedRevision
+
+// add appends graph to h as a new revision, dropping the oldest revisions beyond MaxRevisions.
+// It returns the new revision.
+func (h *history) add(graph core.Graph) storedRevision {
+	number := 1
+
+	if len(*h) != 0 {
+		number = (*h)[len(*h)-1].Number + 1
+	}
+
+	revision := storedRevision{
+		Revision: Revision{
+			Number:  number,
+			Created: time.Now().UTC(),
+		},
+		Graph: graph.Copy(),
+	}
+
+	*h = append(*h, revision)
+	h.trim()
+
+	return revision
+}
+
+// trim drops the oldest revisions of h beyond MaxRevisions.
+func (h *history) trim() {
+	if len(*h) > MaxRevisions {
+		*h = (*h)[len(*h)-MaxRevisions:]
+	}
+}
+
+// latest returns a copy of the graph of the latest revision.
+func (h history) latest() core.Graph {
+	return h[len(h)-1].Graph.Copy()
+}
+
+// graph returns a copy of the graph of revision number.
+func (h history) graph(number int) (core.Graph, error) {
+	i := number - h[0].Number
+
+	if i < 0 || i >= len(h) {
+		return core.Graph{}, ErrRevisionNotFound
+	}
+
+	return h[i].Graph.Copy(), nil
+}
+
+// revisions returns the descriptions of the revisions of h.
+func (h history) revisions() []Revision {
+	revisions := make([]Revision, len(h))
+
+	for i, r := range h {
+		revisions[i] = r.Revision
+	}
+
+	return revisions
+}
+
+// newID generates a random graph ID.
+func newID() (string, error) {
+	id := make([]byte, 16)
+
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(id), nil
+}
+
+// validID reports whether id can have been generated by newID.
+func validID(id string) bool {
+	if len(id) != 32 {
+		return false
+	}
+
+	_, err := hex.DecodeString(id)
+
+	return err == nil
+}
@@ -0,0 +1,215 @@
+package storage
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/ellescotz/graph_backend/pkg/core"
+)
+
+// testRepository runs the same checks against any Repository.
+func testRepository(t *testing.T, repository Repository) {
+	t.Helper()
+
+	nodeA := core.Node{Name: "A"}
+	nodeB := core.Node{Name: "B"}
+
+	graph := core.Graph{
+		Nodes: []core.Node{nodeA, nodeB},
+		Edges: []core.Edge{{Nodes: [2]core.Node{nodeA, nodeB}, Weight: 2}},
+	}
+
+	// Create and Get
+	id, err := repository.Create(graph)
+	if err != nil {
+		t.Fatalf("Create did not work. Got error %v", err)
+	}
+
+	stored, err := repository.Get(id)
+	if err != nil {
+		t.Errorf("Get did not work. Got error %v", err)
+	} else if len(stored.Nodes) != 2 || len(stored.Edges) != 1 || !stored.Edges[0].Equals(&graph.Edges[0]) {
+		t.Errorf("Get did not work. Got %v instead of %v", stored, graph)
+	}
+
+	// Modify
+	err = repository.Modify(id, func(g *core.Graph) error {
+		return g.AddNode(core.Node{Name: "C"})
+	})
+	if err != nil {
+		t.Errorf("Modify did not work. Got error %v", err)
+	}
+
+	err = repository.Modify(id, func(g *core.Graph) error {
+		return g.AddNode(nodeA)
+	})
+	if !errors.Is(err, core.ErrNodeExists) {
+		t.Errorf("Modify did not work. Got error %v instead of %v", err, core.ErrNodeExists)
+	}
+
+	if stored, _ = repository.Get(id); len(stored.Nodes) != 3 {
+		t.Errorf("Modify did not work. Got %v instead of %v", len(stored.Nodes), 3)
+	}
+
+	// Update
+	if err = repository.Update(id, core.Graph{Nodes: []core.Node{nodeA}}); err != nil {
+		t.Errorf("Update did not work. Got error %v", err)
+	}
+
+	if stored, _ = repository.Get(id); len(stored.Nodes) != 1 || len(stored.Edges) != 0 {
+		t.Errorf("Update did not work. Got %v", stored)
+	}
+
+	// Revisions
+	if revisions, err := repository.Revisions(id); err != nil || len(revisions) != 3 || revisions[2].Number != 3 {
+		t.Errorf("Revisions did not work. Got %v and error %v", revisions, err)
+	}
+
+	if stored, err = repository.GetRevision(id, 2); err != nil || len(stored.Nodes) != 3 || len(stored.Edges) != 1 {
+		t.Errorf("GetRevision did not work. Got %v and error %v", stored, err)
+	}
+
+	for _, number := range []int{0, 4} {
+		if _, err = repository.GetRevision(id, number); !errors.Is(err, ErrRevisionNotFound) {
+			t.Errorf("GetRevision did not work. Got error %v instead of %v", err, ErrRevisionNotFound)
+		}
+	}
+
+	// List
+	if ids, err := repository.List(); err != nil || len(ids) != 1 || ids[0] != id {
+		t.Errorf("List did not work. Got %v and error %v", ids, err)
+	}
+
+	// Delete
+	if err = repository.Delete(id); err != nil {
+		t.Errorf("Delete did not work. Got error %v", err)
+	}
+
+	for _, id := range []string{id, "../../etc/passwd"} {
+		if _, err = repository.Get(id); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Get did not work. Got error %v instead of %v", err, ErrNotFound)
+		}
+
+		if err = repository.Delete(id); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Delete did not work. Got error %v instead of %v", err, ErrNotFound)
+		}
+
+		if err = repository.Update(id, graph); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Update did not work. Got error %v instead of %v", err, ErrNotFound)
+		}
+
+		if _, err = repository.Revisions(id); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Revisions did not work. Got error %v instead of %v", err, ErrNotFound)
+		}
+	}
+}
+
+// testRevisionLimit checks that repository keeps the latest MaxRevisions revisions of the graph with id,
+// after modifying it as many times.
+func testRevisionLimit(t *testing.T, repository Repository, id string) {
+	t.Helper()
+
+	for i := 0; i < MaxRevisions; i++ {
+		err := repository.Modify(id, func(g *core.Graph) error {
+			return g.AddNode(core.Node{Name: strconv.Itoa(i)})
+		})
+		if err != nil {
+			t.Fatalf("Modify did not work. Got error %v", err)
+		}
+	}
+
+	revisions, err := repository.Revisions(id)
+	if err != nil || len(revisions) != MaxRevisions || revisions[0].Number != 2 || revisions[MaxRevisions-1].Number != MaxRevisions+1 {
+		t.Errorf("Revisions did not work. Got %v and error %v instead of revisions 2 to %v", len(revisions), err, MaxRevisions+1)
+	}
+
+	if _, err = repository.GetRevision(id, 1); !errors.Is(err, ErrRevisionNotFound) {
+		t.Errorf("GetRevision did not work. Got error %v instead of %v", err, ErrRevisionNotFound)
+	}
+
+	if stored, err := repository.GetRevision(id, 2); err != nil || len(stored.Nodes) != 1 {
+		t.Errorf("GetRevision did not work. Got %v and error %v", stored, err)
+	}
+
+	if stored, err := repository.Get(id); err != nil || len(stored.Nodes) != MaxRevisions {
+		t.Errorf("Get did not work. Got %v nodes and error %v instead of %v", len(stored.Nodes), err, MaxRevisions)
+	}
+}
+
+func TestMemoryRepository(t *testing.T) {
+	t.Parallel()
+
+	repository := NewMemoryRepository()
+
+	testRepository(t, repository)
+
+	id, _ := repository.Create(core.Graph{})
+
+	testRevisionLimit(t, repository, id)
+}
+
+func TestFileRepository(t *testing.T) {
+	t.Parallel()
+
+	repository, err := NewFileRepository(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileRepository did not work. Got error %v", err)
+	}
+
+	testRepository(t, repository)
+
+	// Graphs survive reopening the directory
+	id, _ := repository.Create(core.Graph{Nodes: []core.Node{{Name: "A"}}})
+
+	reopened, _ := NewFileRepository(repository.dir)
+
+	if graph, err := reopened.Get(id); err != nil || len(graph.Nodes) != 1 {
+		t.Errorf("NewFileRepository did not work. Got %v and error %v", graph, err)
+	}
+
+	// New revisions are appended, the file is rewritten with the kept revisions only
+	id, _ = repository.Create(core.Graph{})
+
+	testRevisionLimit(t, repository, id)
+
+	for i := 0; i < MaxRevisions; i++ {
+		_ = repository.Update(id, core.Graph{})
+
+		if lines := countLines(t, repository.path(id)); lines > 2*MaxRevisions {
+			t.Fatalf("Update did not work. Got %v revisions in the file instead of at most %v", lines, 2*MaxRevisions)
+		}
+	}
+
+	// Revisions partially appended are ignored, and overwritten by the next revision
+	file, _ := os.OpenFile(repository.path(id), os.O_WRONLY|os.O_APPEND, 0)
+	_, _ = file.WriteString(`{"number": 1`)
+	file.Close()
+
+	revisions, err := repository.Revisions(id)
+	if err != nil || len(revisions) != MaxRevisions {
+		t.Errorf("Revisions did not work. Got %v and error %v", revisions, err)
+	}
+
+	if err = repository.Update(id, core.Graph{Nodes: []core.Node{{Name: "A"}}}); err != nil {
+		t.Errorf("Update did not work. Got error %v", err)
+	}
+
+	if graph, err := repository.Get(id); err != nil || len(graph.Nodes) != 1 || countLines(t, repository.path(id)) != MaxRevisions {
+		t.Errorf("Update did not work. Got %v and error %v", graph, err)
+	}
+}
+
+// countLines returns the number of lines of the file at path.
+func countLines(t *testing.T, path string) int {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Reading %v did not work. Got error %v", path, err)
+	}
+
+	return bytes.Count(data, []byte("\n"))
+}
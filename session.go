@@ -0,0 +1,413 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+
+	"github.com/ellescotz/graph_backend/pkg/core"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/websocket"
+)
+
+// Operations of the messages of session clients (see sessionRequest).
+const (
+	opLoad        string = "load"        // replaces the graph with Graph
+	opAddNode     string = "addNode"     // adds Node
+	opRemoveNode  string = "removeNode"  // removes Node and its edges
+	opAddEdge     string = "addEdge"     // adds Edge
+	opRemoveEdge  string = "removeEdge"  // removes Edge
+	opSubscribe   string = "subscribe"   // runs Subscription now and whenever the graph changes
+	opUnsubscribe string = "unsubscribe" // stops the subscription with the ID of Subscription
+)
+
+// maxSubscriptions is the largest number of subscriptions of a session,
+// as every change of the graph runs the analyses of all of them.
+const maxSubscriptions int = 16
+
+// Types of the messages sent to session clients (see sessionResponse).
+const (
+	messageGraph  string = "graph"  // the graph, after every change
+	messageResult string = "result" // the paths found by a subscribed analysis
+	messageError  string = "error"  // a failed operation or analysis
+)
+
+// sessionRequest is a message of a session client.
+type sessionRequest struct {
+	Op           string        `json:"op"`
+	Graph        *core.Graph   `json:"graph,omitempty"`
+	Node         *core.Node    `json:"node,omitempty"`
+	Edge         *core.Edge    `json:"edge,omitempty"`
+	Subscription *subscription `json:"subscription,omitempty"`
+}
+
+// sessionResponse is a message sent to a session client.
+type sessionResponse struct {
+	Type      string         `json:"type"`
+	Revision  int            `json:"revision"`            // number of changes of the graph of the session
+	Op        string         `json:"op,omitempty"`        // operation of the failed request, for messageError only
+	ID        string         `json:"id,omitempty"`        // ID of the subscription, if any
+	Graph     *core.Graph    `json:"graph,omitempty"`     // for messageGraph only
+	Paths     []core.Path    `json:"paths,omitempty"`     // for messageResult only, missing if no path is found
+	Truncated string         `json:"truncated,omitempty"` // reason of partial results, if they are
+	Error     *errorResponse `json:"error,omitempty"`     // for messageError only
+}
+
+// subscription represents an analysis between two nodes which is run whenever the graph of a session changes.
+// Analyses are named as the endpoints running them, and take the same parameters.
+type subscription struct {
+	ID        string  `json:"id"`
+	Analysis  string  `json:"analysis"` // maxSteps, maxWeight, highLowWeight, bellmanFord, kShortest, aStar or shortLong
+	From      string  `json:"from"`
+	To        string  `json:"to"`
+	MaxEdges  int     `json:"maxEdges,omitempty"`  // maxSteps only
+	MaxWeight float64 `json:"maxWeight,omitempty"` // maxWeight only
+	Exact     bool    `json:"exact,omitempty"`     // maxSteps and maxWeight only
+	Lowest    bool    `json:"lowest,omitempty"`    // highLowWeight only
+	K         int     `json:"k,omitempty"`         // kShortest only
+	Heuristic string  `json:"heuristic,omitempty"` // aStar only
+	Shortest  bool    `json:"shortest,omitempty"`  // shortLong only
+}
+
+// sessionAnalyses run the analyses of subscriptions by their names on graph.
+var sessionAnalyses = map[string]func(ctx context.Context, graph *core.Graph, s *subscription, from, to core.Node) ([]core.Path, error){
+	"maxSteps": func(ctx context.Context, graph *core.Graph, s *subscription, from, to core.Node) ([]core.Path, error) {
+		return graph.GeneratePathsWithMaxSteps(ctx, from, to, s.MaxEdges, s.Exact)
+	},
+	"maxWeight": func(ctx context.Context, graph *core.Graph, s *subscription, from, to core.Node) ([]core.Path, error) {
+		return graph.GeneratePathsWithMaxWeight(ctx, from, to, s.MaxWeight, s.Exact)
+	},
+	"highLowWeight": func(ctx context.Context, graph *core.Graph, s *subscription, from, to core.Node) ([]core.Path, error) {
+		return graph.GenerateLowestHighestWeightPath(ctx, from, to, s.Lowest)
+	},
+	"bellmanFord": func(ctx context.Context, graph *core.Graph, s *subscription, from, to core.Node) ([]core.Path, error) {
+		return graph.GenerateLowestWeightPathsBellmanFord(ctx, from, to)
+	},
+	"kShortest": func(ctx context.Context, graph *core.Graph, s *subscription, from, to core.Node) ([]core.Path, error) {
+		return graph.GenerateKShortestPaths(ctx, from, to, s.K)
+	},
+	"aStar": func(ctx context.Context, graph *core.Graph, s *subscription, from, to core.Node) ([]core.Path, error) {
+		return graph.GenerateAStarPath(ctx, from, to, core.Heuristics[s.Heuristic])
+	},
+	"shortLong": func(ctx context.Context, graph *core.Graph, s *subscription, from, to core.Node) ([]core.Path, error) {
+		return graph.GenerateShortestLongestPath(ctx, from, to, s.Shortest)
+	},
+}
+
+// check returns an *core.InvalidParameterError if s cannot be run.
+// Its nodes are checked by run, as they may be added or removed later.
+func (s *subscription) check() error {
+	if len(s.ID) == 0 {
+		return invalidParameter("id", "has to be given")
+	}
+
+	if _, ok := sessionAnalyses[s.Analysis]; !ok {
+		return invalidParameter("analysis", "has to be maxSteps, maxWeight, highLowWeight, bellmanFord, kShortest, aStar or shortLong")
+	}
+
+	if len(s.From) == 0 {
+		return invalidParameter("from", malformedNodesErrorMessage)
+	}
+
+	if len(s.To) == 0 {
+		return invalidParameter("to", malformedNodesErrorMessage)
+	}
+
+	switch {
+	case s.Analysis == "maxSteps" && s.MaxEdges <= 0:
+		return invalidParameter("maxEdges", "has to be a positive integer")
+	case s.Analysis == "kShortest" && s.K <= 0:
+		return invalidParameter("k", "has to be a positive integer")
+	case s.Analysis == "aStar" && core.Heuristics[s.Heuristic] == nil:
+		return invalidParameter("heuristic", "has to be euclidean, manhattan, haversine or none")
+	}
+
+	return nil
+}
+
+// run runs the analysis of s on graph.
+// It returns an *core.UnknownNodeError if the nodes of s are not nodes of graph.
+func (s *subscription) run(ctx context.Context, graph *core.Graph) ([]core.Path, error) {
+	from, to := core.Node{Name: s.From}, core.Node{Name: s.To}
+
+	for i, n := range []core.Node{from, to} {
+		if !graph.HasNode(n) {
+			return nil, &core.UnknownNodeError{Node: n, Field: []string{"from", "to"}[i]}
+		}
+	}
+
+	return sessionAnalyses[s.Analysis](ctx, graph, s, from, to)
+}
+
+// session represents the connection of a client editing a graph with live analyses.
+// Requests are handled one by one, analyses run concurrently on copies of the graph.
+type session struct {
+	conn          *websocket.Conn
+	ctx           context.Context // done once the client goes away
+	graph         core.Graph
+	revision      int
+	subscriptions map[string]*subscription
+	cancels       map[string]context.CancelFunc // cancel the running analysis of each subscription
+	analyses      sync.WaitGroup
+	sending       sync.Mutex // serializes messages
+}
+
+// send sends response to the client, unless ctx is done.
+func (s *session) send(ctx context.Context, response sessionResponse) {
+	s.sending.Lock()
+	defer s.sending.Unlock()
+
+	if ctx.Err() != nil {
+		return
+	}
+
+	// Failing sends are noticed by the next receive, which ends the session
+	_ = websocket.JSON.Send(s.conn, response)
+}
+
+// fail sends the error response of err for the request op.
+func (s *session) fail(op, id string, err error) {
+	_, response := errorStatus(err)
+
+	s.send(s.ctx, sessionResponse{Type: messageError, Revision: s.revision, Op: op, ID: id, Error: &response})
+}
+
+// analyze runs sub on the current graph, cancelling its previous run,
+// and sends the paths found to the client unless it is cancelled again.
+// The analysis is limited by the limits of searches (see limitSearches).
+func (s *session) analyze(sub *subscription) {
+	if cancel, ok := s.cancels[sub.ID]; ok {
+		cancel()
+	}
+
+	ctx, cancel := context.WithCancel(s.ctx)
+	s.cancels[sub.ID] = cancel
+
+	graph, revision := s.graph.Copy(), s.revision
+
+	s.analyses.Add(1)
+
+	go func() {
+		defer s.analyses.Done()
+
+		var (
+			searchCtx    = core.WithWorkers(core.WithBudget(ctx, limits.budget), limits.workers)
+			truncatedErr *core.TruncatedError
+		)
+
+		if limits.timeout > 0 {
+			var cancelSearch context.CancelFunc
+
+			searchCtx, cancelSearch = context.WithTimeout(searchCtx, limits.timeout)
+			defer cancelSearch()
+		}
+
+		response := sessionResponse{Type: messageResult, Revision: revision, ID: sub.ID}
+
+		paths, err := sub.run(searchCtx, &graph)
+		if errors.As(err, &truncatedErr) {
+			response.Truncated, err = truncatedErr.Err.Error(), nil
+		}
+
+		if err != nil {
+			_, errResponse := errorStatus(err)
+			response.Type, response.Op, response.Error = messageError, opSubscribe, &errResponse
+		}
+
+		response.Paths = paths
+
+		s.send(ctx, response)
+	}()
+}
+
+// change applies modify to a copy of the graph. Once it succeeds the copy becomes the graph:
+// the client is sent the graph and the analyses of every subscription are run again.
+func (s *session) change(modify func(graph *core.Graph) error) error {
+	graph := s.graph.Copy()
+
+	if err := modify(&graph); err != nil {
+		return err
+	}
+
+	if err := graph.CheckSize(maxGraphNodes, maxGraphEdges); err != nil {
+		return err
+	}
+
+	s.graph = graph
+	s.revision++
+
+	s.send(s.ctx, sessionResponse{Type: messageGraph, Revision: s.revision, Graph: &s.graph})
+
+	for _, sub := range s.subscriptions {
+		s.analyze(sub)
+	}
+
+	return nil
+}
+
+// handle handles a request of the client.
+// It returns an error if the request is malformed or fails.
+func (s *session) handle(request sessionRequest) error {
+	switch request.Op {
+	case opLoad:
+		if request.Graph == nil {
+			return invalidParameter("graph", "has to be given")
+		}
+
+		if err := request.Graph.Validate(defaultValidation); err != nil {
+			return err
+		}
+
+		return s.change(func(graph *core.Graph) error {
+			*graph = *request.Graph
+			return nil
+		})
+	case opAddNode, opRemoveNode:
+		if request.Node == nil || len(request.Node.Name) == 0 {
+			return invalidParameter("node", "malformed node")
+		}
+
+		return s.change(func(graph *core.Graph) error {
+			if request.Op == opAddNode {
+				return graph.AddNode(*request.Node)
+			}
+
+			return graph.RemoveNode(*request.Node)
+		})
+	case opAddEdge, opRemoveEdge:
+		if request.Edge == nil {
+			return invalidParameter("edge", "malformed edge")
+		}
+
+		return s.change(func(graph *core.Graph) error {
+			if request.Op == opAddEdge {
+				return graph.AddEdge(*request.Edge)
+			}
+
+			return graph.RemoveEdge(*request.Edge)
+		})
+	case opSubscribe:
+		if request.Subscription == nil {
+			return invalidParameter("subscription", "has to be given")
+		}
+
+		if err := request.Subscription.check(); err != nil {
+			return err
+		}
+
+		// Subscriptions can be replaced at any time
+		if _, ok := s.subscriptions[request.Subscription.ID]; !ok && len(s.subscriptions) == maxSubscriptions {
+			return invalidParameter("subscription", fmt.Sprintf("at most %v subscriptions are allowed", maxSubscriptions))
+		}
+
+		s.subscriptions[request.Subscription.ID] = request.Subscription
+		s.analyze(request.Subscription)
+
+		return nil
+	case opUnsubscribe:
+		if request.Subscription == nil {
+			return invalidParameter("subscription", "has to be given")
+		}
+
+		if cancel, ok := s.cancels[request.Subscription.ID]; ok {
+			cancel()
+		}
+
+		delete(s.subscriptions, request.Subscription.ID)
+		delete(s.cancels, request.Subscription.ID)
+
+		return nil
+	default:
+		return invalidParameter("op", fmt.Sprintf("unknown operation %q", request.Op))
+	}
+}
+
+// serve handles the requests of the client until it goes away.
+func (s *session) serve() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.ctx = ctx
+
+	defer s.analyses.Wait()
+	defer cancel()
+
+	s.send(s.ctx, sessionResponse{Type: messageGraph, Revision: s.revision, Graph: &s.graph})
+
+	for {
+		var (
+			request   sessionRequest
+			syntaxErr *json.SyntaxError
+			typeErr   *json.UnmarshalTypeError
+		)
+
+		err := websocket.JSON.Receive(s.conn, &request)
+
+		switch {
+		case errors.As(err, &syntaxErr), errors.As(err, &typeErr), errors.Is(err, websocket.ErrFrameTooLarge):
+			s.fail("", "", invalidParameter("message", "malformed message"))
+			continue
+		case err != nil:
+			return
+		}
+
+		if err = s.handle(request); err != nil {
+			var id string
+
+			if request.Subscription != nil {
+				id = request.Subscription.ID
+			}
+
+			s.fail(request.Op, id, err)
+		}
+	}
+}
+
+// checkOrigin accepts WebSocket connections of clients which are not browsers, sending no Origin header,
+// and of browsers on the origin allowed by cors.
+func checkOrigin(config *websocket.Config, req *http.Request) error {
+	origin := req.Header.Get("Origin")
+	if len(origin) == 0 || origin == frontendOrigin {
+		config.Origin, _ = url.Parse(origin)
+		return nil
+	}
+
+	return fmt.Errorf("origin %q is not allowed", origin)
+}
+
+// openSession opens a WebSocket session editing a graph with live analyses.
+// The session starts with an empty graph, or with the graph stored with the "GraphID": "<id>" request parameter,
+// optionally as it was at the "Revision": "<a positive integer>" request parameter.
+// Clients send sessionRequest messages changing the graph and subscribing to analyses.
+// They are sent the graph after every change, and the paths found by each subscribed analysis on it.
+// In case of unknown graph it gives an error response instead of opening the session.
+func openSession(c *gin.Context) {
+	var (
+		graph core.Graph
+		err   error
+	)
+
+	// Loading the graph from the repository
+	if id := c.Query("GraphID"); len(id) != 0 {
+		if graph, err = storedGraph(c, id, "Revision"); err != nil {
+			respondError(c, err)
+			return
+		}
+	}
+
+	websocket.Server{
+		Handshake: checkOrigin,
+		Handler: func(conn *websocket.Conn) {
+			s := &session{
+				conn:          conn,
+				graph:         graph,
+				subscriptions: make(map[string]*subscription),
+				cancels:       make(map[string]context.CancelFunc),
+			}
+
+			s.serve()
+		},
+	}.ServeHTTP(c.Writer, c.Request)
+}
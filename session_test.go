@@ -0,0 +1,180 @@
+package main
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ellescotz/graph_backend/pkg/core"
+	"golang.org/x/net/websocket"
+)
+
+// dialSession opens a session on server and receives its first graph message.
+func dialSession(t *testing.T, server *httptest.Server) *websocket.Conn {
+	t.Helper()
+
+	conn, err := websocket.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/session", "", frontendOrigin)
+	if err != nil {
+		t.Fatalf("openSession did not work. Got error %v", err)
+	}
+
+	conn.SetDeadline(time.Now().Add(10 * time.Second))
+
+	if response := receive(t, conn); response.Type != messageGraph || response.Revision != 0 {
+		t.Fatalf("openSession did not work. Got %+v instead of the empty graph", response)
+	}
+
+	return conn
+}
+
+// send sends request on conn.
+func send(t *testing.T, conn *websocket.Conn, request sessionRequest) {
+	t.Helper()
+
+	if err := websocket.JSON.Send(conn, request); err != nil {
+		t.Fatalf("Sending %+v did not work. Got error %v", request, err)
+	}
+}
+
+// receive receives a message on conn.
+func receive(t *testing.T, conn *websocket.Conn) sessionResponse {
+	t.Helper()
+
+	var response sessionResponse
+
+	if err := websocket.JSON.Receive(conn, &response); err != nil {
+		t.Fatalf("Receiving did not work. Got error %v", err)
+	}
+
+	return response
+}
+
+func TestSession(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(newRouter())
+	defer server.Close()
+
+	conn := dialSession(t, server)
+	defer conn.Close()
+
+	nodeA := core.Node{Name: "A"}
+	nodeB := core.Node{Name: "B"}
+	nodeC := core.Node{Name: "C"}
+
+	graph := core.Graph{
+		Nodes: []core.Node{nodeA, nodeB, nodeC},
+		Edges: []core.Edge{
+			{Nodes: [2]core.Node{nodeA, nodeB}, Weight: 1},
+			{Nodes: [2]core.Node{nodeB, nodeC}, Weight: 1},
+		},
+	}
+
+	// Case 1: loading a graph sends it back
+	send(t, conn, sessionRequest{Op: opLoad, Graph: &graph})
+
+	if response := receive(t, conn); response.Type != messageGraph || response.Revision != 1 || len(response.Graph.Edges) != 2 {
+		t.Errorf("load did not work. Got %+v instead of the graph at revision 1", response)
+	}
+
+	// Case 2: subscribing runs the analysis at once
+	sub := &subscription{ID: "steps", Analysis: "maxSteps", From: "A", To: "C", MaxEdges: 3}
+
+	send(t, conn, sessionRequest{Op: opSubscribe, Subscription: sub})
+
+	if response := receive(t, conn); response.Type != messageResult || response.ID != "steps" || len(response.Paths) != 1 {
+		t.Errorf("subscribe did not work. Got %+v instead of 1 path", response)
+	}
+
+	// Case 3: edits send the graph, then the results on it
+	send(t, conn, sessionRequest{Op: opAddEdge, Edge: &core.Edge{Nodes: [2]core.Node{nodeA, nodeC}, Weight: 5}})
+
+	if response := receive(t, conn); response.Type != messageGraph || response.Revision != 2 {
+		t.Errorf("addEdge did not work. Got %+v instead of the graph at revision 2", response)
+	}
+
+	if response := receive(t, conn); response.Type != messageResult || response.Revision != 2 || len(response.Paths) != 2 {
+		t.Errorf("addEdge did not work. Got %+v instead of 2 paths at revision 2", response)
+	}
+
+	// Case 4: failed edits are reported and leave the graph as it is
+	send(t, conn, sessionRequest{Op: opAddNode, Node: &nodeA})
+
+	if response := receive(t, conn); response.Type != messageError || response.Op != opAddNode || response.Error.Code != codeConflict {
+		t.Errorf("addNode did not work. Got %+v instead of a %v error", response, codeConflict)
+	}
+
+	// Case 5: unsubscribed analyses are not run anymore, the next message answers the malformed request
+	send(t, conn, sessionRequest{Op: opUnsubscribe, Subscription: &subscription{ID: "steps"}})
+	send(t, conn, sessionRequest{Op: opRemoveNode, Node: &nodeB})
+
+	if response := receive(t, conn); response.Type != messageGraph || response.Revision != 3 || len(response.Graph.Edges) != 1 {
+		t.Errorf("removeNode did not work. Got %+v instead of the graph at revision 3", response)
+	}
+
+	if _, err := conn.Write([]byte("{")); err != nil {
+		t.Fatalf("Sending did not work. Got error %v", err)
+	}
+
+	if response := receive(t, conn); response.Type != messageError || response.Error.Code != codeInvalidParameter {
+		t.Errorf("Session did not work. Got %+v instead of a %v error", response, codeInvalidParameter)
+	}
+
+	// Case 6: unknown nodes of subscriptions are reported with their analysis
+	send(t, conn, sessionRequest{Op: opSubscribe, Subscription: &subscription{ID: "low", Analysis: "highLowWeight", From: "A", To: "X"}})
+
+	if response := receive(t, conn); response.Type != messageError || response.ID != "low" || response.Error.Code != codeUnknownNode {
+		t.Errorf("subscribe did not work. Got %+v instead of a %v error", response, codeUnknownNode)
+	}
+}
+
+func TestSessionSubscriptionLimit(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(newRouter())
+	defer server.Close()
+
+	conn := dialSession(t, server)
+	defer conn.Close()
+
+	for i := 0; i <= maxSubscriptions; i++ {
+		send(t, conn, sessionRequest{Op: opSubscribe, Subscription: &subscription{
+			ID:       fmt.Sprint(i),
+			Analysis: "highLowWeight",
+			From:     "A",
+			To:       "B",
+		}})
+	}
+
+	// Every analysis fails on the empty graph, the subscription beyond the limit is rejected
+	var rejected int
+
+	for i := 0; i <= maxSubscriptions; i++ {
+		response := receive(t, conn)
+
+		if response.Type != messageError {
+			t.Fatalf("subscribe did not work. Got %+v instead of an error", response)
+		}
+
+		if response.Error.Code == codeInvalidParameter && response.Error.Field == "subscription" {
+			rejected++
+
+			if response.ID != fmt.Sprint(maxSubscriptions) {
+				t.Errorf("subscribe did not work. Got subscription %v rejected instead of %v", response.ID, maxSubscriptions)
+			}
+		}
+	}
+
+	if rejected != 1 {
+		t.Errorf("subscribe did not work. Got %v subscriptions rejected instead of 1", rejected)
+	}
+
+	// Replacing a subscription is allowed
+	send(t, conn, sessionRequest{Op: opSubscribe, Subscription: &subscription{ID: "0", Analysis: "maxSteps", From: "A", To: "B", MaxEdges: 1}})
+
+	if response := receive(t, conn); response.Type != messageError || response.ID != "0" || response.Error.Code != codeUnknownNode {
+		t.Errorf("subscribe did not work. Got %+v instead of a %v error", response, codeUnknownNode)
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/ellescotz/graph_backend/pkg/core"
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+)
+
+// ndjsonMediaType is the media type of paths streamed as newline-delimited JSON, one path per line.
+const ndjsonMediaType string = "application/x-ndjson"
+
+// Names of the Server-Sent Events of streamed paths.
+const (
+	pathEvent      string = "path"      // data: a path as JSON
+	truncatedEvent string = "truncated" // data: the reason of truncation, the stream ends after it
+	endEvent       string = "end"       // the stream ends with every path
+)
+
+// streamFormat returns the media type of streamed paths preferred by the Accept request header:
+// ndjsonMediaType or sse.ContentType. It returns false if the client does not ask for streaming.
+func streamFormat(c *gin.Context) (string, bool) {
+	offers := make([]string, 0, len(responseMediaTypes)+2)
+	offers = append(offers, responseMediaTypes...)
+	offers = append(offers, ndjsonMediaType, sse.ContentType)
+
+	format := c.NegotiateFormat(offers...)
+
+	return format, format == ndjsonMediaType || format == sse.ContentType
+}
+
+// streamPaths responds with the paths given by stream to its core.PathFunc as soon as they are found, as mediaType.
+// Newline-delimited JSON streams end with the Truncated trailer if the search is truncated,
+// Server-Sent Events streams end with a truncatedEvent or an endEvent.
+// The stream is cut if writing a path fails, for example because the client went away.
+func streamPaths(c *gin.Context, mediaType string, stream func(emit core.PathFunc) error) {
+	var (
+		header       = c.Writer.Header()
+		encoder      = json.NewEncoder(c.Writer)
+		truncatedErr *core.TruncatedError
+	)
+
+	header.Set("Content-Type", mediaType)
+	header.Set("Cache-Control", "no-cache")
+
+	if mediaType == ndjsonMediaType {
+		header.Set("Trailer", truncatedHeader)
+	}
+
+	c.Status(200)
+	c.Writer.Flush()
+
+	err := stream(func(path core.Path) error {
+		var err error
+
+		if mediaType == sse.ContentType {
+			err = sse.Encode(c.Writer, sse.Event{Event: pathEvent, Data: path})
+		} else {
+			err = encoder.Encode(path)
+		}
+
+		c.Writer.Flush()
+
+		return err
+	})
+
+	switch {
+	case errors.As(err, &truncatedErr) && mediaType == sse.ContentType:
+		err = sse.Encode(c.Writer, sse.Event{Event: truncatedEvent, Data: truncatedErr.Err.Error()})
+	case errors.As(err, &truncatedErr):
+		header.Set(truncatedHeader, truncatedErr.Err.Error())
+		err = nil
+	case err == nil && mediaType == sse.ContentType:
+		err = sse.Encode(c.Writer, sse.Event{Event: endEvent, Data: ""})
+	}
+
+	if err != nil {
+		_ = c.Error(err)
+	}
+}
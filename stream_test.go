@@ -0,0 +1,162 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ellescotz/graph_backend/pkg/core"
+	"github.com/gin-contrib/sse"
+)
+
+// requestStream requests the paths of graph with at most maxEdges edges from node1 to node2 as mediaType.
+func requestStream(t *testing.T, graph core.Graph, maxEdges int, mediaType string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	body, err := json.Marshal(graph)
+	if err != nil {
+		t.Fatalf("Encoding the graph did not work. Got error %v", err)
+	}
+
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest("POST", fmt.Sprintf("/maxSteps?From=node1&To=node2&MaxEdges=%v%%3B&Exact=false", maxEdges), bytes.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", mediaType)
+
+	newRouter().ServeHTTP(recorder, request)
+
+	if recorder.Code != 200 || recorder.Header().Get("Content-Type") != mediaType {
+		t.Fatalf("Streaming did not work. Got status %v and content type %q instead of 200 and %q", recorder.Code, recorder.Header().Get("Content-Type"), mediaType)
+	}
+
+	if !recorder.Flushed {
+		t.Errorf("Streaming did not work. Got no flush")
+	}
+
+	return recorder
+}
+
+// streamGraph returns the complete graph on n nodes, node1 to noden.
+func streamGraph(n int) core.Graph {
+	var graph core.Graph
+
+	for i := 1; i <= n; i++ {
+		graph.Nodes = append(graph.Nodes, core.Node{Name: fmt.Sprint("node", i)})
+	}
+
+	for _, from := range graph.Nodes {
+		for _, to := range graph.Nodes {
+			if from != to {
+				graph.Edges = append(graph.Edges, core.Edge{Nodes: [2]core.Node{from, to}, Weight: 1})
+			}
+		}
+	}
+
+	return graph
+}
+
+func TestStreamNDJSON(t *testing.T) {
+	t.Parallel()
+
+	// Case 1: every path is on its own line, without trailer
+	recorder := requestStream(t, streamGraph(3), 2, ndjsonMediaType)
+
+	body := recorder.Body.String()
+	lines := strings.Split(strings.TrimSuffix(body, "\n"), "\n")
+
+	if !strings.HasSuffix(body, "\n") || len(lines) != 2 {
+		t.Errorf("Streaming did not work. Got %q instead of 2 lines", body)
+	}
+
+	for _, line := range lines {
+		var path core.Path
+
+		if err := json.Unmarshal([]byte(line), &path); err != nil || len(path.Subgraph.Edges) == 0 {
+			t.Errorf("Streaming did not work. Got line %q and error %v instead of a path", line, err)
+		}
+	}
+
+	if trailer := recorder.Result().Trailer.Get(truncatedHeader); len(trailer) != 0 {
+		t.Errorf("Streaming did not work. Got trailer %q instead of none", trailer)
+	}
+
+	// Case 2: truncated searches end with the Truncated trailer after the paths found until then
+	recorder = requestStream(t, streamGraph(10), 9, ndjsonMediaType)
+
+	if lines := strings.Count(recorder.Body.String(), "\n"); lines != limits.budget.MaxPaths {
+		t.Errorf("Streaming did not work. Got %v lines instead of %v", lines, limits.budget.MaxPaths)
+	}
+
+	if trailer := recorder.Result().Trailer.Get(truncatedHeader); trailer != core.ErrPathBudgetExhausted.Error() {
+		t.Errorf("Streaming did not work. Got trailer %q instead of %q", trailer, core.ErrPathBudgetExhausted)
+	}
+}
+
+// streamEvent is a Server-Sent Event of a stream.
+type streamEvent struct {
+	event, data string
+}
+
+// readEvents returns the events of a Server-Sent Events stream.
+func readEvents(t *testing.T, body string) []streamEvent {
+	t.Helper()
+
+	var events []streamEvent
+
+	if !strings.HasSuffix(body, "\n\n") {
+		t.Fatalf("Streaming did not work. Got %q instead of events ending with an empty line", body)
+	}
+
+	for _, block := range strings.Split(strings.TrimSuffix(body, "\n\n"), "\n\n") {
+		var event streamEvent
+
+		for _, line := range strings.Split(block, "\n") {
+			switch {
+			case strings.HasPrefix(line, "event:"):
+				event.event = strings.TrimPrefix(line, "event:")
+			case strings.HasPrefix(line, "data:"):
+				event.data = strings.TrimPrefix(line, "data:")
+			default:
+				t.Fatalf("Streaming did not work. Got line %q instead of an event or its data", line)
+			}
+		}
+
+		events = append(events, event)
+	}
+
+	return events
+}
+
+func TestStreamSSE(t *testing.T) {
+	t.Parallel()
+
+	// Case 1: every path is a path event, the stream ends with an end event
+	events := readEvents(t, requestStream(t, streamGraph(3), 2, sse.ContentType).Body.String())
+
+	if len(events) != 3 || events[2].event != endEvent {
+		t.Fatalf("Streaming did not work. Got %v instead of 2 paths and the end", events)
+	}
+
+	for _, event := range events[:2] {
+		var path core.Path
+
+		if err := json.Unmarshal([]byte(event.data), &path); event.event != pathEvent || err != nil || len(path.Subgraph.Edges) == 0 {
+			t.Errorf("Streaming did not work. Got %v and error %v instead of a path event", event, err)
+		}
+	}
+
+	// Case 2: truncated searches end with a truncated event after the paths found until then
+	events = readEvents(t, requestStream(t, streamGraph(10), 9, sse.ContentType).Body.String())
+	last := events[len(events)-1]
+
+	if len(events) != limits.budget.MaxPaths+1 || events[0].event != pathEvent {
+		t.Errorf("Streaming did not work. Got %v events instead of %v paths and the truncation", len(events), limits.budget.MaxPaths)
+	}
+
+	if last.event != truncatedEvent || last.data != core.ErrPathBudgetExhausted.Error() {
+		t.Errorf("Streaming did not work. Got %v instead of the %v event", last, truncatedEvent)
+	}
+}